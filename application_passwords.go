@@ -0,0 +1,92 @@
+package wordpress
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ApplicationPassword is a password a user gives to an application to authenticate with basic auth (WordPress 5.6+)
+type ApplicationPassword struct {
+	UUID     string `json:"uuid,omitempty"`
+	AppID    string `json:"app_id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Created  string `json:"created,omitempty"`
+	LastUsed string `json:"last_used,omitempty"`
+	LastIP   string `json:"last_ip,omitempty"`
+
+	// Password is only returned once, when the application password is created
+	Password string `json:"password,omitempty"`
+}
+
+// ApplicationPasswordDeletedResponse is the response to deleting an application password
+type ApplicationPasswordDeletedResponse struct {
+	Deleted  bool                `json:"deleted,omitempty"`
+	Previous ApplicationPassword `json:"previous,omitempty"`
+}
+
+// ApplicationPasswordsCollection manages the application passwords of a user
+type ApplicationPasswordsCollection struct {
+	client     *Client
+	url        string
+	parent     interface{}
+	parentType string
+}
+
+func (col *ApplicationPasswordsCollection) List(params interface{}) ([]ApplicationPassword, *http.Response, []byte, error) {
+	var passwords []ApplicationPassword
+	resp, body, err := col.client.List(col.url, params, &passwords)
+	return passwords, resp, body, err
+}
+
+// Create creates an application password, given its name and optionally the UUID of the application (AppID).
+// The password is only returned by this call.
+func (col *ApplicationPasswordsCollection) Create(new *ApplicationPassword) (*ApplicationPassword, *http.Response, []byte, error) {
+	var created ApplicationPassword
+	content := map[string]interface{}{"name": new.Name}
+	if new.AppID != "" {
+		content["app_id"] = new.AppID
+	}
+	resp, body, err := col.client.Create(col.url, content, &created)
+	return &created, resp, body, err
+}
+
+func (col *ApplicationPasswordsCollection) Get(uuid string, params interface{}) (*ApplicationPassword, *http.Response, []byte, error) {
+	var password ApplicationPassword
+	entityURL := fmt.Sprintf("%v/%v", col.url, uuid)
+	resp, body, err := col.client.Get(entityURL, params, &password)
+	return &password, resp, body, err
+}
+
+// Introspect returns the application password the client authenticates with; it fails if the client
+// authenticates otherwise, or the collection is not the current user's
+func (col *ApplicationPasswordsCollection) Introspect(params interface{}) (*ApplicationPassword, *http.Response, []byte, error) {
+	var password ApplicationPassword
+	entityURL := fmt.Sprintf("%v/introspect", col.url)
+	resp, body, err := col.client.Get(entityURL, params, &password)
+	return &password, resp, body, err
+}
+
+// Revoke deletes an application password, so that it cannot authenticate anymore
+func (col *ApplicationPasswordsCollection) Revoke(uuid string) (*ApplicationPasswordDeletedResponse, *http.Response, []byte, error) {
+	var response ApplicationPasswordDeletedResponse
+	entityURL := fmt.Sprintf("%v/%v", col.url, uuid)
+	resp, body, err := col.client.Delete(entityURL, nil, &response)
+	return &response, resp, body, err
+}
+
+// Rotate replaces an application password with a new one with the same name and application, and revokes it.
+// If revoking the old password fails, the new one is returned along with the error, so that it is not lost.
+func (col *ApplicationPasswordsCollection) Rotate(uuid string) (*ApplicationPassword, error) {
+	old, _, _, err := col.Get(uuid, nil)
+	if err != nil {
+		return nil, err
+	}
+	created, _, _, err := col.Create(&ApplicationPassword{Name: old.Name, AppID: old.AppID})
+	if err != nil {
+		return nil, err
+	}
+	if _, _, _, err := col.Revoke(uuid); err != nil {
+		return created, fmt.Errorf("created application password %v, but failed to revoke %v: %v", created.UUID, uuid, err)
+	}
+	return created, nil
+}
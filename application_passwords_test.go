@@ -0,0 +1,103 @@
+package wordpress_test
+
+import (
+	"github.com/sogko/go-wordpress"
+	"net/http"
+	"testing"
+)
+
+func newApplicationPasswordClient(username string, password string) *wordpress.Client {
+	return wordpress.NewClient(&wordpress.Options{
+		BaseAPIURL: API_BASE_URL,
+		Username:   username,
+		Password:   password,
+		Transport:  TRANSPORT,
+	})
+}
+
+func TestUsersApplicationPasswords_InvalidCall(t *testing.T) {
+	user := wordpress.User{}
+	if user.ApplicationPasswords() != nil {
+		t.Errorf("Expected no application passwords collection without a parent collection")
+	}
+}
+
+func TestUsersApplicationPasswords(t *testing.T) {
+	wp := initTestClient()
+
+	me, _, _, err := wp.Users().Me(nil)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	col := me.ApplicationPasswords()
+	created, resp, _, err := col.Create(&wordpress.ApplicationPassword{Name: "Deploy", AppID: "9b9b6a7c-2d1e-4b43-8f7a-63c5a9a0f6b2"})
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("Expected 201 Created, got %v", resp.Status)
+	}
+	if created.UUID == "" || created.Password == "" || created.Name != "Deploy" {
+		t.Fatalf("Unexpected application password: %v", created)
+	}
+
+	// the password is only returned once
+	passwords, _, _, err := wp.Users().Entity(me.ID).ApplicationPasswords().List(nil)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	var listed *wordpress.ApplicationPassword
+	for i := range passwords {
+		if passwords[i].UUID == created.UUID {
+			listed = &passwords[i]
+		}
+	}
+	if listed == nil || listed.Password != "" || listed.LastUsed != "" {
+		t.Errorf("Expected the application password without its password, got %v", passwords)
+	}
+
+	// introspecting requires authenticating with an application password
+	if _, _, _, err := col.Introspect(nil); err == nil {
+		t.Errorf("Expected an error introspecting without an application password")
+	}
+	app := newApplicationPasswordClient(USER, created.Password)
+	appMe, _, _, err := app.Users().Me(nil)
+	if err != nil {
+		t.Fatalf("Should authenticate with the application password: %v", err.Error())
+	}
+	current, _, _, err := appMe.ApplicationPasswords().Introspect(nil)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if current.UUID != created.UUID || current.LastUsed == "" || current.LastIP == "" {
+		t.Errorf("Expected the used application password, got %v", current)
+	}
+
+	rotated, err := col.Rotate(created.UUID)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if rotated.UUID == created.UUID || rotated.Name != created.Name || rotated.AppID != created.AppID {
+		t.Errorf("Expected a new application password for the same application, got %v", rotated)
+	}
+	if _, _, _, err := app.Users().Me(nil); err == nil {
+		t.Errorf("Expected the rotated password to be revoked")
+	}
+	if _, _, _, err := newApplicationPasswordClient(USER, rotated.Password).Users().Me(nil); err != nil {
+		t.Errorf("Should authenticate with the new password: %v", err.Error())
+	}
+
+	deleted, _, _, err := col.Revoke(rotated.UUID)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if !deleted.Deleted || deleted.Previous.UUID != rotated.UUID {
+		t.Errorf("Expected the application password to be deleted, got %v", deleted)
+	}
+	if _, _, _, err := col.Get(rotated.UUID, nil); err == nil {
+		t.Errorf("Expected an error getting a revoked application password")
+	}
+	if _, err := col.Rotate(rotated.UUID); err == nil {
+		t.Errorf("Expected an error rotating a revoked application password")
+	}
+}
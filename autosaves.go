@@ -0,0 +1,99 @@
+package wordpress
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+)
+
+// Autosave is the unsaved state of a post or page, saved by a user while editing it.
+// Autosaves are revisions, one per user; for a draft edited by its author, the draft itself is autosaved.
+type Autosave struct {
+	ID          int     `json:"id,omitempty"`
+	Author      int     `json:"author,omitempty"`
+	Date        string  `json:"date,omitempty"`
+	DateGMT     string  `json:"date_gmt,omitempty"`
+	GUID        GUID    `json:"guid,omitempty"`
+	Modified    string  `json:"modified,omitempty"`
+	ModifiedGMT string  `json:"modified_gmt,omitempty"`
+	Parent      int     `json:"parent,omitempty"`
+	Slug        string  `json:"slug,omitempty"`
+	Title       Title   `json:"title,omitempty"`
+	Content     Content `json:"content,omitempty"`
+	Excerpt     Excerpt `json:"excerpt,omitempty"`
+	PreviewLink string  `json:"preview_link,omitempty"`
+}
+
+// Preview is a link to preview the unsaved changes of a post or page.
+// Nonce (the `preview_nonce` query parameter) is only set for autosaves of other users than the author of a
+// draft, or of published posts; it is tied to the user that created the autosave and expires within a day.
+type Preview struct {
+	URL   string
+	ID    int
+	Nonce string
+}
+
+// Preview returns the preview link of an autosave, as returned by the API on WordPress 5.0+
+func (autosave *Autosave) Preview() (*Preview, error) {
+	if autosave.PreviewLink == "" {
+		return nil, errors.New("autosave has no preview link")
+	}
+	u, err := neturl.Parse(autosave.PreviewLink)
+	if err != nil {
+		return nil, err
+	}
+	query := u.Query()
+	preview := &Preview{URL: autosave.PreviewLink, Nonce: query.Get("preview_nonce")}
+	for _, param := range []string{"preview_id", "p", "page_id"} {
+		if id, err := strconv.Atoi(query.Get(param)); err == nil {
+			preview.ID = id
+			break
+		}
+	}
+	return preview, nil
+}
+
+type AutosavesCollection struct {
+	client     *Client
+	url        string
+	parent     interface{}
+	parentType string
+}
+
+func (col *AutosavesCollection) List(params interface{}) ([]Autosave, *http.Response, []byte, error) {
+	var autosaves []Autosave
+	resp, body, err := col.client.List(col.url, params, &autosaves)
+	return autosaves, resp, body, err
+}
+
+func (col *AutosavesCollection) Get(id int, params interface{}) (*Autosave, *http.Response, []byte, error) {
+	var autosave Autosave
+	entityURL := fmt.Sprintf("%v/%v", col.url, id)
+	resp, body, err := col.client.Get(entityURL, params, &autosave)
+	return &autosave, resp, body, err
+}
+
+// Create autosaves the raw title, content and excerpt of new for the current user; empty fields are left unchanged
+func (col *AutosavesCollection) Create(new *Autosave) (*Autosave, *http.Response, []byte, error) {
+	fields := map[string]interface{}{}
+	for field, value := range map[string]string{"title": new.Title.Raw, "content": new.Content.Raw, "excerpt": new.Excerpt.Raw} {
+		if value != "" {
+			fields[field] = value
+		}
+	}
+	var created Autosave
+	resp, body, err := col.client.Create(col.url, fields, &created)
+	return &created, resp, body, err
+}
+
+// Preview autosaves changes and returns a link to preview them, for eg. to show unsaved changes to a reviewer
+func (col *AutosavesCollection) Preview(changes *Autosave) (*Preview, *http.Response, []byte, error) {
+	autosave, resp, body, err := col.Create(changes)
+	if err != nil {
+		return nil, resp, body, err
+	}
+	preview, err := autosave.Preview()
+	return preview, resp, body, err
+}
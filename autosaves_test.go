@@ -0,0 +1,154 @@
+package wordpress_test
+
+import (
+	"github.com/sogko/go-wordpress"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func getPreview(t *testing.T, url string, auth bool) (*http.Response, string) {
+	req, _ := http.NewRequest("GET", url, nil)
+	if auth {
+		req.SetBasicAuth(USER, PASSWORD)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+func TestPostsAutosaves_InvalidCall(t *testing.T) {
+	invalidPost := wordpress.Post{}
+	if autosaves := invalidPost.Autosaves(); autosaves != nil {
+		t.Errorf("Expected autosaves to be nil, %v", autosaves)
+	}
+	invalidPage := wordpress.Page{}
+	if autosaves := invalidPage.Autosaves(); autosaves != nil {
+		t.Errorf("Expected autosaves to be nil, %v", autosaves)
+	}
+}
+
+func TestPostsAutosaves(t *testing.T) {
+	wp := initTestClient()
+
+	post, resp, _, _ := wp.Posts().Create(&wordpress.Post{
+		Title:   wordpress.Title{Raw: "Published"},
+		Content: wordpress.Content{Raw: "Saved content"},
+		Status:  wordpress.PostStatusPublish,
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201 Created, got %v", resp.Status)
+	}
+	defer cleanUpPost(t, post.ID)
+
+	autosave, resp, body, err := post.Autosaves().Create(&wordpress.Autosave{
+		Content: wordpress.Content{Raw: "Unsaved content"},
+	})
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if body == nil {
+		t.Errorf("Should not return nil body")
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 OK, got %v", resp.Status)
+	}
+	if autosave.Parent != post.ID || autosave.Content.Raw != "Unsaved content" || autosave.Title.Raw != "Published" {
+		t.Errorf("Expected an autosave of the post with the changes, got %v", autosave)
+	}
+
+	// autosaving again replaces the autosave of the user
+	again, _, _, _ := post.Autosaves().Create(&wordpress.Autosave{
+		Content: wordpress.Content{Raw: "Unsaved content, edited"},
+	})
+	if again.ID != autosave.ID {
+		t.Errorf("Expected the autosave %v to be replaced, got %v", autosave.ID, again.ID)
+	}
+	autosaves, _, _, err := post.Autosaves().List(nil)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if len(autosaves) != 1 || autosaves[0].ID != autosave.ID {
+		t.Errorf("Expected one autosave, got %v", autosaves)
+	}
+	got, _, _, err := wp.Posts().Entity(post.ID).Autosaves().Get(autosave.ID, nil)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if got.Content.Raw != "Unsaved content, edited" {
+		t.Errorf("Expected the latest changes, got %v", got.Content.Raw)
+	}
+	live, _, _, _ := wp.Posts().Get(post.ID, "context=edit")
+	if live.Content.Raw != "Saved content" {
+		t.Errorf("Expected the post not to change, got %v", live.Content.Raw)
+	}
+
+	// the preview shows the autosave to anyone with the link, but not with a forged nonce
+	preview, _, _, err := post.Autosaves().Preview(&wordpress.Autosave{
+		Content: wordpress.Content{Raw: "Previewed content"},
+	})
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if preview.ID != post.ID || preview.Nonce == "" {
+		t.Errorf("Expected a preview of %v with a nonce, got %v", post.ID, preview)
+	}
+	resp, html := getPreview(t, preview.URL, false)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(html, "Previewed content") {
+		t.Errorf("Expected the preview to show the autosave, got %v: %v", resp.Status, html)
+	}
+	resp, _ = getPreview(t, strings.Replace(preview.URL, preview.Nonce, "forged", 1), false)
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected 403 Forbidden, got %v", resp.Status)
+	}
+
+	if _, resp, _, err := post.Autosaves().Create(&wordpress.Autosave{}); err == nil {
+		t.Errorf("Expected an error autosaving no changes, got %v", resp.Status)
+	}
+}
+
+func TestPagesAutosaves_Draft(t *testing.T) {
+	wp := initTestClient()
+
+	page, resp, _, _ := wp.Pages().Create(&wordpress.Page{
+		Title:  wordpress.Title{Raw: "Draft"},
+		Status: wordpress.PostStatusDraft,
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201 Created, got %v", resp.Status)
+	}
+	defer cleanUpPage(t, page.ID)
+
+	// the draft of the current user is autosaved in place, and its preview has no nonce
+	preview, _, _, err := page.Autosaves().Preview(&wordpress.Autosave{
+		Content: wordpress.Content{Raw: "Draft content"},
+	})
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if preview.ID != page.ID || preview.Nonce != "" {
+		t.Errorf("Expected a preview of %v without a nonce, got %v", page.ID, preview)
+	}
+	draft, _, _, _ := wp.Pages().Get(page.ID, "context=edit")
+	if draft.Content.Raw != "Draft content" {
+		t.Errorf("Expected the draft to be updated, got %v", draft.Content.Raw)
+	}
+	autosaves, _, _, _ := page.Autosaves().List(nil)
+	if len(autosaves) != 0 {
+		t.Errorf("Expected no autosave, got %v", autosaves)
+	}
+
+	resp, _ = getPreview(t, preview.URL, false)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 Not Found without authentication, got %v", resp.Status)
+	}
+	resp, html := getPreview(t, preview.URL, true)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(html, "Draft content") {
+		t.Errorf("Expected the preview to show the draft, got %v: %v", resp.Status, html)
+	}
+}
@@ -0,0 +1,422 @@
+package wordpress
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheStore stores the responses cached by a client with Options.Cache set
+type CacheStore interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte)
+	Delete(key string)
+	Keys() []string
+}
+
+// XFromCache is set on responses served from the cache, with or without revalidation
+const XFromCache = "X-From-Cache"
+
+// cachedResponse is the value stored for a cached GET request
+type cachedResponse struct {
+	URL        string      `json:"url"`
+	Status     string      `json:"status"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	Stored     time.Time   `json:"stored"`
+}
+
+// cacheTransport is an http.RoundTripper caching the JSON responses of GET requests to the API (under baseURL);
+// other requests, like the downloads of media files, are passed through and their responses streamed as they are.
+// Fresh responses (Cache-Control max-age, Expires) are served without a request;
+// stale ones are revalidated with If-None-Match / If-Modified-Since, and a 304 is answered from the cache.
+// Writes (POST, PUT, DELETE and `_method=DELETE`) invalidate the cached responses of the resource and its collection.
+type cacheTransport struct {
+	store     CacheStore
+	transport http.RoundTripper
+	baseURL   string
+	now       func() time.Time
+}
+
+func newCacheTransport(store CacheStore, transport http.RoundTripper, baseURL string) *cacheTransport {
+	if transport == nil {
+		transport = &http.Transport{DisableKeepAlives: true}
+	}
+	return &cacheTransport{store: store, transport: transport, baseURL: strings.TrimRight(baseURL, "/"), now: time.Now}
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if isWrite(req) {
+		resp, err := t.transport.RoundTrip(req)
+		if err == nil && resp.StatusCode < http.StatusBadRequest {
+			t.invalidate(req.URL)
+		}
+		return resp, err
+	}
+	if req.Method != "GET" || !t.covers(req.URL) {
+		return t.transport.RoundTrip(req)
+	}
+
+	key := cacheKey(req)
+	cached := t.lookup(key)
+	if cached != nil && !hasDirective(req.Header, "no-cache") && t.fresh(cached) {
+		return cached.response(req), nil
+	}
+
+	if cached != nil {
+		req = cloneRequest(req)
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if modified := cached.Header.Get("Last-Modified"); modified != "" {
+			req.Header.Set("If-Modified-Since", modified)
+		}
+	}
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		for _, k := range []string{"Cache-Control", "Date", "ETag", "Expires", "Last-Modified"} {
+			if v := resp.Header.Get(k); v != "" {
+				cached.Header.Set(k, v)
+			}
+		}
+		cached.Stored = t.now()
+		t.save(key, cached)
+		return cached.response(req), nil
+	}
+
+	if resp.StatusCode != http.StatusOK || !isJSON(resp) || !cacheable(resp) {
+		if cached != nil {
+			t.store.Delete(key)
+		}
+		return resp, nil
+	}
+	body, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	t.save(key, &cachedResponse{
+		URL:        req.URL.String(),
+		Status:     resp.Status,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+		Stored:     t.now(),
+	})
+	return resp, nil
+}
+
+// covers reports whether a URL is under the base URL of the API
+func (t *cacheTransport) covers(u *url.URL) bool {
+	s := u.String()
+	return s == t.baseURL || strings.HasPrefix(s, t.baseURL+"/") || strings.HasPrefix(s, t.baseURL+"?")
+}
+
+func (t *cacheTransport) lookup(key string) *cachedResponse {
+	data, ok := t.store.Get(key)
+	if !ok {
+		return nil
+	}
+	var cached cachedResponse
+	if err := json.Unmarshal(data, &cached); err != nil {
+		t.store.Delete(key)
+		return nil
+	}
+	return &cached
+}
+
+func (t *cacheTransport) save(key string, cached *cachedResponse) {
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return
+	}
+	t.store.Set(key, data)
+}
+
+// fresh reports whether a cached response can be served without revalidation
+func (t *cacheTransport) fresh(cached *cachedResponse) bool {
+	if hasDirective(cached.Header, "no-cache") {
+		return false
+	}
+	age := t.now().Sub(cached.Stored)
+	if maxAge, ok := directiveSeconds(cached.Header, "max-age"); ok {
+		return age < time.Duration(maxAge)*time.Second
+	}
+	if expires := cached.Header.Get("Expires"); expires != "" {
+		expiresAt, err := http.ParseTime(expires)
+		if err != nil {
+			return false
+		}
+		date, err := http.ParseTime(cached.Header.Get("Date"))
+		if err != nil {
+			date = cached.Stored
+		}
+		return age < expiresAt.Sub(date)
+	}
+	return false
+}
+
+// invalidate deletes the cached responses of a written resource, of its sub-resources and of its collection
+func (t *cacheTransport) invalidate(u *url.URL) {
+	path := strings.TrimRight(u.Path, "/")
+	var parent string
+	if i := strings.LastIndex(path, "/"); i > 0 && isNumeric(path[i+1:]) {
+		parent = path[:i]
+	}
+	for _, key := range t.store.Keys() {
+		cachedURL, err := url.Parse(key[strings.Index(key, " ")+1:])
+		if err != nil || cachedURL.Host != u.Host {
+			continue
+		}
+		cachedPath := strings.TrimRight(cachedURL.Path, "/")
+		if cachedPath == path || cachedPath == parent || (parent != "" && strings.HasPrefix(cachedPath, path+"/")) {
+			t.store.Delete(key)
+		}
+	}
+}
+
+func (cached *cachedResponse) response(req *http.Request) *http.Response {
+	header := http.Header{}
+	for k, v := range cached.Header {
+		header[k] = v
+	}
+	header.Set(XFromCache, "1")
+	return &http.Response{
+		Status:        cached.Status,
+		StatusCode:    cached.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(cached.Body)),
+		ContentLength: int64(len(cached.Body)),
+		Request:       req,
+	}
+}
+
+// cacheKey identifies a GET request; credentials are part of the key (as their full SHA-256 hash, so that two
+// users never share an entry), since responses depend on the user
+func cacheKey(req *http.Request) string {
+	hash := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return hex.EncodeToString(hash[:]) + " " + req.URL.String()
+}
+
+func isWrite(req *http.Request) bool {
+	if req.Method != "GET" && req.Method != "HEAD" && req.Method != "OPTIONS" {
+		return true
+	}
+	return methodOverride(req.Header) != "" || req.URL.Query().Get("_method") != ""
+}
+
+func isJSON(resp *http.Response) bool {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+func cacheable(resp *http.Response) bool {
+	if hasDirective(resp.Header, "no-store") {
+		return false
+	}
+	if _, ok := directiveSeconds(resp.Header, "max-age"); ok {
+		return true
+	}
+	return resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != "" || resp.Header.Get("Expires") != ""
+}
+
+func hasDirective(header http.Header, directive string) bool {
+	for _, d := range strings.Split(header.Get("Cache-Control"), ",") {
+		if strings.EqualFold(strings.TrimSpace(d), directive) {
+			return true
+		}
+	}
+	return false
+}
+
+func directiveSeconds(header http.Header, directive string) (int, bool) {
+	for _, d := range strings.Split(header.Get("Cache-Control"), ",") {
+		parts := strings.SplitN(strings.TrimSpace(d), "=", 2)
+		if len(parts) == 2 && strings.EqualFold(parts[0], directive) {
+			seconds, err := strconv.Atoi(strings.Trim(parts[1], `"`))
+			return seconds, err == nil
+		}
+	}
+	return 0, false
+}
+
+var numericRegexp = regexp.MustCompile(`^\d+$`)
+
+func isNumeric(s string) bool {
+	return numericRegexp.MatchString(s)
+}
+
+func cloneRequest(req *http.Request) *http.Request {
+	clone := new(http.Request)
+	*clone = *req
+	clone.Header = http.Header{}
+	for k, v := range req.Header {
+		clone.Header[k] = v
+	}
+	return clone
+}
+
+// LRUCache is an in-memory CacheStore holding up to a fixed number of responses,
+// evicting the least recently used ones
+type LRUCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key   string
+	value []byte
+}
+
+// NewLRUCache returns an LRUCache holding up to size responses
+func NewLRUCache(size int) *LRUCache {
+	return &LRUCache{size: size, order: list.New(), entries: map[string]*list.Element{}}
+}
+
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*lruEntry).value, true
+}
+
+func (c *LRUCache) Set(key string, value []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*lruEntry).value = value
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key, value})
+	for c.size > 0 && c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (c *LRUCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		c.order.Remove(e)
+		delete(c.entries, key)
+	}
+}
+
+func (c *LRUCache) Keys() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var keys []string
+	for e := c.order.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Value.(*lruEntry).key)
+	}
+	return keys
+}
+
+// DiskCache is a CacheStore keeping one file per response in a directory,
+// so cached responses survive restarts
+type DiskCache struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewDiskCache returns a DiskCache storing responses in dir, which is created if needed
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+// filename returns the file of a key; the key itself is written on the first line of the file
+func (c *DiskCache) filename(key string) string {
+	hash := sha1.Sum([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(hash[:]))
+}
+
+func (c *DiskCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, err := ioutil.ReadFile(c.filename(key))
+	if err != nil {
+		return nil, false
+	}
+	i := bytes.IndexByte(data, '\n')
+	if i < 0 || string(data[:i]) != key {
+		return nil, false
+	}
+	return data[i+1:], true
+}
+
+func (c *DiskCache) Set(key string, value []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data := append([]byte(key+"\n"), value...)
+	tmp := c.filename(key) + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return
+	}
+	os.Rename(tmp, c.filename(key))
+}
+
+func (c *DiskCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	os.Remove(c.filename(key))
+}
+
+func (c *DiskCache) Keys() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	files, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		return nil
+	}
+	var keys []string
+	for _, file := range files {
+		if file.IsDir() || strings.HasSuffix(file.Name(), ".tmp") {
+			continue
+		}
+		f, err := os.Open(filepath.Join(c.dir, file.Name()))
+		if err != nil {
+			continue
+		}
+		key, err := bufio.NewReader(f).ReadString('\n')
+		f.Close()
+		if err == nil {
+			keys = append(keys, strings.TrimSuffix(key, "\n"))
+		}
+	}
+	return keys
+}
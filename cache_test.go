@@ -0,0 +1,259 @@
+package wordpress_test
+
+import (
+	"fmt"
+	"github.com/sogko/go-wordpress"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// cacheTestServer serves posts with an ETag and pages with a max-age, and counts the requests it handles.
+// An uploaded file and a JSON document outside the API are served with a max-age too.
+type cacheTestServer struct {
+	*httptest.Server
+	mu          sync.Mutex
+	hits        map[string]int
+	version     int
+	notModified int
+}
+
+func newCacheTestServer() *cacheTestServer {
+	s := &cacheTestServer{hits: map[string]int{}, version: 1}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.hits[r.Method+" "+r.URL.Path]++
+
+		switch r.URL.Path {
+		case "/wp-content/uploads/photo.jpg":
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Header().Set("Cache-Control", "max-age=60")
+			fmt.Fprint(w, "JPEG")
+			return
+		case "/other.json":
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Cache-Control", "max-age=60")
+			fmt.Fprint(w, `{}`)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		if r.Method == "POST" {
+			s.version++
+			fmt.Fprintf(w, `{"id":1,"title":{"raw":"v%v"}}`, s.version)
+			return
+		}
+		switch r.URL.Path {
+		case "/wp/v2/posts/1":
+			etag := fmt.Sprintf(`"v%v"`, s.version)
+			w.Header().Set("ETag", etag)
+			if r.Header.Get("If-None-Match") == etag {
+				s.notModified++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			fmt.Fprintf(w, `{"id":1,"title":{"raw":"v%v"}}`, s.version)
+		case "/wp/v2/pages", "/wp/v2/pages/1":
+			w.Header().Set("Cache-Control", "max-age=60")
+			if r.URL.Path == "/wp/v2/pages" {
+				fmt.Fprintf(w, `[{"id":1,"title":{"raw":"v%v"}}]`, s.version)
+				return
+			}
+			fmt.Fprintf(w, `{"id":1,"title":{"raw":"v%v"}}`, s.version)
+		case "/wp/v2/media":
+			w.Header().Set("Cache-Control", "no-store, max-age=60")
+			fmt.Fprint(w, `[]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	return s
+}
+
+func (s *cacheTestServer) Hits(method string, path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.hits[method+" "+path]
+}
+
+func newCachingClient(server *cacheTestServer, cache wordpress.CacheStore) *wordpress.Client {
+	return wordpress.NewClient(&wordpress.Options{
+		BaseAPIURL: server.URL + "/wp/v2",
+		Username:   "user",
+		Password:   "password",
+		Cache:      cache,
+	})
+}
+
+func TestCache_Revalidation(t *testing.T) {
+	server := newCacheTestServer()
+	defer server.Close()
+	wp := newCachingClient(server, wordpress.NewLRUCache(100))
+
+	for i := 0; i < 3; i++ {
+		post, resp, _, err := wp.Posts().Get(1, nil)
+		if err != nil {
+			t.Fatalf("Should not return error: %v", err.Error())
+		}
+		if resp.StatusCode != http.StatusOK || post.Title.Raw != "v1" {
+			t.Errorf("Expected the cached post, got %v %v", resp.Status, post.Title.Raw)
+		}
+		if i > 0 && resp.Header.Get(wordpress.XFromCache) == "" {
+			t.Errorf("Expected %v header on a revalidated response", wordpress.XFromCache)
+		}
+	}
+	if server.Hits("GET", "/wp/v2/posts/1") != 3 || server.notModified != 2 {
+		t.Errorf("Expected 3 requests, 2 answered with 304, got %v and %v", server.Hits("GET", "/wp/v2/posts/1"), server.notModified)
+	}
+}
+
+func TestCache_MaxAge(t *testing.T) {
+	server := newCacheTestServer()
+	defer server.Close()
+	wp := newCachingClient(server, wordpress.NewLRUCache(100))
+
+	for i := 0; i < 3; i++ {
+		pages, _, _, err := wp.Pages().List(nil)
+		if err != nil {
+			t.Fatalf("Should not return error: %v", err.Error())
+		}
+		if len(pages) != 1 {
+			t.Errorf("Expected one page, got %v", len(pages))
+		}
+	}
+	if hits := server.Hits("GET", "/wp/v2/pages"); hits != 1 {
+		t.Errorf("Expected a fresh response to be served from the cache, got %v requests", hits)
+	}
+
+	wp.Media().List(nil)
+	wp.Media().List(nil)
+	if hits := server.Hits("GET", "/wp/v2/media"); hits != 2 {
+		t.Errorf("Expected no-store responses not to be cached, got %v requests", hits)
+	}
+}
+
+func TestCache_InvalidateOnWrite(t *testing.T) {
+	server := newCacheTestServer()
+	defer server.Close()
+	wp := newCachingClient(server, wordpress.NewLRUCache(100))
+
+	wp.Pages().List(nil)
+	wp.Pages().Get(1, nil)
+	wp.Pages().Get(1, nil)
+	if server.Hits("GET", "/wp/v2/pages/1") != 1 {
+		t.Fatalf("Expected the page to be cached")
+	}
+
+	if _, _, _, err := wp.Pages().Update(1, &wordpress.Page{Title: wordpress.Title{Raw: "v2"}}); err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+
+	page, _, _, _ := wp.Pages().Get(1, nil)
+	if page.Title.Raw != "v2" || server.Hits("GET", "/wp/v2/pages/1") != 2 {
+		t.Errorf("Expected the updated page to be fetched again, got %v", page.Title.Raw)
+	}
+	pages, _, _, _ := wp.Pages().List(nil)
+	if len(pages) != 1 || pages[0].Title.Raw != "v2" || server.Hits("GET", "/wp/v2/pages") != 2 {
+		t.Errorf("Expected the collection to be fetched again, got %v", pages)
+	}
+}
+
+func TestCache_KeyedByCredentials(t *testing.T) {
+	server := newCacheTestServer()
+	defer server.Close()
+	cache := wordpress.NewLRUCache(100)
+	wp := newCachingClient(server, cache)
+	other := wordpress.NewClient(&wordpress.Options{BaseAPIURL: server.URL + "/wp/v2", Username: "other", Password: "password", Cache: cache})
+
+	wp.Pages().Get(1, nil)
+	other.Pages().Get(1, nil)
+	if hits := server.Hits("GET", "/wp/v2/pages/1"); hits != 2 {
+		t.Errorf("Expected users not to share cached responses, got %v requests", hits)
+	}
+	keys := cache.Keys()
+	if len(keys) != 2 || keys[0] == keys[1] {
+		t.Fatalf("Expected a key for each user, got %v", keys)
+	}
+	for _, key := range keys {
+		// the full SHA-256 of the Authorization header
+		if hash := strings.SplitN(key, " ", 2)[0]; len(hash) != 64 {
+			t.Errorf("Expected a full hash of the credentials, got %v", hash)
+		}
+	}
+}
+
+func TestCache_OnlyAPI(t *testing.T) {
+	server := newCacheTestServer()
+	defer server.Close()
+	cache := wordpress.NewLRUCache(100)
+	wp := newCachingClient(server, cache)
+
+	for i := 0; i < 2; i++ {
+		for _, path := range []string{"/wp-content/uploads/photo.jpg", "/other.json"} {
+			resp, err := wp.GetStream(server.URL+path, nil)
+			if err != nil {
+				t.Fatalf("Should not return error: %v", err.Error())
+			}
+			resp.Body.Close()
+		}
+	}
+	if server.Hits("GET", "/wp-content/uploads/photo.jpg") != 2 || server.Hits("GET", "/other.json") != 2 {
+		t.Errorf("Expected files and URLs outside the API not to be cached")
+	}
+	if keys := cache.Keys(); len(keys) != 0 {
+		t.Errorf("Expected nothing to be cached, got %v", keys)
+	}
+}
+
+func TestCache_Disk(t *testing.T) {
+	server := newCacheTestServer()
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "go-wordpress-cache")
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	cache, err := wordpress.NewDiskCache(dir)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	newCachingClient(server, cache).Pages().Get(1, nil)
+
+	// a new client, with a new store on the same directory
+	cache, _ = wordpress.NewDiskCache(dir)
+	if len(cache.Keys()) != 1 {
+		t.Fatalf("Expected one cached response, got %v", cache.Keys())
+	}
+	page, resp, _, err := newCachingClient(server, cache).Pages().Get(1, nil)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if page.ID != 1 || resp.Header.Get(wordpress.XFromCache) == "" || server.Hits("GET", "/wp/v2/pages/1") != 1 {
+		t.Errorf("Expected the page to be served from the disk cache")
+	}
+}
+
+func TestLRUCache(t *testing.T) {
+	cache := wordpress.NewLRUCache(2)
+	cache.Set("a", []byte("1"))
+	cache.Set("b", []byte("2"))
+	cache.Get("a")
+	cache.Set("c", []byte("3"))
+
+	if _, ok := cache.Get("b"); ok {
+		t.Errorf("Expected the least recently used entry to be evicted")
+	}
+	if v, ok := cache.Get("a"); !ok || string(v) != "1" {
+		t.Errorf("Expected entry a to be kept, got %s", v)
+	}
+	cache.Delete("a")
+	if keys := cache.Keys(); len(keys) != 1 || keys[0] != "c" {
+		t.Errorf("Expected only c to be left, got %v", keys)
+	}
+}
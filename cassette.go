@@ -0,0 +1,276 @@
+package wordpress
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Redacted replaces the value of sensitive headers in recorded interactions
+const Redacted = "[REDACTED]"
+
+// RedactedHeaders are the headers a Recorder never writes to a cassette
+var RedactedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-WP-Nonce",
+}
+
+// RecordedRequest is the request half of an Interaction
+type RecordedRequest struct {
+	Method       string      `json:"method"`
+	URL          string      `json:"url"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"body_encoding,omitempty"`
+}
+
+// RecordedResponse is the response half of an Interaction
+type RecordedResponse struct {
+	Status       string      `json:"status"`
+	StatusCode   int         `json:"status_code"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"body_encoding,omitempty"`
+}
+
+// Interaction is a request/response pair, stored one per line in a JSONL cassette.
+// Bodies are stored as text, or base64-encoded (BodyEncoding "base64") when they are not valid UTF-8.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// Recorder is an http.RoundTripper that writes every request/response pair it sees to a cassette.
+// Set it as Options.Transport to record a client's traffic.
+type Recorder struct {
+	// Transport performs the requests; http.DefaultTransport is used if nil
+	Transport http.RoundTripper
+
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewRecorder returns a Recorder writing interactions to w, one JSON object per line
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{enc: json.NewEncoder(w)}
+}
+
+func (rec *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	transport := rec.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	u := *req.URL
+	u.User = nil
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    u.String(),
+			Header: redactHeader(req.Header),
+		},
+		Response: RecordedResponse{
+			Status:     resp.Status,
+			StatusCode: resp.StatusCode,
+			Header:     redactHeader(resp.Header),
+		},
+	}
+	interaction.Request.Body, interaction.Request.BodyEncoding = encodeBody(reqBody)
+	interaction.Response.Body, interaction.Response.BodyEncoding = encodeBody(respBody)
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if err := rec.enc.Encode(&interaction); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Replayer is an http.RoundTripper that serves the responses of a cassette instead of sending requests.
+// Set it as Options.Transport to run a client offline.
+//
+// A request is answered by the first unused interaction with the same method, URL and
+// X-HTTP-Method-Override header, so identical requests get their responses in recorded order.
+// Request bodies are only compared if MatchBody is set.
+type Replayer struct {
+	MatchBody bool
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer returns a Replayer serving the interactions read from a cassette
+func NewReplayer(r io.Reader) (*Replayer, error) {
+	replayer := &Replayer{}
+	dec := json.NewDecoder(r)
+	for {
+		var interaction Interaction
+		err := dec.Decode(&interaction)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid cassette: %v", err)
+		}
+		replayer.interactions = append(replayer.interactions, interaction)
+	}
+	replayer.used = make([]bool, len(replayer.interactions))
+	return replayer, nil
+}
+
+// LoadCassette returns a Replayer serving the interactions recorded in filename
+func LoadCassette(filename string) (*Replayer, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return NewReplayer(f)
+}
+
+func (replayer *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	body, encoding := encodeBody(reqBody)
+
+	u := *req.URL
+	u.User = nil
+	url := u.String()
+	override := methodOverride(req.Header)
+
+	replayer.mu.Lock()
+	defer replayer.mu.Unlock()
+	for i, interaction := range replayer.interactions {
+		recorded := interaction.Request
+		if replayer.used[i] || recorded.Method != req.Method || recorded.URL != url || methodOverride(recorded.Header) != override {
+			continue
+		}
+		if replayer.MatchBody && (recorded.Body != body || recorded.BodyEncoding != encoding) {
+			continue
+		}
+		replayer.used[i] = true
+
+		respBody, err := decodeBody(interaction.Response.Body, interaction.Response.BodyEncoding)
+		if err != nil {
+			return nil, err
+		}
+		header := http.Header{}
+		for k, v := range interaction.Response.Header {
+			header[k] = v
+		}
+		return &http.Response{
+			Status:        interaction.Response.Status,
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewReader(respBody)),
+			ContentLength: int64(len(respBody)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("no recorded response for %v %v", req.Method, url)
+}
+
+// Unused returns the interactions that have not been replayed yet
+func (replayer *Replayer) Unused() []Interaction {
+	replayer.mu.Lock()
+	defer replayer.mu.Unlock()
+	var unused []Interaction
+	for i, interaction := range replayer.interactions {
+		if !replayer.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+// useTransport routes the requests of a gorequest transport through rt.
+// gorequest only accepts an *http.Transport, so rt is registered as the handler of the http(s) schemes.
+func useTransport(t *http.Transport, rt http.RoundTripper) {
+	// HTTP/2 would otherwise register itself for "https"
+	t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	t.RegisterProtocol("http", rt)
+	t.RegisterProtocol("https", rt)
+}
+
+// readBody reads a request or response body and replaces it with an unread copy
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil {
+		return nil, nil
+	}
+	data, err := ioutil.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = ioutil.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+func redactHeader(header http.Header) http.Header {
+	redacted := http.Header{}
+	for k, v := range header {
+		redacted[k] = v
+	}
+	for _, k := range RedactedHeaders {
+		if _, ok := redacted[http.CanonicalHeaderKey(k)]; ok {
+			redacted.Set(k, Redacted)
+		}
+	}
+	return redacted
+}
+
+func methodOverride(header http.Header) string {
+	for _, k := range []string{"X-HTTP-Method-Override", "HTTP_X_HTTP_METHOD_OVERRIDE"} {
+		if v := header.Get(k); v != "" {
+			return strings.ToUpper(v)
+		}
+	}
+	return ""
+}
+
+func encodeBody(data []byte) (string, string) {
+	if utf8.Valid(data) {
+		return string(data), ""
+	}
+	return base64.StdEncoding.EncodeToString(data), "base64"
+}
+
+func decodeBody(body string, encoding string) ([]byte, error) {
+	switch encoding {
+	case "":
+		return []byte(body), nil
+	case "base64":
+		return base64.StdEncoding.DecodeString(body)
+	}
+	return nil, fmt.Errorf("unknown body encoding %q", encoding)
+}
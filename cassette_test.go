@@ -0,0 +1,122 @@
+package wordpress_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/sogko/go-wordpress"
+	"github.com/sogko/go-wordpress/wptest"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCassette_RecordAndReplay(t *testing.T) {
+	server := newFixtureServer(t)
+	var cassette bytes.Buffer
+
+	wp := wordpress.NewClient(&wordpress.Options{
+		BaseAPIURL: server.BaseAPIURL(),
+		Username:   wptest.DefaultUsername,
+		Password:   wptest.DefaultPassword,
+		Transport:  wordpress.NewRecorder(&cassette),
+	})
+	post, _, _, err := wp.Posts().Get(1, "context=edit")
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	_, _, _, err = wp.Posts().Update(1, &wordpress.Post{Title: wordpress.Title{Raw: "Updated"}})
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	image := []byte{0xff, 0xd8, 0xff, 0xe0, 0x00}
+	_, resp, _, _ := wp.Media().Create(&wordpress.MediaUploadOptions{
+		Filename:    "binary.jpg",
+		ContentType: "image/jpeg",
+		Data:        image,
+	})
+	server.Close()
+
+	lines := strings.Split(strings.TrimSpace(cassette.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 recorded interactions, got %v", len(lines))
+	}
+	for _, line := range lines {
+		var interaction wordpress.Interaction
+		if err := json.Unmarshal([]byte(line), &interaction); err != nil {
+			t.Fatalf("Should not return error: %v", err.Error())
+		}
+		if interaction.Request.Header.Get("Authorization") != wordpress.Redacted {
+			t.Errorf("Expected Authorization to be redacted, got %v", interaction.Request.Header.Get("Authorization"))
+		}
+	}
+	if strings.Contains(cassette.String(), wptest.DefaultPassword) {
+		t.Errorf("Credentials should not be recorded")
+	}
+
+	replayer, err := wordpress.NewReplayer(bytes.NewReader(cassette.Bytes()))
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	wp = wordpress.NewClient(&wordpress.Options{
+		BaseAPIURL: server.BaseAPIURL(),
+		Transport:  replayer,
+	})
+	replayed, _, _, err := wp.Posts().Get(1, "context=edit")
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if replayed.Title.Raw != post.Title.Raw || replayed.Content.Raw != post.Content.Raw {
+		t.Errorf("Expected the recorded post, got %v", replayed)
+	}
+	updated, _, _, err := wp.Posts().Update(1, &wordpress.Post{Title: wordpress.Title{Raw: "Changed since recording"}})
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if updated.Title.Raw != "Updated" {
+		t.Errorf("Expected the recorded update, got %v", updated.Title.Raw)
+	}
+	_, replayedResp, _, _ := wp.Media().Create(&wordpress.MediaUploadOptions{
+		Filename:    "binary.jpg",
+		ContentType: "image/jpeg",
+		Data:        image,
+	})
+	if replayedResp.StatusCode != resp.StatusCode {
+		t.Errorf("Expected %v, got %v", resp.Status, replayedResp.Status)
+	}
+	if unused := replayer.Unused(); len(unused) != 0 {
+		t.Errorf("Expected every interaction to be replayed, got %v left", len(unused))
+	}
+
+	// each interaction is replayed once
+	_, _, _, err = wp.Posts().Get(1, "context=edit")
+	if err == nil {
+		t.Errorf("Should return error for a request that was not recorded")
+	}
+}
+
+func TestCassette_MatchBody(t *testing.T) {
+	cassette := `{"request":{"method":"POST","url":"http://example.com/wp-json/wp/v2/posts","body":"{\"title\":\"A\"}"},"response":{"status":"201 Created","status_code":201,"body":"{\"id\":1}"}}
+{"request":{"method":"POST","url":"http://example.com/wp-json/wp/v2/posts","body":"{\"title\":\"B\"}"},"response":{"status":"201 Created","status_code":201,"body":"{\"id\":2}"}}
+`
+	replayer, err := wordpress.NewReplayer(strings.NewReader(cassette))
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	replayer.MatchBody = true
+	wp := wordpress.NewClient(&wordpress.Options{
+		BaseAPIURL: "http://example.com/wp-json/wp/v2",
+		Transport:  replayer,
+	})
+
+	var created map[string]interface{}
+	resp, _, err := wp.Create("http://example.com/wp-json/wp/v2/posts", map[string]interface{}{"title": "B"}, &created)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if resp.StatusCode != http.StatusCreated || created["id"] != float64(2) {
+		t.Errorf("Expected the interaction with the same body, got %v %v", resp.Status, created)
+	}
+	if _, _, err := wp.Create("http://example.com/wp-json/wp/v2/posts", map[string]interface{}{"title": "C"}, &created); err == nil {
+		t.Errorf("Should return error for a body that was not recorded")
+	}
+}
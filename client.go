@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/parnurzeal/gorequest"
+	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
+	neturl "net/url"
 	"reflect"
+	"time"
 )
 
 const (
@@ -17,11 +20,14 @@ const (
 	CollectionMedia      = "media"
 	CollectionMeta       = "meta"
 	CollectionRevisions  = "revisions"
+	CollectionAutosaves  = "autosaves"
 	CollectionComments   = "comments"
 	CollectionTaxonomies = "taxonomies"
 	CollectionTerms      = "terms"
 	CollectionStatuses   = "statuses"
 	CollectionTypes      = "types"
+
+	CollectionApplicationPasswords = "application-passwords"
 )
 
 type GeneralError struct {
@@ -37,12 +43,24 @@ type Options struct {
 	Username string
 	Password string
 	// TODO: support OAuth authentication
+
+	// Validate Create() and Update() payloads against the route's schema before sending them.
+	// Schemas are fetched once per collection and cached by the client.
+	ValidateSchema bool
+
+	// Transport, if set, handles the client's HTTP requests, for eg. a Recorder or a Replayer
+	Transport http.RoundTripper
+
+	// Cache, if set, caches the JSON responses of GET requests under BaseAPIURL, honoring ETag, Last-Modified and
+	// Cache-Control, for eg. NewLRUCache(1000) or a DiskCache. Writes through the client invalidate the resource.
+	Cache CacheStore
 }
 
 type Client struct {
 	req     *gorequest.SuperAgent
 	options *Options
 	baseURL string
+	schemas *schemaCache
 }
 
 // Used to create a new SuperAgent object.
@@ -65,62 +83,70 @@ func NewClient(options *Options) *Client {
 		log.Println("REDIRECT", r, options.Username, options.Password)
 		return nil
 	})
+	transport := options.Transport
+	if options.Cache != nil {
+		transport = newCacheTransport(options.Cache, transport, options.BaseAPIURL)
+	}
+	if transport != nil {
+		useTransport(req.Transport, transport)
+	}
 	return &Client{
 		req:     req,
 		options: options,
 		baseURL: options.BaseAPIURL,
+		schemas: &schemaCache{routes: map[string]*Route{}, failures: map[string]time.Time{}},
 	}
 }
 
-func (client *Client) Users() *UsersCollection {
+func (client *Client) Users() UsersService {
 	return &UsersCollection{
 		client: client,
 		url:    fmt.Sprintf("%v/%v", client.baseURL, CollectionUsers),
 	}
 }
-func (client *Client) Posts() *PostsCollection {
+func (client *Client) Posts() PostsService {
 	return &PostsCollection{
 		client: client,
 		url:    fmt.Sprintf("%v/%v", client.baseURL, CollectionPosts),
 	}
 }
-func (client *Client) Pages() *PagesCollection {
+func (client *Client) Pages() PagesService {
 	return &PagesCollection{
 		client: client,
 		url:    fmt.Sprintf("%v/%v", client.baseURL, CollectionPages),
 	}
 }
-func (client *Client) Media() *MediaCollection {
+func (client *Client) Media() MediaService {
 	return &MediaCollection{
 		client: client,
 		url:    fmt.Sprintf("%v/%v", client.baseURL, CollectionMedia),
 	}
 }
-func (client *Client) Comments() *CommentsCollection {
+func (client *Client) Comments() CommentsService {
 	return &CommentsCollection{
 		client: client,
 		url:    fmt.Sprintf("%v/%v", client.baseURL, CollectionComments),
 	}
 }
-func (client *Client) Taxonomies() *TaxonomiesCollection {
+func (client *Client) Taxonomies() TaxonomiesService {
 	return &TaxonomiesCollection{
 		client: client,
 		url:    fmt.Sprintf("%v/%v", client.baseURL, CollectionTaxonomies),
 	}
 }
-func (client *Client) Terms() *TermsCollection {
+func (client *Client) Terms() TermsService {
 	return &TermsCollection{
 		client: client,
 		url:    fmt.Sprintf("%v/%v", client.baseURL, CollectionTerms),
 	}
 }
-func (client *Client) Statuses() *StatusesCollection {
+func (client *Client) Statuses() StatusesService {
 	return &StatusesCollection{
 		client: client,
 		url:    fmt.Sprintf("%v/%v", client.baseURL, CollectionStatuses),
 	}
 }
-func (client *Client) Types() *TypesCollection {
+func (client *Client) Types() TypesService {
 	return &TypesCollection{
 		client: client,
 		url:    fmt.Sprintf("%v/%v", client.baseURL, CollectionTypes),
@@ -139,6 +165,11 @@ func (client *Client) List(url string, params interface{}, result interface{}) (
 }
 func (client *Client) Create(url string, content interface{}, result interface{}) (*http.Response, []byte, error) {
 	contentVal := unpackInterfacePointer(content)
+	if client.options.ValidateSchema {
+		if err := client.validate("POST", url, contentVal); err != nil {
+			return nil, nil, err
+		}
+	}
 	client.req.TargetType = "json"
 	req := client.req.Post(url).Send(contentVal)
 	resp, body, errSlice := req.EndBytes()
@@ -162,6 +193,11 @@ func (client *Client) Get(url string, params interface{}, result interface{}) (*
 func (client *Client) Update(url string, content interface{}, result interface{}) (*http.Response, []byte, error) {
 
 	contentVal := unpackInterfacePointer(content)
+	if client.options.ValidateSchema {
+		if err := client.validate("PUT", url, contentVal); err != nil {
+			return nil, nil, err
+		}
+	}
 
 	client.req.TargetType = "json"
 	req := client.req.Post(url).Send(contentVal)
@@ -187,21 +223,39 @@ func (client *Client) Delete(url string, params interface{}, result interface{})
 	_resp := http.Response(*resp)
 	return &_resp, by, err
 }
+func (client *Client) Options(url string, result interface{}) (*http.Response, []byte, error) {
+	client.req.TargetType = "json"
+	resp, body, errSlice := client.req.Options(url).EndBytes()
+	if errSlice != nil && len(errSlice) > 0 {
+		return nil, body, errSlice[len(errSlice)-1]
+	}
+	err := unmarshallResponse(resp, body, result)
+	_resp := http.Response(*resp)
+	return &_resp, body, err
+}
 func (client *Client) PostData(url string, content []byte, contentType string, filename string, result interface{}) (*http.Response, []byte, error) {
+	header := http.Header{}
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Disposition", contentDisposition("attachment", "", filename))
+	return client.PostStream(url, bytes.NewReader(content), int64(len(content)), header, result)
+}
+
+// PostStream POSTs the body as is, with the given headers.
+// length is the size of the body, or -1 if unknown, in which case the body is sent chunked.
+func (client *Client) PostStream(url string, body io.Reader, length int64, header http.Header, result interface{}) (*http.Response, []byte, error) {
 
 	// gorequest does not support POST-ing raw data
 	// so, we have to manually create a HTTP client
 	s := client.req.Post(url)
 
-	buf := bytes.NewBuffer(content)
-
-	req, err := http.NewRequest(s.Method, s.Url, buf)
+	req, err := http.NewRequest(s.Method, s.Url, body)
 	if err != nil {
 		return nil, nil, err
 	}
-
-	req.Header.Set("Content-Type", contentType)
-	req.Header.Set("Content-Disposition", fmt.Sprintf("filename=%v", filename))
+	req.ContentLength = length
+	for k, v := range header {
+		req.Header[k] = v
+	}
 
 	// Add basic auth
 	req.SetBasicAuth(s.BasicAuth.Username, s.BasicAuth.Password)
@@ -216,14 +270,34 @@ func (client *Client) PostData(url string, content []byte, contentType string, f
 	}
 	defer resp.Body.Close()
 
-	body, err := ioutil.ReadAll(resp.Body)
+	respBody, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	err = unmarshallResponse(resp, body, result)
+	err = unmarshallResponse(resp, respBody, result)
 	_resp := http.Response(*resp)
-	return &_resp, body, err
+	return &_resp, respBody, err
+}
+
+// GetStream GETs a URL, for eg. the source URL of a media item, with the given headers and returns the response
+// unread; the caller must close its body. Credentials are only sent to the host of the API.
+func (client *Client) GetStream(url string, header http.Header) (*http.Response, error) {
+	s := client.req.Get(url)
+
+	req, err := http.NewRequest(s.Method, s.Url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if base, err := neturl.Parse(client.baseURL); err == nil && base.Host == req.URL.Host {
+		req.SetBasicAuth(s.BasicAuth.Username, s.BasicAuth.Password)
+	}
+
+	s.Client.Transport = s.Transport
+	return s.Client.Do(req)
 }
 
 func unpackInterfacePointer(content interface{}) interface{} {
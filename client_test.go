@@ -2,6 +2,9 @@ package wordpress_test
 
 import (
 	"github.com/sogko/go-wordpress"
+	"github.com/sogko/go-wordpress/wptest"
+	"log"
+	"net/http"
 	"os"
 	"testing"
 )
@@ -9,6 +12,47 @@ import (
 var USER string = os.Getenv("WP_USER")
 var PASSWORD string = os.Getenv("WP_PASSWD")
 var API_BASE_URL string = os.Getenv("WP_API_URL")
+var TRANSPORT http.RoundTripper
+
+// TestMain runs the tests against an in-memory fake server (see package wptest),
+// unless `WP_API_URL` points them at a live WordPress installation.
+// `WP_RECORD=<cassette>` records the traffic of the tests, `WP_REPLAY=<cassette>` replays it offline.
+func TestMain(m *testing.M) {
+	if filename := os.Getenv("WP_RECORD"); filename != "" {
+		f, err := os.Create(filename)
+		if err != nil {
+			log.Fatal(err)
+		}
+		TRANSPORT = wordpress.NewRecorder(f)
+	}
+	if filename := os.Getenv("WP_REPLAY"); filename != "" {
+		if API_BASE_URL == "" {
+			log.Fatal("WP_REPLAY requires WP_API_URL, set to the URL the cassette was recorded against")
+		}
+		replayer, err := wordpress.LoadCassette(filename)
+		if err != nil {
+			log.Fatal(err)
+		}
+		TRANSPORT = replayer
+	}
+
+	if API_BASE_URL != "" {
+		os.Exit(m.Run())
+	}
+
+	store, err := loadFixtureStore()
+	if err != nil {
+		log.Fatal(err)
+	}
+	server := wptest.NewServer(store)
+	API_BASE_URL = server.BaseAPIURL()
+	USER = wptest.DefaultUsername
+	PASSWORD = wptest.DefaultPassword
+
+	code := m.Run()
+	server.Close()
+	os.Exit(code)
+}
 
 func TestClientNew(t *testing.T) {
 	client := wordpress.NewClient(&wordpress.Options{
@@ -36,5 +80,6 @@ func initTestClient() *wordpress.Client {
 		BaseAPIURL: API_BASE_URL,
 		Username:   USER,
 		Password:   PASSWORD,
+		Transport:  TRANSPORT,
 	})
 }
@@ -0,0 +1,383 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/sogko/go-wordpress"
+	"go/format"
+	"log"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+// initialisms are upper-cased as a whole when converting snake_case names into Go identifiers
+var initialisms = map[string]string{
+	"api":  "API",
+	"gmt":  "GMT",
+	"guid": "GUID",
+	"html": "HTML",
+	"http": "HTTP",
+	"id":   "ID",
+	"ip":   "IP",
+	"json": "JSON",
+	"uri":  "URI",
+	"url":  "URL",
+	"urls": "URLs",
+}
+
+// resource is a top-level route of the namespace that gets an entity struct and a collection wrapper
+type resource struct {
+	Path       string
+	Collection string
+	Entity     string
+	Schema     *wordpress.Schema
+
+	List   bool
+	Create bool
+	Get    bool
+	Update bool
+	Delete bool
+
+	// Keyed is true for routes addressed by slug (types, statuses, taxonomies) which list entities as a map
+	Keyed bool
+}
+
+func (r resource) KeyType() string {
+	if r.Keyed {
+		return "string"
+	}
+	return "int"
+}
+
+type generator struct {
+	pkg       string
+	namespace string
+	buf       bytes.Buffer
+
+	// names are the identifiers declared so far, which nested structs must not reuse
+	names map[string]bool
+
+	// warnf reports problems with the index that the generated source works around
+	warnf func(format string, args ...interface{})
+}
+
+func newGenerator(pkg string, namespace string) *generator {
+	return &generator{
+		pkg:       pkg,
+		namespace: strings.Trim(namespace, "/"),
+		names:     map[string]bool{},
+		warnf:     log.Printf,
+	}
+}
+
+// uniqueName declares an identifier based on name, numbered if the name is taken, for eg. `PostTitle2`
+func (g *generator) uniqueName(name string) string {
+	unique := name
+	for i := 2; g.names[unique]; i++ {
+		unique = fmt.Sprintf("%v%v", name, i)
+	}
+	g.names[unique] = true
+	return unique
+}
+
+// Generate returns the gofmt-ed source for every resource with a schema in the given index
+func (g *generator) Generate(index *wordpress.Index) ([]byte, error) {
+	resources := g.resources(index)
+	if len(resources) == 0 {
+		return nil, fmt.Errorf("no routes with a schema found in namespace %q", g.namespace)
+	}
+
+	fmt.Fprintf(&g.buf, "// Code generated by wpgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&g.buf, "package %v\n\n", g.pkg)
+	fmt.Fprintf(&g.buf, "import (\n\t\"fmt\"\n\t\"github.com/sogko/go-wordpress\"\n\t\"net/http\"\n)\n\n")
+
+	// entities and collections keep their names; nested structs are named after them
+	for _, r := range resources {
+		g.names[r.Collection] = true
+		g.names["New"+r.Collection] = true
+	}
+	for i, r := range resources {
+		if !g.names[r.Entity] {
+			g.names[r.Entity] = true
+			continue
+		}
+		// a route sorted earlier has a schema with the same title but possibly other properties, e.g. a plugin's `post`
+		resources[i].Entity = g.uniqueName(exportedName(r.Path))
+		g.warnf("wpgen: %v has the same schema title as another route, its entity is named %v instead of %v",
+			r.Path, resources[i].Entity, r.Entity)
+	}
+	for _, r := range resources {
+		g.emitStruct(r.Entity, r.Schema.Properties)
+		if err := collectionTemplate.Execute(&g.buf, r); err != nil {
+			return nil, err
+		}
+	}
+
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return g.buf.Bytes(), fmt.Errorf("failed to format generated source: %v", err)
+	}
+	return src, nil
+}
+
+// resources picks the top-level collection routes of the namespace, e.g. `/wp/v2/posts`,
+// and pairs each one with its item route, e.g. `/wp/v2/posts/(?P<id>[\d]+)`.
+func (g *generator) resources(index *wordpress.Index) []resource {
+	prefix := fmt.Sprintf("/%v/", g.namespace)
+
+	var paths []string
+	for path := range index.Routes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var resources []resource
+	for _, path := range paths {
+		if !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		rel := strings.TrimPrefix(path, prefix)
+		if rel == "" || strings.ContainsAny(rel, "/(") {
+			continue
+		}
+		route := index.Routes[path]
+		r := resource{
+			Path:       rel,
+			Collection: exportedName(rel) + "Collection",
+			Schema:     route.Schema,
+		}
+		methods := routeMethods(route)
+		r.List = methods["GET"]
+		r.Create = methods["POST"]
+
+		if itemPath, item, ok := findItemRoute(index, path); ok {
+			itemMethods := routeMethods(item)
+			r.Get = itemMethods["GET"]
+			r.Update = itemMethods["POST"] || itemMethods["PUT"] || itemMethods["PATCH"]
+			r.Delete = itemMethods["DELETE"]
+			r.Keyed = !strings.Contains(itemPath, `\d`)
+			if r.Schema == nil {
+				r.Schema = item.Schema
+			}
+		}
+		if r.Schema == nil || len(r.Schema.Properties) == 0 || !(r.List || r.Get) {
+			continue
+		}
+		r.Entity = exportedName(r.Schema.Title)
+		if r.Entity == "" {
+			r.Entity = exportedName(rel)
+		}
+		resources = append(resources, r)
+	}
+	return resources
+}
+
+// findItemRoute returns the route addressing a single entity of the collection at path
+func findItemRoute(index *wordpress.Index, path string) (string, wordpress.Route, bool) {
+	for itemPath, route := range index.Routes {
+		rest := strings.TrimPrefix(itemPath, path+"/(?P<")
+		if rest == itemPath {
+			continue
+		}
+		// the named group must be the last path segment
+		if end := strings.Index(rest, ")"); end >= 0 && end == len(rest)-1 {
+			return itemPath, route, true
+		}
+	}
+	return "", wordpress.Route{}, false
+}
+
+func routeMethods(route wordpress.Route) map[string]bool {
+	methods := map[string]bool{}
+	for _, m := range route.Methods {
+		methods[m] = true
+	}
+	for _, endpoint := range route.Endpoints {
+		for _, m := range endpoint.Methods {
+			methods[m] = true
+		}
+	}
+	return methods
+}
+
+type field struct {
+	Name    string
+	JSON    string
+	Type    string
+	Comment string
+}
+
+// optional reports whether a property is sent only when set: writable booleans and numbers are pointers,
+// so that false and 0 can be sent too
+func optional(prop wordpress.SchemaProperty, typeName string) bool {
+	return !prop.ReadOnly && (typeName == "bool" || typeName == "int" || typeName == "float64")
+}
+
+func (g *generator) emitStruct(name string, properties map[string]wordpress.SchemaProperty) {
+	var keys []string
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		// keep `id` first, like the hand-written entities
+		if keys[i] == "id" || keys[j] == "id" {
+			return keys[i] == "id"
+		}
+		return keys[i] < keys[j]
+	})
+
+	var fields []field
+	used := map[string]bool{}
+	var nested []func()
+	for _, key := range keys {
+		prop := properties[key]
+		fieldName := exportedName(key)
+		for used[fieldName] {
+			fieldName += "_"
+		}
+		used[fieldName] = true
+
+		typeName, pending := g.goType(prop, name+fieldName)
+		if pending != nil {
+			nested = append(nested, pending)
+		}
+		if optional(prop, typeName) {
+			typeName = "*" + typeName
+		}
+		fields = append(fields, field{
+			Name:    fieldName,
+			JSON:    key,
+			Type:    typeName,
+			Comment: strings.TrimSpace(strings.Replace(prop.Description, "\n", " ", -1)),
+		})
+	}
+
+	fmt.Fprintf(&g.buf, "type %v struct {\n", name)
+	for _, f := range fields {
+		if f.Comment != "" {
+			fmt.Fprintf(&g.buf, "\t// %v\n", f.Comment)
+		}
+		fmt.Fprintf(&g.buf, "\t%v %v `json:\"%v,omitempty\"`\n", f.Name, f.Type, f.JSON)
+	}
+	fmt.Fprintf(&g.buf, "}\n\n")
+
+	for _, emit := range nested {
+		emit()
+	}
+}
+
+// goType maps a schema property onto a Go type.
+// Object properties with known fields become a struct named after name (numbered if the name is taken),
+// which is emitted by the returned func.
+func (g *generator) goType(prop wordpress.SchemaProperty, name string) (string, func()) {
+	var types []string
+	for _, t := range prop.Type {
+		if t != "null" {
+			types = append(types, t)
+		}
+	}
+	if len(types) != 1 {
+		return "interface{}", nil
+	}
+	switch types[0] {
+	case "integer":
+		return "int", nil
+	case "number":
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "string":
+		return "string", nil
+	case "array":
+		if prop.Items == nil {
+			return "[]interface{}", nil
+		}
+		itemType, pending := g.goType(*prop.Items, name+"Item")
+		return "[]" + itemType, pending
+	case "object":
+		if len(prop.Properties) == 0 {
+			return "map[string]interface{}", nil
+		}
+		name = g.uniqueName(name)
+		return name, func() {
+			g.emitStruct(name, prop.Properties)
+		}
+	}
+	return "interface{}", nil
+}
+
+// exportedName converts a snake_case, kebab-case or dashed route name into an exported Go identifier
+func exportedName(s string) string {
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var name string
+	for _, part := range parts {
+		if upper, ok := initialisms[strings.ToLower(part)]; ok {
+			name += upper
+			continue
+		}
+		first, size := utf8.DecodeRuneInString(part)
+		name += string(unicode.ToUpper(first)) + part[size:]
+	}
+	// names starting with a digit, or with a letter without an upper case, are not exported identifiers
+	if first, _ := utf8.DecodeRuneInString(name); name != "" && !unicode.IsUpper(first) {
+		name = "Field" + name
+	}
+	return name
+}
+
+var collectionTemplate = template.Must(template.New("collection").Parse(`
+type {{.Collection}} struct {
+	client *wordpress.Client
+	url    string
+}
+
+func New{{.Collection}}(client *wordpress.Client, baseAPIURL string) *{{.Collection}} {
+	return &{{.Collection}}{
+		client: client,
+		url:    fmt.Sprintf("%v/%v", baseAPIURL, "{{.Path}}"),
+	}
+}
+{{if .List}}
+func (col *{{.Collection}}) List(params interface{}) ({{if .Keyed}}map[string]{{else}}[]{{end}}{{.Entity}}, *http.Response, []byte, error) {
+	var entities {{if .Keyed}}map[string]{{else}}[]{{end}}{{.Entity}}
+	resp, body, err := col.client.List(col.url, params, &entities)
+	return entities, resp, body, err
+}
+{{- end}}
+{{if .Create}}
+func (col *{{.Collection}}) Create(new *{{.Entity}}) (*{{.Entity}}, *http.Response, []byte, error) {
+	var created {{.Entity}}
+	resp, body, err := col.client.Create(col.url, new, &created)
+	return &created, resp, body, err
+}
+{{- end}}
+{{if .Get}}
+func (col *{{.Collection}}) Get(id {{.KeyType}}, params interface{}) (*{{.Entity}}, *http.Response, []byte, error) {
+	var entity {{.Entity}}
+	entityURL := fmt.Sprintf("%v/%v", col.url, id)
+	resp, body, err := col.client.Get(entityURL, params, &entity)
+	return &entity, resp, body, err
+}
+{{- end}}
+{{if .Update}}
+func (col *{{.Collection}}) Update(id {{.KeyType}}, entity *{{.Entity}}) (*{{.Entity}}, *http.Response, []byte, error) {
+	var updated {{.Entity}}
+	entityURL := fmt.Sprintf("%v/%v", col.url, id)
+	resp, body, err := col.client.Update(entityURL, entity, &updated)
+	return &updated, resp, body, err
+}
+{{- end}}
+{{if .Delete}}
+func (col *{{.Collection}}) Delete(id {{.KeyType}}, params interface{}) (*{{.Entity}}, *http.Response, []byte, error) {
+	var deleted {{.Entity}}
+	entityURL := fmt.Sprintf("%v/%v", col.url, id)
+	resp, body, err := col.client.Delete(entityURL, params, &deleted)
+	return &deleted, resp, body, err
+}
+{{- end}}
+`))
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/sogko/go-wordpress"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+)
+
+const testIndex = `{
+  "namespace": "wp/v2",
+  "routes": {
+    "/wp/v2": {"namespace": "wp/v2", "methods": ["GET"]},
+    "/wp/v2/posts": {
+      "namespace": "wp/v2",
+      "methods": ["GET", "POST"],
+      "schema": {
+        "title": "post",
+        "type": "object",
+        "properties": {
+          "id": {"type": "integer", "readonly": true},
+          "date_gmt": {"type": ["string", "null"], "format": "date-time"},
+          "title": {
+            "type": "object",
+            "properties": {
+              "raw": {"type": "string"},
+              "rendered": {"type": "string"}
+            }
+          },
+          "sticky": {"type": "boolean"},
+          "categories": {"type": "array", "items": {"type": "integer"}},
+          "meta": {"type": "object"},
+          "meta_box": {"type": "object", "properties": {"open": {"type": "boolean"}}},
+          "acf_rating": {"type": "number", "description": "Registered by a plugin"}
+        }
+      }
+    },
+    "/wp/v2/posts/(?P<id>[\\d]+)": {"namespace": "wp/v2", "methods": ["GET", "POST", "PUT", "PATCH", "DELETE"]},
+    "/wp/v2/posts/(?P<parent>[\\d]+)/revisions": {"namespace": "wp/v2", "methods": ["GET"]},
+    "/wp/v2/types": {
+      "namespace": "wp/v2",
+      "methods": ["GET"],
+      "schema": {
+        "title": "type",
+        "type": "object",
+        "properties": {
+          "slug": {"type": "string"},
+          "hierarchical": {"type": "boolean"}
+        }
+      }
+    },
+    "/wp/v2/types/(?P<type>[\\w-]+)": {"namespace": "wp/v2", "methods": ["GET"]},
+    "/wp/v2/post-meta": {
+      "namespace": "wp/v2",
+      "methods": ["GET"],
+      "schema": {
+        "title": "post_meta",
+        "type": "object",
+        "properties": {
+          "id": {"type": "integer", "readonly": true},
+          "box": {"type": "object", "properties": {"closed": {"type": "boolean"}}}
+        }
+      }
+    },
+    "/oembed/1.0/embed": {"namespace": "oembed/1.0", "methods": ["GET"]}
+  }
+}`
+
+func generateTestIndex(t *testing.T) string {
+	var index wordpress.Index
+	if err := json.Unmarshal([]byte(testIndex), &index); err != nil {
+		t.Fatalf("Failed to parse test index: %v", err.Error())
+	}
+	src, err := newGenerator("example", "wp/v2").Generate(&index)
+	if err != nil {
+		t.Fatalf("Should not return error: %v\n%s", err.Error(), src)
+	}
+	return string(src)
+}
+
+func TestGenerate_EntityStructs(t *testing.T) {
+	src := generateTestIndex(t)
+
+	expected := []string{
+		"package example",
+		"type Post struct {",
+		"ID int `json:\"id,omitempty\"`",
+		"DateGMT string `json:\"date_gmt,omitempty\"`",
+		"Title PostTitle `json:\"title,omitempty\"`",
+		"Sticky *bool `json:\"sticky,omitempty\"`",
+		"type PostTitle struct {",
+		"Categories []int `json:\"categories,omitempty\"`",
+		"Meta map[string]interface{} `json:\"meta,omitempty\"`",
+		"// Registered by a plugin",
+		"AcfRating *float64 `json:\"acf_rating,omitempty\"`",
+		"type Type struct {",
+	}
+	for _, e := range expected {
+		if !strings.Contains(normalizeSpaces(src), normalizeSpaces(e)) {
+			t.Errorf("Generated source should contain %q\n%v", e, src)
+		}
+	}
+}
+
+func TestGenerate_UniqueNames(t *testing.T) {
+	src := generateTestIndex(t)
+
+	// `PostMeta` is an entity, whose Box field is generated first and takes `PostMetaBox` from Post.MetaBox
+	expected := []string{
+		"type PostMeta struct {",
+		"Box PostMetaBox `json:\"box,omitempty\"`",
+		"type PostMetaBox struct {",
+		"Closed *bool `json:\"closed,omitempty\"`",
+		"MetaBox PostMetaBox2 `json:\"meta_box,omitempty\"`",
+		"type PostMetaBox2 struct {",
+		"Open *bool `json:\"open,omitempty\"`",
+	}
+	for _, e := range expected {
+		if !strings.Contains(normalizeSpaces(src), normalizeSpaces(e)) {
+			t.Errorf("Generated source should contain %q\n%v", e, src)
+		}
+	}
+}
+
+func TestGenerate_SameTitle(t *testing.T) {
+	var index wordpress.Index
+	if err := json.Unmarshal([]byte(testIndex), &index); err != nil {
+		t.Fatalf("Failed to parse test index: %v", err.Error())
+	}
+	// a plugin route reusing the title of the posts schema, with other properties
+	index.Routes["/wp/v2/posts-legacy"] = wordpress.Route{
+		Namespace: "wp/v2",
+		Methods:   []string{"GET"},
+		Schema: &wordpress.Schema{
+			Title: "post",
+			Type:  wordpress.SchemaTypes{"object"},
+			Properties: map[string]wordpress.SchemaProperty{
+				"legacy_id": {Type: wordpress.SchemaTypes{"integer"}},
+			},
+		},
+	}
+	g := newGenerator("example", "wp/v2")
+	var warnings []string
+	g.warnf = func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+	src, err := g.Generate(&index)
+	if err != nil {
+		t.Fatalf("Should not return error: %v\n%s", err.Error(), src)
+	}
+
+	expected := []string{
+		"type Post struct {",
+		"type PostsLegacy struct {",
+		"LegacyID *int `json:\"legacy_id,omitempty\"`",
+		"func (col *PostsLegacyCollection) List(params interface{}) ([]PostsLegacy, *http.Response, []byte, error) {",
+	}
+	for _, e := range expected {
+		if !strings.Contains(normalizeSpaces(string(src)), normalizeSpaces(e)) {
+			t.Errorf("Generated source should contain %q\n%s", e, src)
+		}
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "posts-legacy") {
+		t.Errorf("Expected a warning about posts-legacy, got %v", warnings)
+	}
+}
+
+// TestGenerate_TypeCheck compiles the generated source against the wordpress package
+func TestGenerate_TypeCheck(t *testing.T) {
+	src := generateTestIndex(t)
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "types.go", src, 0)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check("example", fset, []*ast.File{file}, nil); err != nil {
+		t.Errorf("Generated source should compile: %v\n%v", err, src)
+	}
+}
+
+func TestGenerate_Collections(t *testing.T) {
+	src := generateTestIndex(t)
+
+	expected := []string{
+		"func NewPostsCollection(client *wordpress.Client, baseAPIURL string) *PostsCollection",
+		"func (col *PostsCollection) List(params interface{}) ([]Post, *http.Response, []byte, error)",
+		"func (col *PostsCollection) Create(new *Post) (*Post, *http.Response, []byte, error)",
+		"func (col *PostsCollection) Get(id int, params interface{}) (*Post, *http.Response, []byte, error)",
+		"func (col *PostsCollection) Update(id int, entity *Post) (*Post, *http.Response, []byte, error)",
+		"func (col *PostsCollection) Delete(id int, params interface{}) (*Post, *http.Response, []byte, error)",
+		"func (col *TypesCollection) List(params interface{}) (map[string]Type, *http.Response, []byte, error)",
+		"func (col *TypesCollection) Get(id string, params interface{}) (*Type, *http.Response, []byte, error)",
+	}
+	for _, e := range expected {
+		if !strings.Contains(src, e) {
+			t.Errorf("Generated source should contain %q\n%v", e, src)
+		}
+	}
+	if strings.Contains(src, "func (col *TypesCollection) Create") {
+		t.Errorf("Read-only route should not have a Create method")
+	}
+	if strings.Contains(src, "Revisions") {
+		t.Errorf("Sub-routes should not be generated")
+	}
+}
+
+func TestExportedName(t *testing.T) {
+	cases := map[string]string{
+		"id":             "ID",
+		"date_gmt":       "DateGMT",
+		"source_url":     "SourceURL",
+		"avatar_urls":    "AvatarURLs",
+		"wp_block":       "WpBlock",
+		"post-thumbnail": "PostThumbnail",
+		"1536x1536":      "Field1536x1536",
+		"über_größe":     "ÜberGröße",
+		"émission":       "Émission",
+		"文章":             "Field文章",
+	}
+	for in, out := range cases {
+		if name := exportedName(in); name != out {
+			t.Errorf("exportedName(%q) should be %v, got %v", in, out, name)
+		}
+	}
+}
+
+func normalizeSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
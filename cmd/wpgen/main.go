@@ -0,0 +1,116 @@
+// Command wpgen generates Go entity structs and collection wrappers from a site's WP-API schemas.
+//
+// The schemas are read either from a live site:
+//
+//	wpgen -url http://example.com/wp-json -user admin -password secret -o types.go
+//
+// or from an index previously saved with `-save`, so types can be regenerated offline:
+//
+//	wpgen -url http://example.com/wp-json -save example.com.json
+//	wpgen -file example.com.json -package example -o types.go
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/sogko/go-wordpress"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+)
+
+func main() {
+	var (
+		rootURL   = flag.String("url", "", "API root URL of the site, e.g. http://example.com/wp-json")
+		username  = flag.String("user", "", "username for basic auth")
+		password  = flag.String("password", "", "password for basic auth")
+		file      = flag.String("file", "", "read the API index from a saved JSON file instead of the site")
+		save      = flag.String("save", "", "save the fetched API index (including schemas) to this JSON file")
+		namespace = flag.String("namespace", "wp/v2", "namespace of the routes to generate")
+		pkg       = flag.String("package", "wp", "package name of the generated file")
+		output    = flag.String("o", "", "output file (default: stdout)")
+	)
+	flag.Parse()
+
+	var index *wordpress.Index
+	var err error
+	switch {
+	case *file != "":
+		index, err = readIndex(*file)
+	case *rootURL != "":
+		index, err = fetchIndex(*rootURL, *username, *password, *namespace)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("wpgen: %v", err)
+	}
+
+	if *save != "" {
+		data, err := json.MarshalIndent(index, "", "  ")
+		if err != nil {
+			log.Fatalf("wpgen: %v", err)
+		}
+		if err := ioutil.WriteFile(*save, data, 0644); err != nil {
+			log.Fatalf("wpgen: %v", err)
+		}
+	}
+
+	src, err := newGenerator(*pkg, *namespace).Generate(index)
+	if err != nil {
+		log.Fatalf("wpgen: %v", err)
+	}
+	if *output == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := ioutil.WriteFile(*output, src, 0644); err != nil {
+		log.Fatalf("wpgen: %v", err)
+	}
+}
+
+func readIndex(path string) (*wordpress.Index, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var index wordpress.Index
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse %v: %v", path, err)
+	}
+	return &index, nil
+}
+
+// fetchIndex reads the API index with `context=help`, then fills in the schema of any route in the namespace
+// the index left out by requesting the route itself.
+func fetchIndex(rootURL string, username string, password string, namespace string) (*wordpress.Index, error) {
+	rootURL = strings.TrimRight(rootURL, "/")
+	client := wordpress.NewClient(&wordpress.Options{
+		BaseAPIURL: rootURL,
+		Username:   username,
+		Password:   password,
+	})
+
+	index, _, _, err := client.Index("context=help")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch API index from %v: %v", rootURL, err)
+	}
+
+	prefix := fmt.Sprintf("/%v/", strings.Trim(namespace, "/"))
+	for path, route := range index.Routes {
+		if route.Schema != nil || !strings.HasPrefix(path, prefix) || strings.Contains(path, "(") {
+			continue
+		}
+		described, _, _, err := client.Route(rootURL + path)
+		if err != nil {
+			log.Printf("wpgen: skipping %v: %v", path, err)
+			continue
+		}
+		route.Schema = described.Schema
+		index.Routes[path] = route
+	}
+	return index, nil
+}
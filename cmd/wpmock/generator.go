@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// mock is a mock implementation of an interface of package wordpress
+type mock struct {
+	Name    string
+	Methods []method
+}
+
+type method struct {
+	Name string
+
+	// Params declares the parameters, e.g. `id int, params interface{}`, and Args passes them on, e.g. `id, params`
+	Params string
+	Args   string
+
+	// Recorded lists the arguments as recorded, with variadic parameters recorded as a single slice
+	Recorded string
+
+	// Results lists the result types, e.g. `(*wordpress.Post, error)`, and NamedResults names them, e.g. `(r0 *wordpress.Post, r1 error)`
+	Results      string
+	NamedResults string
+
+	// Service is set for accessors returning another interface of the package, e.g. `Tag() TermsTaxonomyService`;
+	// their mock returns a mock of that interface by default.
+	Service string
+}
+
+// Generate returns the gofmt-ed source of the mocks of every interface declared in src
+func Generate(pkg string, filename string, src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	interfaces := map[string]*ast.InterfaceType{}
+	var names []string
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			if iface, ok := typeSpec.Type.(*ast.InterfaceType); ok && typeSpec.Name.IsExported() {
+				interfaces[typeSpec.Name.Name] = iface
+				names = append(names, typeSpec.Name.Name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no interfaces found in %v", filename)
+	}
+
+	var mocks []mock
+	for _, name := range names {
+		m := mock{Name: name}
+		for _, field := range interfaces[name].Methods.List {
+			fn, ok := field.Type.(*ast.FuncType)
+			if !ok {
+				return nil, fmt.Errorf("%v: embedded interfaces are not supported", name)
+			}
+			for _, ident := range field.Names {
+				m.Methods = append(m.Methods, newMethod(fset, ident.Name, fn, interfaces))
+			}
+		}
+		mocks = append(mocks, m)
+	}
+
+	// the mocks import the package, and the imports of src its interfaces use
+	imports := []string{`"github.com/sogko/go-wordpress"`}
+	for _, spec := range file.Imports {
+		name, decl := path.Base(strings.Trim(spec.Path.Value, `"`)), spec.Path.Value
+		if spec.Name != nil {
+			name, decl = spec.Name.Name, spec.Name.Name+" "+spec.Path.Value
+		}
+		if bytes.Contains(src, []byte(name+".")) {
+			imports = append(imports, decl)
+		}
+	}
+	sort.Strings(imports)
+
+	var buf bytes.Buffer
+	data := struct {
+		Package string
+		Mocks   []mock
+		Imports []string
+	}{pkg, mocks, imports}
+	if err := mocksTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return buf.Bytes(), fmt.Errorf("failed to format generated source: %v", err)
+	}
+	return out, nil
+}
+
+func newMethod(fset *token.FileSet, name string, fn *ast.FuncType, interfaces map[string]*ast.InterfaceType) method {
+	m := method{Name: name}
+
+	var params, args, recorded []string
+	for i, field := range fn.Params.List {
+		typ := typeString(fset, qualify(field.Type))
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{ast.NewIdent(fmt.Sprintf("a%v", i))}
+		}
+		for _, ident := range names {
+			params = append(params, fmt.Sprintf("%v %v", ident.Name, typ))
+			recorded = append(recorded, ident.Name)
+			if _, ok := field.Type.(*ast.Ellipsis); ok {
+				args = append(args, ident.Name+"...")
+			} else {
+				args = append(args, ident.Name)
+			}
+		}
+	}
+	m.Params = strings.Join(params, ", ")
+	m.Args = strings.Join(args, ", ")
+	m.Recorded = strings.Join(recorded, ", ")
+
+	var results, named []string
+	if fn.Results != nil {
+		for _, field := range fn.Results.List {
+			count := len(field.Names)
+			if count == 0 {
+				count = 1
+			}
+			for i := 0; i < count; i++ {
+				typ := typeString(fset, qualify(field.Type))
+				named = append(named, fmt.Sprintf("r%v %v", len(results), typ))
+				results = append(results, typ)
+			}
+		}
+		if len(results) == 1 {
+			if ident, ok := fn.Results.List[0].Type.(*ast.Ident); ok && interfaces[ident.Name] != nil && len(params) == 0 {
+				m.Service = ident.Name
+			}
+		}
+	}
+	m.Results = "(" + strings.Join(results, ", ") + ")"
+	m.NamedResults = "(" + strings.Join(named, ", ") + ")"
+	return m
+}
+
+// qualify returns a copy of the type expression with the exported identifiers of package wordpress
+// prefixed with the package name
+func qualify(expr ast.Expr) ast.Expr {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if t.IsExported() {
+			return &ast.SelectorExpr{X: ast.NewIdent("wordpress"), Sel: ast.NewIdent(t.Name)}
+		}
+		return t
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: qualify(t.X)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Len: t.Len, Elt: qualify(t.Elt)}
+	case *ast.MapType:
+		return &ast.MapType{Key: qualify(t.Key), Value: qualify(t.Value)}
+	case *ast.Ellipsis:
+		return &ast.Ellipsis{Elt: qualify(t.Elt)}
+	case *ast.ChanType:
+		return &ast.ChanType{Dir: t.Dir, Value: qualify(t.Value)}
+	}
+	// selectors (e.g. http.Response), interface{} and func types are used as is
+	return expr
+}
+
+func typeString(fset *token.FileSet, expr ast.Expr) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, fset, expr)
+	return buf.String()
+}
+
+var mocksTemplate = template.Must(template.New("mocks").Parse(`// Code generated by wpmock. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .Imports}}
+	{{.}}
+{{- end}}
+)
+{{range $mock := .Mocks}}
+// {{.Name}} is a mock of wordpress.{{.Name}}
+type {{.Name}} struct {
+	Mock
+{{range .Methods}}
+	{{.Name}}Func func({{.Params}}) {{.Results}}
+{{- if .Service}}
+	{{.Name}}Mock *{{.Service}}
+{{- end}}
+{{- end}}
+}
+
+var _ wordpress.{{.Name}} = (*{{.Name}})(nil)
+{{range .Methods}}
+func (m *{{$mock.Name}}) {{.Name}}({{.Params}}) {{.NamedResults}} {
+	m.record("{{.Name}}"{{if .Recorded}}, {{.Recorded}}{{end}})
+	if m.{{.Name}}Func != nil {
+		return m.{{.Name}}Func({{.Args}})
+	}
+{{- if .Service}}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.{{.Name}}Mock == nil {
+		m.{{.Name}}Mock = &{{.Service}}{}
+	}
+	return m.{{.Name}}Mock
+{{- else}}
+	return
+{{- end}}
+}
+{{end}}
+{{- end}}
+`))
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+const testSource = `package wordpress
+
+import (
+	"net/http"
+)
+
+type ThingsService interface {
+	List(params interface{}) ([]Thing, *http.Response, []byte, error)
+	Tag(names ...string) error
+	Children() ThingsService
+}
+
+type unexported interface {
+	Ignored()
+}
+`
+
+func TestGenerate(t *testing.T) {
+	src, err := Generate("mocks", "things.go", []byte(testSource))
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	for _, expected := range []string{
+		"package mocks",
+		"type ThingsService struct {",
+		"ListFunc     func(params interface{}) ([]wordpress.Thing, *http.Response, []byte, error)",
+		"ChildrenMock *ThingsService",
+		`m.record("Tag", names)`,
+		"return m.TagFunc(names...)",
+		"func (m *ThingsService) Children() (r0 wordpress.ThingsService) {",
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("Expected generated source to contain %q, got:\n%s", expected, src)
+		}
+	}
+	if strings.Contains(string(src), "Ignored") {
+		t.Errorf("Unexported interfaces should not be mocked")
+	}
+}
+
+func TestGenerate_UpToDate(t *testing.T) {
+	services, err := ioutil.ReadFile("../../services.go")
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	src, err := Generate("wpmock", "services.go", services)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	mocks, err := ioutil.ReadFile("../../wpmock/mocks.go")
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if !bytes.Equal(src, mocks) {
+		t.Errorf("wpmock/mocks.go is out of date, run `go generate` in the root of the repository")
+	}
+}
@@ -0,0 +1,38 @@
+// Command wpmock generates the mocks of package wpmock from the service interfaces of package wordpress.
+//
+// It is run with `go generate` from the root of the repository:
+//
+//	wpmock -src services.go -package wpmock -o wpmock/mocks.go
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+)
+
+func main() {
+	var (
+		src    = flag.String("src", "services.go", "Go file declaring the interfaces to mock")
+		pkg    = flag.String("package", "wpmock", "package name of the generated file")
+		output = flag.String("o", "", "output file (default: stdout)")
+	)
+	flag.Parse()
+
+	data, err := ioutil.ReadFile(*src)
+	if err != nil {
+		log.Fatalf("wpmock: %v", err)
+	}
+	out, err := Generate(*pkg, *src, data)
+	if err != nil {
+		log.Fatalf("wpmock: %v", err)
+	}
+	if *output == "" {
+		os.Stdout.Write(out)
+		return
+	}
+	if err := ioutil.WriteFile(*output, out, 0644); err != nil {
+		log.Fatalf("wpmock: %v", err)
+	}
+}
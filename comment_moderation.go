@@ -0,0 +1,176 @@
+package wordpress
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+)
+
+// CommentAction is a moderation action, applied with CommentsCollection.Moderate
+type CommentAction string
+
+const (
+	CommentApprove   CommentAction = "approve"
+	CommentUnapprove CommentAction = "unapprove"
+	CommentSpam      CommentAction = "spam"
+	CommentTrash     CommentAction = "trash"
+
+	// CommentRestore takes a comment out of the trash or spam, back to its status before
+	CommentRestore CommentAction = "restore"
+)
+
+// CommentFilter selects the comments to moderate in bulk
+type CommentFilter struct {
+	// Status is the status of the comments to list, CommentStatusHold (awaiting moderation) by default
+	Status CommentStatus
+
+	// Params filters the comments listed, for eg. "post=12" or "search=casino"
+	Params string
+
+	// AuthorIP, if set, only selects the comments from that IP address
+	AuthorIP string
+
+	// Pattern, if set, only selects the comments whose author name, email, URL, user agent or raw content match it
+	Pattern *regexp.Regexp
+}
+
+// Match returns true if the comment matches the AuthorIP and Pattern of the filter
+func (filter *CommentFilter) Match(comment *Comment) bool {
+	if filter.AuthorIP != "" && comment.AuthorIP != filter.AuthorIP {
+		return false
+	}
+	if filter.Pattern != nil {
+		for _, field := range []string{comment.AuthorName, comment.AuthorEmail, comment.AuthorURL, comment.AuthorUserAgent, comment.Content.Raw} {
+			if filter.Pattern.MatchString(field) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// ModerationResult is the outcome of a moderation action on a comment.
+// Comment is the comment as updated, or as it was listed if the action failed with Err.
+type ModerationResult struct {
+	Comment Comment
+	Action  CommentAction
+	Err     error
+}
+
+// Approve approves a comment
+func (col *CommentsCollection) Approve(id int) (*Comment, *http.Response, []byte, error) {
+	return col.setStatus(id, CommentStatusApproved)
+}
+
+// Unapprove holds a comment for moderation
+func (col *CommentsCollection) Unapprove(id int) (*Comment, *http.Response, []byte, error) {
+	return col.setStatus(id, string(CommentStatusHold))
+}
+
+// Spam marks a comment as spam
+func (col *CommentsCollection) Spam(id int) (*Comment, *http.Response, []byte, error) {
+	return col.setStatus(id, string(CommentStatusSpam))
+}
+
+// Trash moves a comment to the trash; use Delete() with "force=true" to delete it permanently
+func (col *CommentsCollection) Trash(id int) (*Comment, *http.Response, []byte, error) {
+	return col.setStatus(id, string(CommentStatusTrash))
+}
+
+// Restore takes a comment out of the trash or spam; WordPress restores the status it had before
+func (col *CommentsCollection) Restore(id int) (*Comment, *http.Response, []byte, error) {
+	comment, resp, body, err := col.Get(id, "context=edit")
+	if err != nil {
+		return comment, resp, body, err
+	}
+	return col.restore(comment)
+}
+
+func (col *CommentsCollection) restore(comment *Comment) (*Comment, *http.Response, []byte, error) {
+	switch CommentStatus(comment.Status) {
+	case CommentStatusTrash:
+		return col.setStatus(comment.ID, "untrash")
+	case CommentStatusSpam:
+		return col.setStatus(comment.ID, "unspam")
+	}
+	return comment, nil, nil, fmt.Errorf("comment %v is neither in the trash nor spam", comment.ID)
+}
+
+// Moderate applies a moderation action to a comment
+func (col *CommentsCollection) Moderate(id int, action CommentAction) (*Comment, *http.Response, []byte, error) {
+	switch action {
+	case CommentApprove:
+		return col.Approve(id)
+	case CommentUnapprove:
+		return col.Unapprove(id)
+	case CommentSpam:
+		return col.Spam(id)
+	case CommentTrash:
+		return col.Trash(id)
+	case CommentRestore:
+		return col.Restore(id)
+	}
+	return nil, nil, nil, fmt.Errorf("unknown comment action %q", action)
+}
+
+// BulkModerate applies a moderation action to every comment selected by the filter, across all pages.
+// An error is only returned if the comments cannot be listed; failures of the action are reported per comment.
+func (col *CommentsCollection) BulkModerate(filter *CommentFilter, action CommentAction) ([]ModerationResult, error) {
+	comments, err := col.listFiltered(filter)
+	if err != nil {
+		return nil, err
+	}
+	var results []ModerationResult
+	for _, comment := range comments {
+		result := ModerationResult{Comment: comment, Action: action}
+		var moderated *Comment
+		if action == CommentRestore {
+			moderated, _, _, err = col.restore(&comment)
+		} else {
+			moderated, _, _, err = col.Moderate(comment.ID, action)
+		}
+		if err != nil {
+			result.Err = fmt.Errorf("comment %v: %v", comment.ID, err)
+		} else {
+			result.Comment = *moderated
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// listFiltered lists the comments selected by a filter, in the `edit` context
+func (col *CommentsCollection) listFiltered(filter *CommentFilter) ([]Comment, error) {
+	if filter == nil {
+		filter = &CommentFilter{}
+	}
+	status := filter.Status
+	if status == "" {
+		status = CommentStatusHold
+	}
+	query := fmt.Sprintf("context=edit&status=%v", status)
+	if filter.Params != "" {
+		query += "&" + filter.Params
+	}
+	var comments []Comment
+	err := forEachPage(query, func(params string) (*http.Response, int, error) {
+		page, resp, _, err := col.List(params)
+		for _, comment := range page {
+			if filter.Match(&comment) {
+				comments = append(comments, comment)
+			}
+		}
+		return resp, len(page), err
+	})
+	return comments, err
+}
+
+// setStatus updates the status of a comment alone
+func (col *CommentsCollection) setStatus(id int, status string) (*Comment, *http.Response, []byte, error) {
+	var updated Comment
+	entityURL := fmt.Sprintf("%v/%v", col.url, id)
+	resp, body, err := col.client.Update(entityURL, map[string]interface{}{"status": status}, &updated)
+	updated.setCollection(col)
+	return &updated, resp, body, err
+}
@@ -0,0 +1,116 @@
+package wordpress_test
+
+import (
+	"github.com/sogko/go-wordpress"
+	"github.com/sogko/go-wordpress/wptest"
+	"regexp"
+	"testing"
+)
+
+func newModerationTestClient(t *testing.T) (*wptest.Server, *wordpress.Client, []int) {
+	store := newFixtureStore(t)
+	post := store.AddPost(wptest.Object{"type": wptest.PostTypePost, "title": "Moderation", "status": "publish"})
+	var ids []int
+	for _, comment := range []wptest.Object{
+		{"author_ip": "10.0.0.1", "content": "Cheap casino chips"},
+		{"author_ip": "10.0.0.1", "content": "Hello"},
+		{"author_ip": "10.0.0.2", "content": "Nice post", "author_url": "http://casino.example.com"},
+		{"author_ip": "10.0.0.2", "content": "Thanks!"},
+		{"author_ip": "10.0.0.1", "content": "Approved already", "status": "approved"},
+	} {
+		comment["post"] = post.Int("id")
+		if comment["status"] == nil {
+			comment["status"] = wptest.CommentStatusHold
+		}
+		ids = append(ids, store.AddComment(comment).Int("id"))
+	}
+	server := wptest.NewServer(store)
+	return server, newFakeTestClient(server, nil), ids
+}
+
+func TestCommentsModerate(t *testing.T) {
+	server, wp, ids := newModerationTestClient(t)
+	defer server.Close()
+	id := ids[3]
+
+	steps := []struct {
+		action   wordpress.CommentAction
+		expected wordpress.CommentStatus
+	}{
+		{wordpress.CommentSpam, wordpress.CommentStatusSpam},
+		{wordpress.CommentRestore, wordpress.CommentStatusHold},
+		{wordpress.CommentApprove, wordpress.CommentStatusApproved},
+		{wordpress.CommentTrash, wordpress.CommentStatusTrash},
+		{wordpress.CommentRestore, wordpress.CommentStatusApproved},
+		{wordpress.CommentUnapprove, wordpress.CommentStatusHold},
+	}
+	for _, step := range steps {
+		comment, _, _, err := wp.Comments().Moderate(id, step.action)
+		if err != nil {
+			t.Fatalf("%v: should not return error: %v", step.action, err.Error())
+		}
+		if wordpress.CommentStatus(comment.Status) != step.expected {
+			t.Errorf("%v: expected %v, got %v", step.action, step.expected, comment.Status)
+		}
+	}
+	// content is left untouched
+	comment, _, _, _ := wp.Comments().Get(id, "context=edit")
+	if comment.Content.Raw != "Thanks!" {
+		t.Errorf("Expected the content to be kept, got %v", comment.Content.Raw)
+	}
+
+	if _, _, _, err := wp.Comments().Restore(id); err == nil {
+		t.Errorf("Expected an error restoring a comment that is not in the trash")
+	}
+	if _, _, _, err := wp.Comments().Moderate(id, "publish"); err == nil {
+		t.Errorf("Expected an error for an unknown action")
+	}
+}
+
+func TestCommentsBulkModerate(t *testing.T) {
+	server, wp, ids := newModerationTestClient(t)
+	defer server.Close()
+
+	// the pending comments from an IP
+	results, err := wp.Comments().BulkModerate(&wordpress.CommentFilter{AuthorIP: "10.0.0.1"}, wordpress.CommentSpam)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %v", results)
+	}
+	for _, result := range results {
+		if result.Err != nil || wordpress.CommentStatus(result.Comment.Status) != wordpress.CommentStatusSpam || result.Action != wordpress.CommentSpam {
+			t.Errorf("Expected comment %v to be spam, got %v (%v)", result.Comment.ID, result.Comment.Status, result.Err)
+		}
+	}
+	approved, _, _, _ := wp.Comments().Get(ids[4], "context=edit")
+	if approved.Status != wordpress.CommentStatusApproved {
+		t.Errorf("Expected the approved comment to be left alone, got %v", approved.Status)
+	}
+
+	// the spam matching a pattern, in any field
+	results, err = wp.Comments().BulkModerate(&wordpress.CommentFilter{
+		Status:  wordpress.CommentStatusSpam,
+		Pattern: regexp.MustCompile(`(?i)casino`),
+	}, wordpress.CommentRestore)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if len(results) != 1 || results[0].Comment.ID != ids[0] || wordpress.CommentStatus(results[0].Comment.Status) != wordpress.CommentStatusHold {
+		t.Errorf("Expected comment %v to be restored, got %v", ids[0], results)
+	}
+	results, _ = wp.Comments().BulkModerate(&wordpress.CommentFilter{Pattern: regexp.MustCompile(`casino`)}, wordpress.CommentTrash)
+	if len(results) != 2 {
+		t.Errorf("Expected 2 results, got %v", results)
+	}
+
+	// failures are reported per comment
+	results, err = wp.Comments().BulkModerate(nil, wordpress.CommentRestore)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if len(results) != 1 || results[0].Comment.ID != ids[3] || results[0].Err == nil {
+		t.Errorf("Expected restoring a pending comment to fail, got %v", results)
+	}
+}
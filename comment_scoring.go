@@ -0,0 +1,277 @@
+package wordpress
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Score is the rating of a comment by a Scorer: positive for spam signals, negative for legitimate ones
+type Score struct {
+	Value   float64
+	Reasons []string
+}
+
+// Scorer rates comments, for eg. with a classifier
+type Scorer interface {
+	Score(comment *Comment) (Score, error)
+}
+
+// BatchScorer is a Scorer that looks at all the comments of a run before scoring them
+type BatchScorer interface {
+	Scorer
+	Prepare(comments []Comment) error
+}
+
+// ScorerFunc adapts a function to a Scorer
+type ScorerFunc func(comment *Comment) (Score, error)
+
+func (f ScorerFunc) Score(comment *Comment) (Score, error) {
+	return f(comment)
+}
+
+var (
+	anchorElement = regexp.MustCompile(`(?is)<a\s[^>]*>(.*?</a\s*>)?`)
+	anchorHref    = regexp.MustCompile(`(?i)\shref\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+	anyTag        = regexp.MustCompile(`<[^>]*>`)
+	bareURL       = regexp.MustCompile(`(?i)\bhttps?://[^\s<>"']+`)
+)
+
+// RuleScorer is a Scorer with rules like the WordPress discussion settings.
+// NewRuleScorer returns one with the defaults given for each field; zero values are used as they are.
+type RuleScorer struct {
+	// MaxLinks is the number of links a comment may have (2); each extra one scores LinkWeight (1)
+	MaxLinks   int
+	LinkWeight float64
+
+	// Blocklist are words that, found in the content, author name, email, URL, IP or user agent (ignoring case),
+	// score BlocklistWeight (5) each
+	Blocklist       []string
+	BlocklistWeight float64
+
+	// MaxCommentsPerIP is the number of comments of a run an IP address may have sent (3);
+	// each comment from an IP that sent more scores RepeatedIPWeight (2)
+	MaxCommentsPerIP int
+	RepeatedIPWeight float64
+
+	commentsPerIP map[string]int
+}
+
+// NewRuleScorer returns a RuleScorer with the default settings and the given blocklist
+func NewRuleScorer(blocklist ...string) *RuleScorer {
+	return &RuleScorer{
+		MaxLinks:         2,
+		LinkWeight:       1,
+		Blocklist:        blocklist,
+		BlocklistWeight:  5,
+		MaxCommentsPerIP: 3,
+		RepeatedIPWeight: 2,
+	}
+}
+
+// Prepare counts the comments per IP address
+func (scorer *RuleScorer) Prepare(comments []Comment) error {
+	scorer.commentsPerIP = map[string]int{}
+	for _, comment := range comments {
+		if comment.AuthorIP != "" {
+			scorer.commentsPerIP[comment.AuthorIP]++
+		}
+	}
+	return nil
+}
+
+func (scorer *RuleScorer) Score(comment *Comment) (Score, error) {
+	var score Score
+	add := func(value float64, reason string) {
+		score.Value += value
+		score.Reasons = append(score.Reasons, reason)
+	}
+
+	content := comment.Content.Raw
+	if content == "" {
+		content = comment.Content.Rendered
+	}
+	if links := countLinks(content); links > scorer.MaxLinks {
+		add(float64(links-scorer.MaxLinks)*scorer.LinkWeight, fmt.Sprintf("%v links", links))
+	}
+
+	fields := strings.ToLower(strings.Join([]string{content, comment.AuthorName, comment.AuthorEmail, comment.AuthorURL,
+		comment.AuthorIP, comment.AuthorUserAgent}, "\n"))
+	for _, word := range scorer.Blocklist {
+		if word != "" && strings.Contains(fields, strings.ToLower(word)) {
+			add(scorer.BlocklistWeight, fmt.Sprintf("blocklisted word %q", word))
+		}
+	}
+
+	if n := scorer.commentsPerIP[comment.AuthorIP]; n > scorer.MaxCommentsPerIP {
+		add(scorer.RepeatedIPWeight, fmt.Sprintf("%v comments from %v", n, comment.AuthorIP))
+	}
+	return score, nil
+}
+
+// countLinks returns the number of distinct links of a content: the hrefs of its anchors, and the URLs outside
+// anchors. An anchor whose text is its URL, like the links WordPress adds to rendered URLs, counts once.
+func countLinks(content string) int {
+	links := map[string]bool{}
+	outside := anchorElement.ReplaceAllStringFunc(content, func(anchor string) string {
+		if href := anchorHref.FindStringSubmatch(anyTag.FindString(anchor)); href != nil {
+			links[href[1]+href[2]+href[3]] = true
+		}
+		return " "
+	})
+	for _, url := range bareURL.FindAllString(anyTag.ReplaceAllString(outside, " "), -1) {
+		links[url] = true
+	}
+	return len(links)
+}
+
+// ModerationPipeline scores comments with a list of scorers, and approves, holds or marks them as spam
+type ModerationPipeline struct {
+	Scorers []Scorer
+
+	// Filter selects the comments to moderate; by default, those awaiting moderation
+	Filter *CommentFilter
+
+	// Comments scoring SpamThreshold (DefaultSpamThreshold if zero) or more are marked as spam; comments scoring less
+	// than ApproveBelow (0 by default, so only comments with negative scores) are approved; others are held.
+	// RunModeration rejects a SpamThreshold that is not above ApproveBelow.
+	SpamThreshold float64
+	ApproveBelow  float64
+
+	// DryRun, if set, only reports the decisions
+	DryRun bool
+}
+
+// DefaultSpamThreshold is the score from which a ModerationPipeline marks comments as spam, unless it sets another
+const DefaultSpamThreshold = 5
+
+// NewModerationPipeline returns a ModerationPipeline with the given scorers and the default thresholds
+func NewModerationPipeline(scorers ...Scorer) *ModerationPipeline {
+	return &ModerationPipeline{Scorers: scorers, SpamThreshold: DefaultSpamThreshold}
+}
+
+// ScoredComment is the score of a comment, and the decision taken on it
+type ScoredComment struct {
+	Comment  Comment
+	Score    float64
+	Reasons  []string
+	Decision CommentAction
+
+	// Applied is set if the status of the comment was changed; Err is the error scoring the comment or changing
+	// its status, if any (a comment that cannot be scored is left alone)
+	Applied bool
+	Err     error
+}
+
+// ModerationReport is the outcome of a ModerationPipeline run
+type ModerationReport struct {
+	DryRun   bool
+	Comments []ScoredComment
+}
+
+// Count returns the number of comments the decision was taken on
+func (report *ModerationReport) Count(decision CommentAction) int {
+	n := 0
+	for _, scored := range report.Comments {
+		if scored.Err == nil && scored.Decision == decision {
+			n++
+		}
+	}
+	return n
+}
+
+// String formats the report with a line per comment, for eg. `#12 spam (7): blocklisted word "casino", 3 links`
+func (report *ModerationReport) String() string {
+	var lines []string
+	if report.DryRun {
+		lines = append(lines, "dry run, no comment was changed")
+	}
+	for _, scored := range report.Comments {
+		line := fmt.Sprintf("#%v %v (%v)", scored.Comment.ID, scored.Decision, scored.Score)
+		if len(scored.Reasons) > 0 {
+			line += ": " + strings.Join(scored.Reasons, ", ")
+		}
+		if scored.Err != nil {
+			line += fmt.Sprintf(" [error: %v]", scored.Err)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, fmt.Sprintf("%v approved, %v held, %v spam", report.Count(CommentApprove), report.Count(CommentUnapprove), report.Count(CommentSpam)))
+	return strings.Join(lines, "\n")
+}
+
+// RunModeration runs a moderation pipeline over the comments selected by its filter, across all pages.
+// An error is only returned if the thresholds are invalid, the comments cannot be listed or a scorer fails to
+// prepare; the other errors are reported per comment.
+func (col *CommentsCollection) RunModeration(pipeline *ModerationPipeline) (*ModerationReport, error) {
+	spamThreshold := pipeline.SpamThreshold
+	if spamThreshold == 0 {
+		spamThreshold = DefaultSpamThreshold
+	}
+	if spamThreshold <= pipeline.ApproveBelow {
+		return nil, fmt.Errorf("spam threshold %v must be above the approval threshold %v", spamThreshold, pipeline.ApproveBelow)
+	}
+	comments, err := col.listFiltered(pipeline.Filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, scorer := range pipeline.Scorers {
+		if batch, ok := scorer.(BatchScorer); ok {
+			if err := batch.Prepare(comments); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	report := &ModerationReport{DryRun: pipeline.DryRun}
+	for i := range comments {
+		scored := pipeline.score(&comments[i], spamThreshold)
+		if scored.Err == nil && !pipeline.DryRun && statusOfAction(scored.Decision) != CommentStatus(scored.Comment.Status) {
+			updated, _, _, err := col.Moderate(scored.Comment.ID, scored.Decision)
+			if err != nil {
+				scored.Err = fmt.Errorf("comment %v: %v", scored.Comment.ID, err)
+			} else {
+				scored.Comment, scored.Applied = *updated, true
+			}
+		}
+		report.Comments = append(report.Comments, scored)
+	}
+	return report, nil
+}
+
+func (pipeline *ModerationPipeline) score(comment *Comment, spamThreshold float64) ScoredComment {
+	scored := ScoredComment{Comment: *comment}
+	for _, scorer := range pipeline.Scorers {
+		score, err := scorer.Score(comment)
+		if err != nil {
+			scored.Err = fmt.Errorf("comment %v: %v", comment.ID, err)
+			return scored
+		}
+		scored.Score += score.Value
+		scored.Reasons = append(scored.Reasons, score.Reasons...)
+	}
+	switch {
+	case scored.Score >= spamThreshold:
+		scored.Decision = CommentSpam
+	case scored.Score < pipeline.ApproveBelow:
+		scored.Decision = CommentApprove
+	default:
+		scored.Decision = CommentUnapprove
+	}
+	return scored
+}
+
+// statusOfAction returns the status a comment has after a moderation action
+func statusOfAction(action CommentAction) CommentStatus {
+	switch action {
+	case CommentApprove:
+		return CommentStatusApproved
+	case CommentUnapprove:
+		return CommentStatusHold
+	case CommentSpam:
+		return CommentStatusSpam
+	case CommentTrash:
+		return CommentStatusTrash
+	}
+	return ""
+}
@@ -0,0 +1,151 @@
+package wordpress_test
+
+import (
+	"errors"
+	"github.com/sogko/go-wordpress"
+	"strings"
+	"testing"
+)
+
+func TestRuleScorer(t *testing.T) {
+	scorer := wordpress.NewRuleScorer("Casino", "viagra")
+	scorer.MaxCommentsPerIP = 1
+	comments := []wordpress.Comment{
+		{ID: 1, AuthorIP: "10.0.0.1", Content: wordpress.Content{Raw: "Hello"}},
+		{ID: 2, AuthorIP: "10.0.0.2", Content: wordpress.Content{Raw: `<a href="http://a.example.com">a</a> http://b.example.com <a href="/c">c</a> https://d.example.com`}},
+		{ID: 3, AuthorIP: "10.0.0.2", AuthorURL: "http://casino.example.com", Content: wordpress.Content{Rendered: "<p>Nice</p>"}},
+		// URLs as anchor text, repeated, and linked by WordPress in the rendered content
+		{ID: 4, AuthorIP: "10.0.0.3", Content: wordpress.Content{Raw: `<a href="http://a.example.com">http://a.example.com</a> http://a.example.com <a href='http://b.example.com'>b</a>`}},
+		{ID: 5, AuthorIP: "10.0.0.4", Content: wordpress.Content{Rendered: `<p>See <a href="http://a.example.com" rel="nofollow">http://a.example.com</a> and <a href="http://b.example.com" rel="nofollow">http://b.example.com</a></p>`}},
+		{ID: 6, AuthorIP: "10.0.0.5", Content: wordpress.Content{Raw: "http://a.example.com http://b.example.com http://c.example.com"}},
+	}
+	if err := scorer.Prepare(comments); err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+
+	expected := []struct {
+		value   float64
+		reasons string
+	}{
+		{0, ""},
+		{2 + 2, "4 links, 2 comments from 10.0.0.2"},
+		{5 + 2, `blocklisted word "Casino", 2 comments from 10.0.0.2`},
+		{0, ""},
+		{0, ""},
+		{1, "3 links"},
+	}
+	for i, comment := range comments {
+		score, err := scorer.Score(&comment)
+		if err != nil {
+			t.Fatalf("Should not return error: %v", err.Error())
+		}
+		if score.Value != expected[i].value || strings.Join(score.Reasons, ", ") != expected[i].reasons {
+			t.Errorf("Expected comment %v to score %v (%v), got %v (%v)", comment.ID, expected[i].value, expected[i].reasons, score.Value, score.Reasons)
+		}
+	}
+}
+
+func TestRuleScorer_ZeroSettings(t *testing.T) {
+	// zero values are not replaced with the defaults
+	scorer := &wordpress.RuleScorer{LinkWeight: 1}
+	score, _ := scorer.Score(&wordpress.Comment{Content: wordpress.Content{Raw: "See http://example.com"}})
+	if score.Value != 1 {
+		t.Errorf("Expected a single link to score with MaxLinks 0, got %v", score)
+	}
+}
+
+func TestCommentsRunModeration(t *testing.T) {
+	server, wp, ids := newModerationTestClient(t)
+	defer server.Close()
+
+	thanks := wordpress.ScorerFunc(func(comment *wordpress.Comment) (wordpress.Score, error) {
+		if strings.Contains(comment.Content.Raw, "Thanks") {
+			return wordpress.Score{Value: -1, Reasons: []string{"thankful"}}, nil
+		}
+		if strings.Contains(comment.Content.Raw, "Hello") {
+			return wordpress.Score{}, errors.New("classifier unavailable")
+		}
+		return wordpress.Score{}, nil
+	})
+	pipeline := wordpress.NewModerationPipeline(wordpress.NewRuleScorer("casino"), thanks)
+	pipeline.DryRun = true
+
+	report, err := wp.Comments().RunModeration(pipeline)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	decisions := map[int]wordpress.CommentAction{}
+	for _, scored := range report.Comments {
+		if scored.Applied {
+			t.Errorf("Expected nothing to be applied in a dry run")
+		}
+		if scored.Err == nil {
+			decisions[scored.Comment.ID] = scored.Decision
+		}
+	}
+	expected := map[int]wordpress.CommentAction{ids[0]: wordpress.CommentSpam, ids[2]: wordpress.CommentSpam, ids[3]: wordpress.CommentApprove}
+	if len(report.Comments) != 4 || len(decisions) != len(expected) {
+		t.Fatalf("Expected 4 comments and 1 error, got %v", report)
+	}
+	for id, decision := range expected {
+		if decisions[id] != decision {
+			t.Errorf("Expected %v for comment %v, got %v", decision, id, decisions[id])
+		}
+	}
+	if !strings.Contains(report.String(), `blocklisted word "casino"`) || !strings.Contains(report.String(), "1 approved, 0 held, 2 spam") {
+		t.Errorf("Unexpected report: %v", report)
+	}
+	comment, _, _, _ := wp.Comments().Get(ids[0], "context=edit")
+	if wordpress.CommentStatus(comment.Status) != wordpress.CommentStatusHold {
+		t.Errorf("Expected the comment to be left alone, got %v", comment.Status)
+	}
+
+	pipeline.DryRun = false
+	report, err = wp.Comments().RunModeration(pipeline)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	for _, scored := range report.Comments {
+		if scored.Comment.ID == ids[1] {
+			if scored.Applied || scored.Err == nil {
+				t.Errorf("Expected the comment that failed scoring to be left alone, got %v", scored)
+			}
+			continue
+		}
+		if !scored.Applied || scored.Err != nil {
+			t.Errorf("Expected comment %v to be moderated, got %v", scored.Comment.ID, scored.Err)
+		}
+	}
+	for id, decision := range expected {
+		comment, _, _, _ := wp.Comments().Get(id, "context=edit")
+		if (decision == wordpress.CommentSpam) != (wordpress.CommentStatus(comment.Status) == wordpress.CommentStatusSpam) {
+			t.Errorf("Expected comment %v to be %v, got %v", id, decision, comment.Status)
+		}
+	}
+}
+
+func TestCommentsRunModeration_Thresholds(t *testing.T) {
+	server, wp, ids := newModerationTestClient(t)
+	defer server.Close()
+
+	// a pipeline built without NewModerationPipeline has the default spam threshold, so comments scoring 0 are held
+	pipeline := &wordpress.ModerationPipeline{Scorers: []wordpress.Scorer{wordpress.NewRuleScorer("casino")}, DryRun: true}
+	report, err := wp.Comments().RunModeration(pipeline)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	for _, scored := range report.Comments {
+		expected := wordpress.CommentUnapprove
+		if scored.Comment.ID == ids[0] || scored.Comment.ID == ids[2] {
+			expected = wordpress.CommentSpam
+		}
+		if scored.Decision != expected {
+			t.Errorf("Expected %v for comment %v, got %v (%v)", expected, scored.Comment.ID, scored.Decision, scored.Score)
+		}
+	}
+
+	pipeline.SpamThreshold, pipeline.ApproveBelow = 1, 2
+	if _, err := wp.Comments().RunModeration(pipeline); err == nil {
+		t.Errorf("Expected an error with a spam threshold below the approval threshold")
+	}
+}
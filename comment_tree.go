@@ -0,0 +1,122 @@
+package wordpress
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// CommentNode is a comment of a CommentTree, with its replies sorted by date
+type CommentNode struct {
+	Comment Comment
+
+	// Depth is 0 for top-level comments, 1 for their replies and so on
+	Depth   int
+	Replies []*CommentNode
+}
+
+// ReplyCount returns the number of replies to the comment, including replies to replies
+func (node *CommentNode) ReplyCount() int {
+	count := len(node.Replies)
+	for _, reply := range node.Replies {
+		count += reply.ReplyCount()
+	}
+	return count
+}
+
+// CommentTree is a list of comments threaded by their parent, top-level comments first, sorted by date
+type CommentTree struct {
+	Roots []*CommentNode
+}
+
+// Flatten returns the comments in display order: each comment is followed by its replies
+func (tree *CommentTree) Flatten() []*CommentNode {
+	var nodes []*CommentNode
+	var walk func(list []*CommentNode)
+	walk = func(list []*CommentNode) {
+		for _, node := range list {
+			nodes = append(nodes, node)
+			walk(node.Replies)
+		}
+	}
+	walk(tree.Roots)
+	return nodes
+}
+
+// Count returns the number of comments in the tree
+func (tree *CommentTree) Count() int {
+	count := len(tree.Roots)
+	for _, node := range tree.Roots {
+		count += node.ReplyCount()
+	}
+	return count
+}
+
+// BuildCommentTree threads a list of comments, oldest first at each level.
+// Replies to comments that are not in the list (for eg. to unapproved comments) are shown as top-level comments,
+// like WordPress does.
+func BuildCommentTree(comments []Comment) *CommentTree {
+	nodes := map[int]*CommentNode{}
+	var sorted []*CommentNode
+	for _, comment := range comments {
+		node := &CommentNode{Comment: comment}
+		nodes[comment.ID] = node
+		sorted = append(sorted, node)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Comment.Date != sorted[j].Comment.Date {
+			return sorted[i].Comment.Date < sorted[j].Comment.Date
+		}
+		return sorted[i].Comment.ID < sorted[j].Comment.ID
+	})
+
+	tree := &CommentTree{}
+	parents := map[*CommentNode]*CommentNode{}
+	for _, node := range sorted {
+		if parent, ok := nodes[node.Comment.Parent]; ok && !isAncestor(node, parent, parents) {
+			parent.Replies = append(parent.Replies, node)
+			parents[node] = parent
+		} else {
+			tree.Roots = append(tree.Roots, node)
+		}
+	}
+	var setDepth func(list []*CommentNode, depth int)
+	setDepth = func(list []*CommentNode, depth int) {
+		for _, node := range list {
+			node.Depth = depth
+			setDepth(node.Replies, depth+1)
+		}
+	}
+	setDepth(tree.Roots, 0)
+	return tree
+}
+
+// isAncestor returns true if node is other or one of its ancestors, so that threading node under other would make
+// a cycle (which only broken data has)
+func isAncestor(node *CommentNode, other *CommentNode, parents map[*CommentNode]*CommentNode) bool {
+	for ; other != nil; other = parents[other] {
+		if other == node {
+			return true
+		}
+	}
+	return false
+}
+
+// Tree fetches the comments of a post across all pages and threads them.
+// params filters the comments, for eg. "status=all" to include unapproved ones.
+func (col *CommentsCollection) Tree(postID int, params string) (*CommentTree, error) {
+	query := fmt.Sprintf("post=%v", postID)
+	if params != "" {
+		query += "&" + params
+	}
+	var comments []Comment
+	err := forEachPage(query, func(params string) (*http.Response, int, error) {
+		page, resp, _, err := col.List(params)
+		comments = append(comments, page...)
+		return resp, len(page), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return BuildCommentTree(comments), nil
+}
@@ -0,0 +1,86 @@
+package wordpress_test
+
+import (
+	"fmt"
+	"github.com/sogko/go-wordpress"
+	"github.com/sogko/go-wordpress/wptest"
+	"testing"
+)
+
+func commentIDs(nodes []*wordpress.CommentNode) []string {
+	var ids []string
+	for _, node := range nodes {
+		ids = append(ids, fmt.Sprintf("%v:%v", node.Comment.ID, node.Depth))
+	}
+	return ids
+}
+
+func TestBuildCommentTree(t *testing.T) {
+	tree := wordpress.BuildCommentTree([]wordpress.Comment{
+		{ID: 5, Parent: 1, Date: "2015-08-23T11:05:00"},
+		{ID: 1, Date: "2015-08-23T11:01:00"},
+		{ID: 2, Date: "2015-08-23T11:02:00"},
+		{ID: 3, Parent: 1, Date: "2015-08-23T11:03:00"},
+		{ID: 4, Parent: 3, Date: "2015-08-23T11:04:00"},
+		// a reply to a comment that is not listed
+		{ID: 6, Parent: 100, Date: "2015-08-23T11:00:00"},
+		// a cycle
+		{ID: 7, Parent: 8, Date: "2015-08-23T11:07:00"},
+		{ID: 8, Parent: 7, Date: "2015-08-23T11:08:00"},
+	})
+
+	expected := "[6:0 1:0 3:1 4:2 5:1 2:0 8:0 7:1]"
+	if got := fmt.Sprint(commentIDs(tree.Flatten())); got != expected {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if tree.Count() != 8 {
+		t.Errorf("Expected 8 comments, got %v", tree.Count())
+	}
+	if replies := tree.Roots[1].ReplyCount(); replies != 3 {
+		t.Errorf("Expected 3 replies to comment 1, got %v", replies)
+	}
+	if replies := tree.Roots[1].Replies[0].ReplyCount(); replies != 1 {
+		t.Errorf("Expected 1 reply to comment 3, got %v", replies)
+	}
+
+	if empty := wordpress.BuildCommentTree(nil); len(empty.Flatten()) != 0 || empty.Count() != 0 {
+		t.Errorf("Expected an empty tree, got %v", empty.Flatten())
+	}
+}
+
+func TestCommentsTree(t *testing.T) {
+	store := newFixtureStore(t)
+	post := store.AddPost(wptest.Object{"type": wptest.PostTypePost, "title": "Comments", "status": "publish"})
+	root := store.AddComment(wptest.Object{"post": post.Int("id"), "date": "2015-08-23T10:00:00"})
+	reply := store.AddComment(wptest.Object{"post": post.Int("id"), "parent": root.Int("id"), "date": "2015-08-23T10:01:00"})
+	store.AddComment(wptest.Object{"post": post.Int("id"), "parent": reply.Int("id"), "date": "2015-08-23T10:02:00"})
+	store.AddComment(wptest.Object{"post": post.Int("id"), "parent": root.Int("id"), "status": "hold", "date": "2015-08-23T10:03:00"})
+	// more than a page of comments
+	for i := 0; i < 100; i++ {
+		store.AddComment(wptest.Object{"post": post.Int("id"), "date": fmt.Sprintf("2015-08-23T11:%02d:%02d", i/60, i%60)})
+	}
+	server := wptest.NewServer(store)
+	defer server.Close()
+	wp := newFakeTestClient(server, nil)
+
+	tree, err := wp.Comments().Tree(post.Int("id"), "")
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if tree.Count() != 103 || len(tree.Roots) != 101 {
+		t.Fatalf("Expected 103 comments in 101 threads, got %v in %v", tree.Count(), len(tree.Roots))
+	}
+	first := tree.Roots[0]
+	if first.Comment.ID != root.Int("id") || first.ReplyCount() != 2 || first.Replies[0].Replies[0].Depth != 2 {
+		t.Errorf("Expected the first thread to have 2 replies, got %v", commentIDs(tree.Flatten()[:3]))
+	}
+
+	// unapproved comments are only listed on request
+	tree, err = wp.Comments().Tree(post.Int("id"), "status=all")
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if tree.Roots[0].ReplyCount() != 3 {
+		t.Errorf("Expected the held reply to be included, got %v replies", tree.Roots[0].ReplyCount())
+	}
+}
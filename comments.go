@@ -5,7 +5,23 @@ import (
 	"net/http"
 )
 
+// CommentStatus is the moderation status of a comment, for eg. CommentStatusHold.
+// Comment.Status is a plain string; compare it as CommentStatus(comment.Status).
+// CommentStatusApproved and CommentStatusUnapproved are declared with the post constants, and apply as well.
+type CommentStatus string
+
+const (
+	CommentStatusHold  CommentStatus = "hold"
+	CommentStatusSpam  CommentStatus = "spam"
+	CommentStatusTrash CommentStatus = "trash"
+
+	// CommentStatusAll lists comments of any status but spam and trash; it is only a filter
+	CommentStatusAll CommentStatus = "all"
+)
+
 type Comment struct {
+	collection *CommentsCollection `json:"-"`
+
 	ID              int        `json:"id,omitempty"`
 	AvatarURL       string     `json:"avatar_url,omitempty"`
 	AvatarURLs      AvatarURLS `json:"avatar_urls,omitempty"`
@@ -24,6 +40,26 @@ type Comment struct {
 	Post            int        `json:"post,omitempty"`
 	Status          string     `json:"status,omitempty"`
 	Type            string     `json:"type,omitempty"`
+
+	// MetaValues are the registered meta keys; see MetaValues
+	MetaValues MetaValues `json:"meta,omitempty"`
+}
+
+func (entity *Comment) setCollection(col *CommentsCollection) {
+	entity.collection = col
+}
+func (entity *Comment) Meta() *MetaCollection {
+	if entity.collection == nil {
+		// missing comment.collection parent. Probably Comment struct was initialized manually, not fetched from API
+		_warning("Missing parent comment collection")
+		return nil
+	}
+	return &MetaCollection{
+		client:     entity.collection.client,
+		parent:     entity,
+		parentType: CollectionComments,
+		url:        fmt.Sprintf("%v/%v/%v", entity.collection.url, entity.ID, CollectionMeta),
+	}
 }
 
 type CommentsCollection struct {
@@ -34,28 +70,51 @@ type CommentsCollection struct {
 func (col *CommentsCollection) List(params interface{}) ([]Comment, *http.Response, []byte, error) {
 	var comments []Comment
 	resp, body, err := col.client.List(col.url, params, &comments)
+
+	// set collection object for each entity which has sub-collection
+	for i := range comments {
+		comments[i].setCollection(col)
+	}
+
 	return comments, resp, body, err
 }
 func (col *CommentsCollection) Create(new *Comment) (*Comment, *http.Response, []byte, error) {
 	var created Comment
 	resp, body, err := col.client.Create(col.url, new, &created)
+	created.setCollection(col)
+	if err == nil {
+		err = saveUnregisteredMeta(created.Meta(), new.MetaValues, &created.MetaValues)
+	}
 	return &created, resp, body, err
 }
 func (col *CommentsCollection) Get(id int, params interface{}) (*Comment, *http.Response, []byte, error) {
 	var entity Comment
 	entityURL := fmt.Sprintf("%v/%v", col.url, id)
 	resp, body, err := col.client.Get(entityURL, params, &entity)
+	entity.setCollection(col)
 	return &entity, resp, body, err
 }
+func (col *CommentsCollection) Entity(id int) *Comment {
+	entity := Comment{
+		collection: col,
+		ID:         id,
+	}
+	return &entity
+}
 func (col *CommentsCollection) Update(id int, post *Comment) (*Comment, *http.Response, []byte, error) {
 	var updated Comment
 	entityURL := fmt.Sprintf("%v/%v", col.url, id)
 	resp, body, err := col.client.Update(entityURL, post, &updated)
+	updated.setCollection(col)
+	if err == nil {
+		err = saveUnregisteredMeta(updated.Meta(), post.MetaValues, &updated.MetaValues)
+	}
 	return &updated, resp, body, err
 }
 func (col *CommentsCollection) Delete(id int, params interface{}) (*Comment, *http.Response, []byte, error) {
 	var deleted Comment
 	entityURL := fmt.Sprintf("%v/%v", col.url, id)
 	resp, body, err := col.client.Delete(entityURL, params, &deleted)
+	deleted.setCollection(col)
 	return &deleted, resp, body, err
 }
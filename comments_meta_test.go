@@ -0,0 +1,53 @@
+package wordpress_test
+
+import (
+	"github.com/sogko/go-wordpress"
+	"net/http"
+	"testing"
+)
+
+func TestCommentsMeta_InvalidCall(t *testing.T) {
+	invalidComment := wordpress.Comment{}
+	invalidMeta := invalidComment.Meta()
+	if invalidMeta != nil {
+		t.Errorf("Expected meta to be nil, %v", invalidMeta)
+	}
+}
+
+func TestCommentsMeta(t *testing.T) {
+	wp := initTestClient()
+	comment := getAnyOneComment(t, wp)
+
+	newMeta, resp, _, err := comment.Meta().Create(&wordpress.Meta{Key: "testKey", Value: "testValue"})
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("Expected 201 Created, got %v", resp.Status)
+	}
+
+	meta, _, _, err := comment.Meta().List(nil)
+	if err != nil {
+		t.Errorf("Should not return error: %v", err.Error())
+	}
+	if len(meta) != 1 || meta[0].ID != newMeta.ID || meta[0].Value != "testValue" {
+		t.Errorf("Expected the new meta to be listed, got %v", meta)
+	}
+
+	updatedMeta, _, _, err := wp.Comments().Entity(comment.ID).Meta().Update(newMeta.ID, &wordpress.Meta{Value: "newTestValue"})
+	if err != nil {
+		t.Errorf("Failed to update comment meta: %v", err.Error())
+	}
+	if updatedMeta.Key != newMeta.Key || updatedMeta.Value != "newTestValue" {
+		t.Errorf("Expected the value to be updated, got %v", updatedMeta)
+	}
+
+	// note: Need to pass in `force=true` param in order to delete comment meta
+	deletedMeta, resp, _, err := comment.Meta().Delete(newMeta.ID, "force=true")
+	if err != nil {
+		t.Errorf("Failed to clean up new comment meta: %v", err.Error())
+	}
+	if resp.StatusCode != http.StatusOK || deletedMeta.Message != "Deleted meta" {
+		t.Errorf("Unexpected response to deleted meta: %v", deletedMeta.Message)
+	}
+}
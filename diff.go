@@ -0,0 +1,271 @@
+package wordpress
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DiffGranularity is the unit texts are compared by
+type DiffGranularity int
+
+const (
+	DiffLines DiffGranularity = iota
+	DiffWords
+)
+
+// DiffOp is the operation of a DiffChunk
+type DiffOp int
+
+const (
+	DiffEqual DiffOp = iota
+	DiffDelete
+	DiffInsert
+)
+
+// DiffChunk is a run of text kept, deleted or inserted
+type DiffChunk struct {
+	Op   DiffOp
+	Text string
+}
+
+// TextDiff is the difference between two texts, as the chunks turning the first one into the second one
+type TextDiff struct {
+	Granularity DiffGranularity
+	Chunks      []DiffChunk
+}
+
+// Changed returns true if the texts are different
+func (diff TextDiff) Changed() bool {
+	for _, chunk := range diff.Chunks {
+		if chunk.Op != DiffEqual {
+			return true
+		}
+	}
+	return false
+}
+
+// String formats a line diff like a unified diff without headers (every line prefixed with ` `, `-` or `+`),
+// and a word diff like `git diff --word-diff` (`[-deleted-]{+inserted+}`)
+func (diff TextDiff) String() string {
+	var s []string
+	for _, chunk := range diff.Chunks {
+		if diff.Granularity == DiffWords {
+			switch chunk.Op {
+			case DiffDelete:
+				s = append(s, "[-"+chunk.Text+"-]")
+			case DiffInsert:
+				s = append(s, "{+"+chunk.Text+"+}")
+			default:
+				s = append(s, chunk.Text)
+			}
+			continue
+		}
+		prefix := map[DiffOp]string{DiffEqual: " ", DiffDelete: "-", DiffInsert: "+"}[chunk.Op]
+		for _, line := range splitLines(chunk.Text) {
+			if !strings.HasSuffix(line, "\n") {
+				line += "\n"
+			}
+			s = append(s, prefix+line)
+		}
+	}
+	return strings.Join(s, "")
+}
+
+// DiffText compares two texts line by line, or word by word (HTML tags and whitespace runs being words too)
+func DiffText(from string, to string, granularity DiffGranularity) TextDiff {
+	tokenize := splitLines
+	if granularity == DiffWords {
+		tokenize = splitWords
+	}
+	return TextDiff{Granularity: granularity, Chunks: diffTokens(tokenize(from), tokenize(to))}
+}
+
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+var words = regexp.MustCompile(`<[^>]*>|\s+|[^\s<]+|<`)
+
+func splitWords(s string) []string {
+	return words.FindAllString(s, -1)
+}
+
+// diffTokens returns the shortest edit script between two lists of tokens, with Myers' algorithm (approximated
+// past maxEditDistance edits)
+func diffTokens(a []string, b []string) []DiffChunk {
+	// common prefix and suffix
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []DiffOp
+	var tokens []string
+	add := func(op DiffOp, token string) {
+		ops = append(ops, op)
+		tokens = append(tokens, token)
+	}
+	for _, token := range a[:prefix] {
+		add(DiffEqual, token)
+	}
+	middle := myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])
+	for _, edit := range middle {
+		add(edit.op, edit.token)
+	}
+	for _, token := range a[len(a)-suffix:] {
+		add(DiffEqual, token)
+	}
+
+	// merge the runs of tokens with the same operation, keeping deletions before insertions
+	var chunks []DiffChunk
+	for i := 0; i < len(ops); i++ {
+		if n := len(chunks); n > 0 && chunks[n-1].Op == ops[i] {
+			chunks[n-1].Text += tokens[i]
+			continue
+		}
+		if n := len(chunks); ops[i] == DiffDelete && n > 0 && chunks[n-1].Op == DiffInsert {
+			if n > 1 && chunks[n-2].Op == DiffDelete {
+				chunks[n-2].Text += tokens[i]
+			} else {
+				chunks = append(chunks[:n-1], DiffChunk{DiffDelete, tokens[i]}, chunks[n-1])
+			}
+			continue
+		}
+		chunks = append(chunks, DiffChunk{ops[i], tokens[i]})
+	}
+	return chunks
+}
+
+type edit struct {
+	op    DiffOp
+	token string
+}
+
+// maxEditDistance bounds the edit distance searched for between two lists of tokens; past it, the remaining
+// tokens are replaced as a whole rather than spending quadratic time on texts that have almost nothing in common
+const maxEditDistance = 1000
+
+// myers returns the edits turning a into b with the linear space variant of Myers' algorithm: the middle snake of
+// the shortest edit script splits the lists in two halves, which are compared recursively
+func myers(a []string, b []string) []edit {
+	var edits []edit
+	var compare func(a []string, b []string)
+	compare = func(a []string, b []string) {
+		// common prefix and suffix
+		prefix := 0
+		for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+			edits = append(edits, edit{DiffEqual, a[prefix]})
+			prefix++
+		}
+		a, b = a[prefix:], b[prefix:]
+		suffix := 0
+		for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+			suffix++
+		}
+		common := a[len(a)-suffix:]
+		a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+		if x, y, ok := middleSnake(a, b); ok {
+			compare(a[:x], b[:y])
+			compare(a[x:], b[y:])
+		} else {
+			for _, token := range a {
+				edits = append(edits, edit{DiffDelete, token})
+			}
+			for _, token := range b {
+				edits = append(edits, edit{DiffInsert, token})
+			}
+		}
+		for _, token := range common {
+			edits = append(edits, edit{DiffEqual, token})
+		}
+	}
+	compare(a, b)
+	return edits
+}
+
+// middleSnake returns where the middle snake of the shortest edit script between a and b starts, searching from
+// both ends at once; it reports false when either list is empty or the edit distance exceeds maxEditDistance
+func middleSnake(a []string, b []string) (int, int, bool) {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return 0, 0, false
+	}
+	maxD := (n + m + 1) / 2
+	if maxD > maxEditDistance {
+		maxD = maxEditDistance
+	}
+	// forward[offset+k] is the furthest x reached on diagonal k from the start, backward[offset+k] the furthest
+	// distance from the end reached on diagonal k from the end
+	offset := maxD + 1
+	forward := make([]int, 2*offset+1)
+	backward := make([]int, 2*offset+1)
+	for i := range forward {
+		forward[i], backward[i] = -1, -1
+	}
+	forward[offset+1], backward[offset+1] = 0, 0
+
+	delta := n - m
+	odd := delta%2 != 0
+	// the diagonals that ran past the end of a or b are skipped from then on
+	forwardStart, forwardEnd, backwardStart, backwardEnd := 0, 0, 0, 0
+	for d := 0; d < maxD; d++ {
+		for k := -d + forwardStart; k <= d-forwardEnd; k += 2 {
+			var x int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+			switch {
+			case x > n:
+				forwardEnd += 2
+			case y > m:
+				forwardStart += 2
+			case odd:
+				if i := offset + delta - k; i >= 0 && i < len(backward) && backward[i] != -1 && x >= n-backward[i] {
+					return x, y, true
+				}
+			}
+		}
+		for k := -d + backwardStart; k <= d-backwardEnd; k += 2 {
+			var x int
+			if k == -d || (k != d && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+			switch {
+			case x > n:
+				backwardEnd += 2
+			case y > m:
+				backwardStart += 2
+			case !odd:
+				if i := offset + delta - k; i >= 0 && i < len(forward) && forward[i] != -1 && forward[i] >= n-x {
+					return forward[i], forward[i] - (i - offset), true
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,114 @@
+package wordpress_test
+
+import (
+	"fmt"
+	"github.com/sogko/go-wordpress"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffText(t *testing.T) {
+	tests := []struct {
+		from        string
+		to          string
+		granularity wordpress.DiffGranularity
+		expected    string
+	}{
+		{"Hello world", "Hello world", wordpress.DiffWords, "Hello world"},
+		{"Hello world", "Hello there world", wordpress.DiffWords, "Hello {+there +}world"},
+		{"Hello world", "Goodbye world", wordpress.DiffWords, "[-Hello-]{+Goodbye+} world"},
+		{"<p>Hello world</p>", "<p>Hello</p>", wordpress.DiffWords, "<p>Hello[- world-]</p>"},
+		{"", "Hello", wordpress.DiffWords, "{+Hello+}"},
+		{"one\ntwo\nthree\n", "one\n2\nthree\nfour\n", wordpress.DiffLines, " one\n-two\n+2\n three\n+four\n"},
+		{"one", "two", wordpress.DiffLines, "-one\n+two\n"},
+	}
+	for _, test := range tests {
+		diff := wordpress.DiffText(test.from, test.to, test.granularity)
+		if diff.String() != test.expected {
+			t.Errorf("Expected %q, got %q", test.expected, diff.String())
+		}
+		if diff.Changed() != (test.from != test.to) {
+			t.Errorf("Expected Changed() to be %v for %q and %q", test.from != test.to, test.from, test.to)
+		}
+	}
+}
+
+func TestDiffText_Chunks(t *testing.T) {
+	texts := []string{
+		"",
+		"a b c d e f",
+		"a c b d f e",
+		"<p>The quick brown fox</p>\n<p>jumps over the lazy dog</p>",
+		"<p>The quick red fox</p>\n<p>jumps over the dog</p>\n<p>twice</p>",
+		"x a b x a b x",
+		"b x a x b a",
+	}
+	for _, from := range texts {
+		for _, to := range texts {
+			for _, granularity := range []wordpress.DiffGranularity{wordpress.DiffLines, wordpress.DiffWords} {
+				diff := wordpress.DiffText(from, to, granularity)
+				var old, new string
+				for i, chunk := range diff.Chunks {
+					if i > 0 && chunk.Op == diff.Chunks[i-1].Op {
+						t.Errorf("Expected chunks with the same operation to be merged: %v", diff.Chunks)
+					}
+					if chunk.Op != wordpress.DiffInsert {
+						old += chunk.Text
+					}
+					if chunk.Op != wordpress.DiffDelete {
+						new += chunk.Text
+					}
+				}
+				if old != from || new != to {
+					t.Errorf("Expected the chunks to rebuild %q and %q, got %q and %q", from, to, old, new)
+				}
+			}
+		}
+	}
+}
+
+func TestDiffText_Large(t *testing.T) {
+	// a few edits in a long text
+	var fromLines, toLines []string
+	for i := 0; i < 20000; i++ {
+		line := fmt.Sprintf("line %v\n", i)
+		fromLines = append(fromLines, line)
+		switch {
+		case i%5000 == 0:
+			toLines = append(toLines, "changed "+line)
+		case i%7000 == 0:
+		default:
+			toLines = append(toLines, line)
+		}
+	}
+	from, to := strings.Join(fromLines, ""), strings.Join(toLines, "")
+	diff := wordpress.DiffText(from, to, wordpress.DiffLines)
+	var old, new string
+	changes := 0
+	for _, chunk := range diff.Chunks {
+		if chunk.Op != wordpress.DiffInsert {
+			old += chunk.Text
+		}
+		if chunk.Op != wordpress.DiffDelete {
+			new += chunk.Text
+		}
+		if chunk.Op != wordpress.DiffEqual {
+			changes += strings.Count(chunk.Text, "\n")
+		}
+	}
+	if old != from || new != to {
+		t.Errorf("Expected the chunks to rebuild both texts")
+	}
+	if changes != 10 {
+		t.Errorf("Expected 10 changed lines, got %v", changes)
+	}
+
+	// two long texts with nothing in common
+	from, to = strings.Repeat("a\n", 20000), strings.Repeat("b\n", 30000)
+	diff = wordpress.DiffText(from, to, wordpress.DiffLines)
+	expected := []wordpress.DiffChunk{{wordpress.DiffDelete, from}, {wordpress.DiffInsert, to}}
+	if !reflect.DeepEqual(diff.Chunks, expected) {
+		t.Errorf("Expected the whole text to be replaced, got %v chunks", len(diff.Chunks))
+	}
+}
@@ -0,0 +1,91 @@
+package wordpress
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+type DuplicateScanOptions struct {
+	// Params filters the media to scan, for eg. "media_type=image"
+	Params string
+
+	// StoreHashes, if set, stores the checksums computed by the scan in the MediaHashMetaKey meta of the
+	// attachments, so that the next scans (and uploads with MediaUploadOptions.Deduplicate) find them
+	StoreHashes bool
+}
+
+// DuplicateGroup is a set of attachments with the same content, oldest first
+type DuplicateGroup struct {
+	Hash  string
+	Media []Media
+}
+
+// FindDuplicateMedia reports the attachments of the media library that have the same content.
+// The checksum of an attachment is read from its MediaHashMetaKey meta, in the `meta` object the media is listed
+// with; attachments without it are downloaded to compute it.
+func FindDuplicateMedia(client *Client, options *DuplicateScanOptions) ([]DuplicateGroup, error) {
+	if options == nil {
+		options = &DuplicateScanOptions{}
+	}
+	query := "context=edit"
+	if options.Params != "" {
+		query += "&" + options.Params
+	}
+
+	var media []Media
+	err := forEachPage(query, func(params string) (*http.Response, int, error) {
+		page, resp, _, err := client.Media().List(params)
+		media = append(media, page...)
+		return resp, len(page), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	groups := map[string][]Media{}
+	for i := range media {
+		hash := storedHash(&media[i])
+		if hash == "" {
+			var err error
+			if hash, err = computeHash(client, &media[i]); err != nil {
+				return nil, fmt.Errorf("media %v: %v", media[i].ID, err)
+			}
+			if options.StoreHashes {
+				if err := storeHash(&media[i], hash); err != nil {
+					return nil, fmt.Errorf("media %v: %v", media[i].ID, err)
+				}
+			}
+		}
+		groups[hash] = append(groups[hash], media[i])
+	}
+
+	var duplicates []DuplicateGroup
+	for hash, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].ID < group[j].ID
+		})
+		duplicates = append(duplicates, DuplicateGroup{Hash: hash, Media: group})
+	}
+	sort.Slice(duplicates, func(i, j int) bool {
+		return duplicates[i].Media[0].ID < duplicates[j].Media[0].ID
+	})
+	return duplicates, nil
+}
+
+// computeHash downloads the file of an attachment and returns its SHA-256 checksum
+func computeHash(client *Client, media *Media) (string, error) {
+	resp, err := client.GetStream(media.SourceURL, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%v: %v", media.SourceURL, resp.Status)
+	}
+	sum, _, err := checksum(resp.Body)
+	return sum, err
+}
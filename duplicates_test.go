@@ -0,0 +1,190 @@
+package wordpress_test
+
+import (
+	"bytes"
+	"github.com/sogko/go-wordpress"
+	"github.com/sogko/go-wordpress/wptest"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMediaCreate_Deduplicate(t *testing.T) {
+	server := newFixtureServer(t)
+	defer server.Close()
+	server.Store.RegisterMeta("post", wordpress.MediaHashMetaKey)
+	wp := newFakeTestClient(server, nil)
+
+	upload := factoryMediaFileUpload(t)
+	upload.Deduplicate = true
+	media, resp, _, err := wp.Media().Create(upload)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("Expected 201 Created, got %v", resp.Status)
+	}
+
+	// the same content, from a reader that cannot seek
+	duplicate, resp, _, err := wp.Media().Create(&wordpress.MediaUploadOptions{
+		Filename:    "copy.jpg",
+		Reader:      struct{ io.Reader }{bytes.NewReader(upload.Data)},
+		Deduplicate: true,
+	})
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if resp.StatusCode != http.StatusOK || duplicate.ID != media.ID {
+		t.Errorf("Expected the existing media %v, got %v (%v)", media.ID, duplicate.ID, resp.Status)
+	}
+
+	other := factoryMediaFileUpload(t)
+	other.Data = append(other.Data, 0)
+	other.Deduplicate = true
+	created, resp, _, err := wp.Media().Create(other)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if resp.StatusCode != http.StatusCreated || created.ID == media.ID {
+		t.Errorf("Expected different content to be uploaded, got %v (%v)", created.ID, resp.Status)
+	}
+}
+
+// noFilterTransport drops the `filter` parameters, which WordPress 4.7+ ignores
+type noFilterTransport struct{}
+
+func (noFilterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	query := req.URL.Query()
+	for key := range query {
+		if strings.HasPrefix(key, "filter[") {
+			query.Del(key)
+		}
+	}
+	req.URL.RawQuery = query.Encode()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestMediaCreate_DeduplicateWithoutFilter(t *testing.T) {
+	server := newFixtureServer(t)
+	defer server.Close()
+	server.Store.RegisterMeta("post", wordpress.MediaHashMetaKey)
+	wp := newFakeTestClient(server, noFilterTransport{})
+
+	upload := factoryMediaFileUpload(t)
+	upload.Deduplicate = true
+	media, _, _, err := wp.Media().Create(upload)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	// newer attachments, without a checksum
+	for i := 0; i < 12; i++ {
+		other := factoryMediaFileUpload(t)
+		other.Data = append(other.Data, byte(i))
+		if _, _, _, err := wp.Media().Create(other); err != nil {
+			t.Fatalf("Should not return error: %v", err.Error())
+		}
+	}
+
+	upload.Deduplicate = true
+	duplicate, resp, _, err := wp.Media().Create(upload)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if resp.StatusCode != http.StatusOK || duplicate.ID != media.ID {
+		t.Errorf("Expected the existing media %v, got %v (%v)", media.ID, duplicate.ID, resp.Status)
+	}
+}
+
+func TestFindDuplicateMedia(t *testing.T) {
+	server := newFixtureServer(t)
+	defer server.Close()
+	server.Store.RegisterMeta("post", wordpress.MediaHashMetaKey)
+	transport := &countingTransport{}
+	wp := newFakeTestClient(server, transport)
+
+	var ids []int
+	for _, deduplicate := range []bool{false, false, true} {
+		upload := factoryMediaFileUpload(t)
+		upload.Deduplicate = deduplicate
+		media, _, _, err := wp.Media().Create(upload)
+		if err != nil {
+			t.Fatalf("Should not return error: %v", err.Error())
+		}
+		ids = append(ids, media.ID)
+	}
+
+	groups, err := wordpress.FindDuplicateMedia(wp, &wordpress.DuplicateScanOptions{StoreHashes: true})
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if len(groups) != 1 || len(groups[0].Media) != 3 {
+		t.Fatalf("Expected a group of 3 duplicates, got %v", groups)
+	}
+	for i, media := range groups[0].Media {
+		if media.ID != ids[i] {
+			t.Errorf("Expected media %v, got %v", ids[i], media.ID)
+		}
+	}
+
+	// the checksums are stored: the next scan does not download anything
+	requests := len(transport.Requests())
+	groups, _ = wordpress.FindDuplicateMedia(wp, nil)
+	if len(groups) != 1 || len(groups[0].Media) != 3 {
+		t.Errorf("Expected the same group, got %v", groups)
+	}
+	for _, req := range transport.Requests()[requests:] {
+		if strings.HasPrefix(req.URL.Path, wptest.UploadsPath) {
+			t.Errorf("Expected no file to be downloaded, got %v", req.URL)
+		}
+	}
+}
+
+// noMetaRouteTransport answers 404 Not Found to the requests to meta routes
+type noMetaRouteTransport struct{}
+
+func (noMetaRouteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if strings.Contains(req.URL.Path, "/meta") {
+		return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Header: http.Header{},
+			Body: ioutil.NopCloser(strings.NewReader(`{"code":"rest_no_route"}`)), Request: req}, nil
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestMediaCreate_DeduplicateUnregistered(t *testing.T) {
+	server := newFixtureServer(t)
+	defer server.Close()
+	wp := newFakeTestClient(server, nil)
+
+	// without the key registered, the checksum is written through the meta route, but uploads cannot find it
+	upload := factoryMediaFileUpload(t)
+	upload.Deduplicate = true
+	media, _, _, err := wp.Media().Create(upload)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	duplicate, resp, _, err := wp.Media().Create(upload)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if resp.StatusCode != http.StatusCreated || duplicate.ID == media.ID {
+		t.Errorf("Expected the file to be uploaded again, got %v (%v)", duplicate.ID, resp.Status)
+	}
+
+	// without a meta route either, the upload succeeds but the checksum is not stored
+	wp = newFakeTestClient(server, noMetaRouteTransport{})
+	created, _, _, err := wp.Media().Create(upload)
+	if _, ok := err.(*wordpress.UnregisteredMetaError); !ok || created == nil || created.ID == 0 {
+		t.Fatalf("Expected the media to be created with an UnregisteredMetaError, got %v (%v)", created, err)
+	}
+
+	// the scan computes the checksums instead
+	groups, err := wordpress.FindDuplicateMedia(wp, nil)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if len(groups) != 1 || len(groups[0].Media) != 3 {
+		t.Errorf("Expected a group of 3 duplicates, got %v", groups)
+	}
+}
@@ -0,0 +1,46 @@
+package wordpress_test
+
+import (
+	"github.com/sogko/go-wordpress"
+	"github.com/sogko/go-wordpress/wptest"
+	"net/http"
+	"os"
+	"testing"
+)
+
+// loadFixtureStore returns a store seeded with the fixture export of the repository (see wptest.NewFixtureStore)
+func loadFixtureStore() (*wptest.Store, error) {
+	f, err := os.Open(wptest.FixtureWXR)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return wptest.NewFixtureStore(f)
+}
+
+func newFixtureStore(t *testing.T) *wptest.Store {
+	store, err := loadFixtureStore()
+	if err != nil {
+		t.Fatalf("Failed to load the fixture: %v", err)
+	}
+	return store
+}
+
+// newFixtureServer starts a fake server of its own, seeded with the fixture
+func newFixtureServer(t *testing.T) *wptest.Server {
+	return wptest.NewServer(newFixtureStore(t))
+}
+
+// newFakeTestClient creates a client of a fake server of its own, as the administrator.
+// Most tests use initTestClient, which runs against the shared server of TestMain, a live site or a cassette.
+// A private fake is only used by the tests that need what those cannot give: seeding or inspecting the store,
+// a library without other uploads, or a transport that cuts or rewrites the traffic. These tests ignore
+// WP_API_URL, WP_RECORD and WP_REPLAY.
+func newFakeTestClient(server *wptest.Server, transport http.RoundTripper) *wordpress.Client {
+	return wordpress.NewClient(&wordpress.Options{
+		BaseAPIURL: server.BaseAPIURL(),
+		Username:   wptest.DefaultUsername,
+		Password:   wptest.DefaultPassword,
+		Transport:  transport,
+	})
+}
@@ -0,0 +1,349 @@
+package wordpress
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultLoaderWait is how long a Loader collects IDs before sending a batch
+	DefaultLoaderWait = 2 * time.Millisecond
+
+	// MaxLoaderBatch is the most IDs a Loader fetches with one request; WP-API caps `per_page` at 100
+	MaxLoaderBatch = 100
+)
+
+// Loader fetches users, posts, pages, media and terms by ID in batches.
+// Concurrent loads of the same ID share a single fetch, and the distinct IDs requested within Wait
+// are fetched with one list request, e.g. `GET /users?include=1,2,3&per_page=100`.
+//
+// Results are memoized for the lifetime of the Loader, so use one Loader per unit of work
+// (for eg. rendering a page), or Clear() it. Since IDs are fetched with list requests, only what the
+// list endpoints return can be loaded: for eg. posts must be published, unless the client can list drafts.
+type Loader struct {
+	Wait     time.Duration
+	MaxBatch int
+
+	client *Client
+
+	// fetchMu serializes the requests of the loader, as a Client must not be used concurrently
+	fetchMu  sync.Mutex
+	mu       sync.Mutex
+	batchers map[string]*batcher
+}
+
+// NewLoader returns a Loader fetching with the given client
+func NewLoader(client *Client) *Loader {
+	return &Loader{
+		Wait:     DefaultLoaderWait,
+		MaxBatch: MaxLoaderBatch,
+		client:   client,
+		batchers: map[string]*batcher{},
+	}
+}
+
+// User loads a user by ID
+func (l *Loader) User(id int) (*User, error) {
+	v, err := l.batcher(CollectionUsers, l.fetchUsers).load(id)
+	if err != nil {
+		return nil, err
+	}
+	user := *v.(*User)
+	return &user, nil
+}
+
+// Users loads users by ID; IDs that were not found are missing from the result
+func (l *Loader) Users(ids ...int) (map[int]*User, error) {
+	values, err := l.batcher(CollectionUsers, l.fetchUsers).loadMany(ids)
+	users := map[int]*User{}
+	for id, v := range values {
+		user := *v.(*User)
+		users[id] = &user
+	}
+	return users, err
+}
+
+// Post loads a post by ID
+func (l *Loader) Post(id int) (*Post, error) {
+	v, err := l.batcher(CollectionPosts, l.fetchPosts).load(id)
+	if err != nil {
+		return nil, err
+	}
+	post := *v.(*Post)
+	return &post, nil
+}
+
+// Posts loads posts by ID; IDs that were not found are missing from the result
+func (l *Loader) Posts(ids ...int) (map[int]*Post, error) {
+	values, err := l.batcher(CollectionPosts, l.fetchPosts).loadMany(ids)
+	posts := map[int]*Post{}
+	for id, v := range values {
+		post := *v.(*Post)
+		posts[id] = &post
+	}
+	return posts, err
+}
+
+// Page loads a page by ID
+func (l *Loader) Page(id int) (*Page, error) {
+	v, err := l.batcher(CollectionPages, l.fetchPages).load(id)
+	if err != nil {
+		return nil, err
+	}
+	page := *v.(*Page)
+	return &page, nil
+}
+
+// Pages loads pages by ID; IDs that were not found are missing from the result
+func (l *Loader) Pages(ids ...int) (map[int]*Page, error) {
+	values, err := l.batcher(CollectionPages, l.fetchPages).loadMany(ids)
+	pages := map[int]*Page{}
+	for id, v := range values {
+		page := *v.(*Page)
+		pages[id] = &page
+	}
+	return pages, err
+}
+
+// Media loads a media item by ID
+func (l *Loader) Media(id int) (*Media, error) {
+	v, err := l.batcher(CollectionMedia, l.fetchMedia).load(id)
+	if err != nil {
+		return nil, err
+	}
+	media := *v.(*Media)
+	return &media, nil
+}
+
+// MediaItems loads media items by ID; IDs that were not found are missing from the result
+func (l *Loader) MediaItems(ids ...int) (map[int]*Media, error) {
+	values, err := l.batcher(CollectionMedia, l.fetchMedia).loadMany(ids)
+	media := map[int]*Media{}
+	for id, v := range values {
+		item := *v.(*Media)
+		media[id] = &item
+	}
+	return media, err
+}
+
+// Term loads a term of a taxonomy ("tag" or "category", as in TermsCollection.List) by ID
+func (l *Loader) Term(taxonomy string, id int) (*Term, error) {
+	v, err := l.batcher(CollectionTerms+"/"+taxonomy, l.termsFetcher(taxonomy)).load(id)
+	if err != nil {
+		return nil, err
+	}
+	term := *v.(*Term)
+	return &term, nil
+}
+
+// Terms loads terms of a taxonomy by ID; IDs that were not found are missing from the result
+func (l *Loader) Terms(taxonomy string, ids ...int) (map[int]*Term, error) {
+	values, err := l.batcher(CollectionTerms+"/"+taxonomy, l.termsFetcher(taxonomy)).loadMany(ids)
+	terms := map[int]*Term{}
+	for id, v := range values {
+		term := *v.(*Term)
+		terms[id] = &term
+	}
+	return terms, err
+}
+
+// Clear forgets the loaded objects, so they are fetched again on their next load
+func (l *Loader) Clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, b := range l.batchers {
+		for id, r := range b.results {
+			select {
+			case <-r.done:
+				delete(b.results, id)
+			default:
+				// still being fetched
+			}
+		}
+	}
+}
+
+func (l *Loader) fetchUsers(params string) (map[int]interface{}, error) {
+	users, _, _, err := l.client.Users().List(params)
+	values := map[int]interface{}{}
+	for i := range users {
+		values[users[i].ID] = &users[i]
+	}
+	return values, err
+}
+
+func (l *Loader) fetchPosts(params string) (map[int]interface{}, error) {
+	posts, _, _, err := l.client.Posts().List(params)
+	values := map[int]interface{}{}
+	for i := range posts {
+		values[posts[i].ID] = &posts[i]
+	}
+	return values, err
+}
+
+func (l *Loader) fetchPages(params string) (map[int]interface{}, error) {
+	pages, _, _, err := l.client.Pages().List(params)
+	values := map[int]interface{}{}
+	for i := range pages {
+		values[pages[i].ID] = &pages[i]
+	}
+	return values, err
+}
+
+func (l *Loader) fetchMedia(params string) (map[int]interface{}, error) {
+	media, _, _, err := l.client.Media().List(params)
+	values := map[int]interface{}{}
+	for i := range media {
+		values[media[i].ID] = &media[i]
+	}
+	return values, err
+}
+
+func (l *Loader) termsFetcher(taxonomy string) func(params string) (map[int]interface{}, error) {
+	return func(params string) (map[int]interface{}, error) {
+		terms, _, _, err := l.client.Terms().List(taxonomy, params)
+		values := map[int]interface{}{}
+		for i := range terms {
+			values[terms[i].ID] = &terms[i]
+		}
+		return values, err
+	}
+}
+
+func (l *Loader) batcher(name string, fetch func(params string) (map[int]interface{}, error)) *batcher {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.batchers[name]
+	if !ok {
+		b = &batcher{loader: l, name: name, fetch: fetch, results: map[int]*loadResult{}}
+		l.batchers[name] = b
+	}
+	return b
+}
+
+// loadResult is the (future) result of loading an ID; done is closed once it is set
+type loadResult struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// batcher collects the IDs to load of a single collection; its fields are guarded by loader.mu
+type batcher struct {
+	loader  *Loader
+	name    string
+	fetch   func(params string) (map[int]interface{}, error)
+	results map[int]*loadResult
+	pending []int
+	timer   *time.Timer
+}
+
+func (b *batcher) load(id int) (interface{}, error) {
+	r := b.enqueue([]int{id})[0]
+	<-r.done
+	return r.value, r.err
+}
+
+func (b *batcher) loadMany(ids []int) (map[int]interface{}, error) {
+	values := map[int]interface{}{}
+	var err error
+	for i, r := range b.enqueue(ids) {
+		<-r.done
+		if r.err == nil {
+			values[ids[i]] = r.value
+		} else if _, notFound := r.err.(*NotFoundError); !notFound && err == nil {
+			err = r.err
+		}
+	}
+	return values, err
+}
+
+// enqueue returns the results of the given IDs, adding the ones not loaded or loading yet to the pending batch
+func (b *batcher) enqueue(ids []int) []*loadResult {
+	l := b.loader
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	maxBatch := l.MaxBatch
+	if maxBatch <= 0 || maxBatch > MaxLoaderBatch {
+		maxBatch = MaxLoaderBatch
+	}
+	results := make([]*loadResult, len(ids))
+	for i, id := range ids {
+		r, ok := b.results[id]
+		if !ok {
+			r = &loadResult{done: make(chan struct{})}
+			b.results[id] = r
+			b.pending = append(b.pending, id)
+		}
+		results[i] = r
+
+		if len(b.pending) >= maxBatch {
+			batch := b.pending
+			b.pending = nil
+			if b.timer != nil {
+				b.timer.Stop()
+				b.timer = nil
+			}
+			go b.dispatch(batch)
+		}
+	}
+	if len(b.pending) > 0 && b.timer == nil {
+		b.timer = time.AfterFunc(l.Wait, b.flush)
+	}
+	return results
+}
+
+func (b *batcher) flush() {
+	b.loader.mu.Lock()
+	batch := b.pending
+	b.pending = nil
+	b.timer = nil
+	b.loader.mu.Unlock()
+	if len(batch) > 0 {
+		b.dispatch(batch)
+	}
+}
+
+// dispatch fetches a batch of IDs and resolves their results; failed loads are not memoized
+func (b *batcher) dispatch(ids []int) {
+	l := b.loader
+	var s []string
+	for _, id := range ids {
+		s = append(s, strconv.Itoa(id))
+	}
+	params := fmt.Sprintf("include=%v&per_page=%v", strings.Join(s, ","), MaxLoaderBatch)
+
+	l.fetchMu.Lock()
+	values, err := b.fetch(params)
+	l.fetchMu.Unlock()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, id := range ids {
+		r := b.results[id]
+		if value, ok := values[id]; ok && err == nil {
+			r.value = value
+		} else {
+			r.err = err
+			if r.err == nil {
+				r.err = &NotFoundError{Collection: b.name, ID: id}
+			}
+			delete(b.results, id)
+		}
+		close(r.done)
+	}
+}
+
+// NotFoundError is returned by a Loader for IDs the API did not return
+type NotFoundError struct {
+	Collection string
+	ID         int
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%v %v not found", e.Collection, e.ID)
+}
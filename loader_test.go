@@ -0,0 +1,147 @@
+package wordpress_test
+
+import (
+	"fmt"
+	"github.com/sogko/go-wordpress"
+	"github.com/sogko/go-wordpress/wptest"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// countingTransport records the requests it sends
+type countingTransport struct {
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	t.requests = append(t.requests, req)
+	t.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func (t *countingTransport) Requests() []*http.Request {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*http.Request(nil), t.requests...)
+}
+
+func newLoaderTestClient(t *testing.T) (*wordpress.Loader, *countingTransport, func()) {
+	store := newFixtureStore(t)
+	for i := 0; i < 5; i++ {
+		store.AddUser(wptest.Object{"username": fmt.Sprintf("user%v", i), "email": fmt.Sprintf("user%v@example.com", i)})
+	}
+	server := wptest.NewServer(store)
+	transport := &countingTransport{}
+	wp := wordpress.NewClient(&wordpress.Options{
+		BaseAPIURL: server.BaseAPIURL(),
+		Username:   wptest.DefaultUsername,
+		Password:   wptest.DefaultPassword,
+		Transport:  transport,
+	})
+	return wordpress.NewLoader(wp), transport, server.Close
+}
+
+func TestLoader_Coalescing(t *testing.T) {
+	loader, transport, done := newLoaderTestClient(t)
+	defer done()
+
+	ids := []int{1, 2, 3, 2, 1, 3, 99}
+	users := make([]*wordpress.User, len(ids))
+	errs := make([]error, len(ids))
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id int) {
+			defer wg.Done()
+			users[i], errs[i] = loader.User(id)
+		}(i, id)
+	}
+	wg.Wait()
+
+	requests := transport.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("Expected a single request, got %v", len(requests))
+	}
+	if requests[0].URL.Path != "/wp-json/wp/v2/users" || requests[0].URL.Query().Get("per_page") != "100" {
+		t.Errorf("Expected a list request, got %v", requests[0].URL)
+	}
+	for i, id := range ids {
+		if id == 99 {
+			if _, ok := errs[i].(*wordpress.NotFoundError); !ok {
+				t.Errorf("Expected NotFoundError for user 99, got %v", errs[i])
+			}
+			continue
+		}
+		if errs[i] != nil {
+			t.Fatalf("Should not return error: %v", errs[i].Error())
+		}
+		if users[i].ID != id {
+			t.Errorf("Expected user %v, got %v", id, users[i].ID)
+		}
+	}
+
+	// loaded users are memoized
+	loader.User(2)
+	if len(transport.Requests()) != 1 {
+		t.Errorf("Expected a loaded user not to be fetched again")
+	}
+	loader.Clear()
+	loader.User(2)
+	if len(transport.Requests()) != 2 {
+		t.Errorf("Expected a cleared user to be fetched again")
+	}
+}
+
+func TestLoader_MaxBatch(t *testing.T) {
+	loader, transport, done := newLoaderTestClient(t)
+	defer done()
+	loader.MaxBatch = 2
+
+	users, err := loader.Users(1, 2, 3, 4, 5, 404)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if len(users) != 5 || users[5].ID != 5 {
+		t.Errorf("Expected 5 users, got %v", users)
+	}
+	if requests := transport.Requests(); len(requests) != 3 {
+		t.Errorf("Expected 3 batches of 2, got %v requests", len(requests))
+	}
+}
+
+func TestLoader_Collections(t *testing.T) {
+	loader, transport, done := newLoaderTestClient(t)
+	defer done()
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		post, err := loader.Post(1)
+		if err != nil || post.Title.Rendered != "Hello world!" {
+			t.Errorf("Expected post 1, got %v %v", post, err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		page, err := loader.Page(2)
+		if err != nil || page.Slug != "sample-page" {
+			t.Errorf("Expected page 2, got %v %v", page, err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		category, err := loader.Term("category", 1)
+		if err != nil || category.Slug != "uncategorized" {
+			t.Errorf("Expected category 1, got %v %v", category, err)
+		}
+	}()
+	wg.Wait()
+
+	if requests := transport.Requests(); len(requests) != 3 {
+		t.Errorf("Expected one request per collection, got %v", len(requests))
+	}
+}
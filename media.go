@@ -1,8 +1,21 @@
 package wordpress
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 type MediaDetailsSizesItem struct {
@@ -12,12 +25,14 @@ type MediaDetailsSizesItem struct {
 	MimeType  string `json:"mime_type,omitempty"`
 	SourceURL string `json:"source_url,omitempty"`
 }
-type MediaDetailsSizes struct {
-	Thumbnail MediaDetailsSizesItem `json:"thumbnail,omitempty"`
-	Medium    MediaDetailsSizesItem `json:"medium,omitempty"`
-	Large     MediaDetailsSizesItem `json:"large,omitempty"`
-	SiteLogo  MediaDetailsSizesItem `json:"site-logo,omitempty"`
-}
+
+// MediaDetailsSizes are the intermediate sizes of an image by name, for eg. "thumbnail", "medium_large"
+// or sizes registered by themes and plugins
+type MediaDetailsSizes map[string]MediaDetailsSizesItem
+
+// MediaSizeFull is the name of the size of the original image
+const MediaSizeFull = "full"
+
 type MediaDetails struct {
 	Raw       string                 `json:"raw,omitempty"`
 	Rendered  string                 `json:"rendered,omitempty"`
@@ -31,15 +46,222 @@ type MediaUploadOptions struct {
 	Filename    string
 	ContentType string
 	Data        []byte
+
+	// Reader, if set, is streamed instead of Data. Size is its length, or -1 if unknown
+	// (in which case the upload is sent chunked); when 0, it is taken from the reader if possible
+	// (files, bytes.Reader, strings.Reader, ...).
+	Reader io.Reader
+	Size   int64
+
+	// Multipart sends the file as multipart/form-data along with the fields below, in a single request.
+	// Setting any of the fields implies Multipart.
+	Multipart   bool
+	Title       string
+	Caption     string
+	AltText     string
+	Description string
+	Post        int
+
+	// Progress, if set, is called as the file is sent, with the bytes sent so far and the total (-1 if unknown)
+	Progress func(sent int64, total int64)
+
+	// Deduplicate, if set, looks up an attachment with the same content (by the checksum stored in its
+	// MediaHashMetaKey meta) and returns it instead of uploading the file again; the response is then the one of
+	// the lookup, with a 200 OK status instead of 201 Created. The checksum of uploaded files is stored.
+	// Attachments are only found on sites registering MediaHashMetaKey, see MediaHashMetaKey.
+	// A Reader that is not an io.Seeker is read into memory to compute the checksum.
+	Deduplicate bool
+}
+
+// MediaHashMetaKey is the meta key the SHA-256 checksum of the content of an attachment is stored under.
+// It is read from the `meta` object of attachments, so the site must register it with `show_in_rest`, for eg.
+// `register_post_meta('attachment', 'go_wordpress_sha256', array('show_in_rest' => true, 'single' => true))`.
+// On other sites, it is written through the meta route, which attachments only have on sites adding
+// `custom-fields` support to them.
+const MediaHashMetaKey = "go_wordpress_sha256"
+
+// checksum returns the SHA-256 checksum of the file of an upload, and the options to upload it with
+func (options *MediaUploadOptions) checksum() (string, *MediaUploadOptions, error) {
+	if options.Reader == nil {
+		sum, _, err := checksum(bytes.NewReader(options.Data))
+		return sum, options, err
+	}
+	if seeker, ok := options.Reader.(io.Seeker); ok {
+		offset, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return "", nil, err
+		}
+		sum, _, err := checksum(options.Reader)
+		if err != nil {
+			return "", nil, err
+		}
+		_, err = seeker.Seek(offset, io.SeekStart)
+		return sum, options, err
+	}
+	data, err := ioutil.ReadAll(options.Reader)
+	if err != nil {
+		return "", nil, err
+	}
+	buffered := *options
+	buffered.Reader, buffered.Size = bytes.NewReader(data), int64(len(data))
+	sum, _, err := checksum(bytes.NewReader(data))
+	return sum, &buffered, err
+}
+
+// request returns the body of an upload, its length (-1 if unknown) and its headers
+func (options *MediaUploadOptions) request() (io.Reader, int64, http.Header, error) {
+	file := options.Reader
+	size := options.Size
+	if file == nil {
+		file = bytes.NewReader(options.Data)
+		size = int64(len(options.Data))
+	} else if size == 0 {
+		size = readerSize(file)
+	}
+	if options.Progress != nil {
+		file = &progressReader{reader: file, total: size, progress: options.Progress}
+	}
+	contentType := options.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(options.Filename))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	header := http.Header{}
+	fields := [][2]string{
+		{"title", options.Title},
+		{"caption", options.Caption},
+		{"alt_text", options.AltText},
+		{"description", options.Description},
+	}
+	if options.Post != 0 {
+		fields = append(fields, [2]string{"post", strconv.Itoa(options.Post)})
+	}
+	multipartUpload := options.Multipart
+	for _, field := range fields {
+		multipartUpload = multipartUpload || field[1] != ""
+	}
+	if !multipartUpload {
+		header.Set("Content-Type", contentType)
+		header.Set("Content-Disposition", contentDisposition("attachment", "", options.Filename))
+		return file, size, header, nil
+	}
+
+	// the fields and the header of the file part are buffered, the file itself is streamed
+	var prefix bytes.Buffer
+	w := multipart.NewWriter(&prefix)
+	for _, field := range fields {
+		if field[1] != "" {
+			if err := w.WriteField(field[0], field[1]); err != nil {
+				return nil, 0, nil, err
+			}
+		}
+	}
+	part := textproto.MIMEHeader{}
+	part.Set("Content-Disposition", contentDisposition("form-data", "file", options.Filename))
+	part.Set("Content-Type", contentType)
+	if _, err := w.CreatePart(part); err != nil {
+		return nil, 0, nil, err
+	}
+	suffix := fmt.Sprintf("\r\n--%v--\r\n", w.Boundary())
+
+	length := int64(-1)
+	if size >= 0 {
+		length = int64(prefix.Len()) + size + int64(len(suffix))
+	}
+	header.Set("Content-Type", w.FormDataContentType())
+	return io.MultiReader(&prefix, file, strings.NewReader(suffix)), length, header, nil
+}
+
+// readerSize returns the remaining length of readers that know it, or -1
+func readerSize(r io.Reader) int64 {
+	switch r := r.(type) {
+	case interface {
+		Len() int
+	}:
+		return int64(r.Len())
+	case *os.File:
+		info, err := r.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		offset, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return info.Size() - offset
+	}
+	return -1
+}
+
+// progressReader reports the bytes read from an upload
+type progressReader struct {
+	reader   io.Reader
+	sent     int64
+	total    int64
+	progress func(sent int64, total int64)
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.sent += int64(n)
+		r.progress(r.sent, r.total)
+	}
+	return n, err
+}
+
+// Caption is the caption of a media item; older WP-API versions return it as a plain string
+type Caption struct {
+	Raw      string `json:"raw,omitempty"`
+	Rendered string `json:"rendered,omitempty"`
+}
+
+func (caption *Caption) UnmarshalJSON(data []byte) error {
+	raw, rendered, err := unmarshalRenderedField(data)
+	caption.Raw, caption.Rendered = raw, rendered
+	return err
 }
+
+// Description is the description of a media item; older WP-API versions return it as a plain string
+type Description struct {
+	Raw      string `json:"raw,omitempty"`
+	Rendered string `json:"rendered,omitempty"`
+}
+
+func (description *Description) UnmarshalJSON(data []byte) error {
+	raw, rendered, err := unmarshalRenderedField(data)
+	description.Raw, description.Rendered = raw, rendered
+	return err
+}
+
+// unmarshalRenderedField decodes either a `{"raw": ..., "rendered": ...}` object or a plain string,
+// which is then used as both
+func unmarshalRenderedField(data []byte) (string, string, error) {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return s, s, nil
+	}
+	var field struct {
+		Raw      string `json:"raw"`
+		Rendered string `json:"rendered"`
+	}
+	err := json.Unmarshal(data, &field)
+	return field.Raw, field.Rendered, err
+}
+
 type Media struct {
+	collection *MediaCollection `json:"-"`
+
 	ID           int          `json:"id,omitempty"`
 	Date         string       `json:"date,omitempty"`
 	DateGMT      string       `json:"date_gmt,omitempty"`
 	GUID         GUID         `json:"guid,omitempty"`
 	Link         string       `json:"link,omitempty"`
 	Modified     string       `json:"modified,omitempty"`
-	ModifiedGMT  string       `json:"modifiedGMT,omitempty"`
+	ModifiedGMT  string       `json:"modified_gmt,omitempty"`
 	Password     string       `json:"password,omitempty"`
 	Slug         string       `json:"slug,omitempty"`
 	Status       string       `json:"status,omitempty"`
@@ -49,13 +271,138 @@ type Media struct {
 	MediaStatus  string       `json:"comment_status,omitempty"`
 	PingStatus   string       `json:"ping_status,omitempty"`
 	AltText      string       `json:"alt_text,omitempty"`
-	Caption      string       `json:"caption,omitempty"`
-	Description  string       `json:"description,omitempty"`
+	Caption      Caption      `json:"caption,omitempty"`
+	Description  Description  `json:"description,omitempty"`
 	MediaType    string       `json:"media_type,omitempty"`
+	MimeType     string       `json:"mime_type,omitempty"`
 	MediaDetails MediaDetails `json:"media_details,omitempty"`
 	Post         int          `json:"post,omitempty"`
 	SourceURL    string       `json:"source_url,omitempty"`
+
+	// MetaValues are the registered meta keys; see MetaValues
+	MetaValues MetaValues `json:"meta,omitempty"`
+}
+
+// Sizes returns the sizes of an image, including its full size (which older WP-API versions do not list)
+func (media *Media) Sizes() MediaDetailsSizes {
+	sizes := MediaDetailsSizes{}
+	for name, size := range media.MediaDetails.Sizes {
+		sizes[name] = size
+	}
+	if _, ok := sizes[MediaSizeFull]; !ok && media.MediaDetails.Width > 0 {
+		sizes[MediaSizeFull] = MediaDetailsSizesItem{
+			File:      path.Base(media.MediaDetails.File),
+			Width:     media.MediaDetails.Width,
+			Height:    media.MediaDetails.Height,
+			MimeType:  media.MimeType,
+			SourceURL: media.SourceURL,
+		}
+	}
+	return sizes
+}
+
+// BestSize returns the smallest size of an image covering width x height (0 leaves a dimension unconstrained),
+// or its largest size if none does. ok is false if the media has no sizes, for eg. if it is not an image.
+func (media *Media) BestSize(width int, height int) (name string, size MediaDetailsSizesItem, ok bool) {
+	sizes := media.Sizes()
+	var largest string
+	for _, n := range sortedSizeNames(sizes) {
+		s := sizes[n]
+		if s.Width >= width && s.Height >= height && (name == "" || s.Width*s.Height < size.Width*size.Height) {
+			name, size = n, s
+		}
+		largest = n
+	}
+	if name == "" && largest != "" {
+		return largest, sizes[largest], true
+	}
+	return name, size, name != ""
+}
+
+// SrcSet returns the value of a srcset attribute for an image, for eg. "a-300x200.jpg 300w, a.jpg 1200w".
+// Like WordPress, only sizes with the aspect ratio of the full image (give or take a pixel) are listed.
+func (media *Media) SrcSet() string {
+	sizes := media.Sizes()
+	full, ok := sizes[MediaSizeFull]
+	var candidates []string
+	seen := map[int]bool{}
+	for _, name := range sortedSizeNames(sizes) {
+		size := sizes[name]
+		if size.SourceURL == "" || seen[size.Width] {
+			continue
+		}
+		if ok && !sameAspectRatio(size, full) {
+			continue
+		}
+		seen[size.Width] = true
+		sourceURL := strings.NewReplacer(" ", "%20", ",", "%2C").Replace(size.SourceURL)
+		candidates = append(candidates, fmt.Sprintf("%v %vw", sourceURL, size.Width))
+	}
+	return strings.Join(candidates, ", ")
+}
+
+// SizeURL returns the URL of an intermediate size of an image, or of the original file if size is "" or MediaSizeFull
+func (media *Media) SizeURL(size string) (string, error) {
+	if (size == "" || size == MediaSizeFull) && media.SourceURL != "" {
+		return media.SourceURL, nil
+	}
+	if item, ok := media.Sizes()[size]; ok && item.SourceURL != "" {
+		return item.SourceURL, nil
+	}
+	return "", fmt.Errorf("media %v has no %v size", media.ID, size)
 }
+
+// sortedSizeNames returns the names of the given sizes, by ascending width (then height, then name)
+func sortedSizeNames(sizes MediaDetailsSizes) []string {
+	var names []string
+	for name := range sizes {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := sizes[names[i]], sizes[names[j]]
+		if a.Width != b.Width {
+			return a.Width < b.Width
+		}
+		if a.Height != b.Height {
+			return a.Height < b.Height
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+func sameAspectRatio(size MediaDetailsSizesItem, full MediaDetailsSizesItem) bool {
+	if full.Width == 0 || full.Height == 0 {
+		return true
+	}
+	delta := size.Width*full.Height - size.Height*full.Width
+	if delta < 0 {
+		delta = -delta
+	}
+	tolerance := full.Width
+	if full.Height > tolerance {
+		tolerance = full.Height
+	}
+	return delta <= tolerance
+}
+
+func (entity *Media) setCollection(col *MediaCollection) {
+	entity.collection = col
+}
+func (entity *Media) Meta() *MetaCollection {
+	if entity.collection == nil {
+		// missing media.collection parent. Probably Media struct was initialized manually, not fetched from API
+		_warning("Missing parent media collection")
+		return nil
+	}
+	return &MetaCollection{
+		client:     entity.collection.client,
+		parent:     entity,
+		parentType: CollectionMedia,
+		url:        fmt.Sprintf("%v/%v/%v", entity.collection.url, entity.ID, CollectionMeta),
+	}
+}
+
 type MediaCollection struct {
 	client *Client
 	url    string
@@ -64,22 +411,144 @@ type MediaCollection struct {
 func (col *MediaCollection) List(params interface{}) ([]Media, *http.Response, []byte, error) {
 	var media []Media
 	resp, body, err := col.client.List(col.url, params, &media)
+
+	// set collection object for each entity which has sub-collection
+	for i := range media {
+		media[i].setCollection(col)
+	}
+
 	return media, resp, body, err
 }
 func (col *MediaCollection) Create(options *MediaUploadOptions) (*Media, *http.Response, []byte, error) {
+	var hash string
+	if options.Deduplicate {
+		var err error
+		if hash, options, err = options.checksum(); err != nil {
+			return nil, nil, nil, err
+		}
+		existing, resp, body, err := col.findByHash(hash)
+		if err != nil || existing != nil {
+			return existing, resp, body, err
+		}
+	}
+
 	var created Media
-	resp, body, err := col.client.PostData(col.url, options.Data, options.ContentType, options.Filename, &created)
+	upload, length, header, err := options.request()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	resp, body, err := col.client.PostStream(col.url, upload, length, header, &created)
+	created.setCollection(col)
+	if err == nil && hash != "" {
+		err = storeHash(&created, hash)
+	}
 	return &created, resp, body, err
 }
+
+// errHashFound stops paging through the attachments once findByHash found a match
+var errHashFound = errors.New("attachment found")
+
+// findByHash returns the attachment whose content has the given checksum, or nil if there is none.
+// The meta filter only narrows the attachments on sites with the `filter` parameter (removed in WordPress 4.7),
+// so every page of attachments is checked, by the checksums in their `meta` object.
+func (col *MediaCollection) findByHash(hash string) (*Media, *http.Response, []byte, error) {
+	var found *Media
+	var resp *http.Response
+	var body []byte
+	params := fmt.Sprintf("context=edit&filter[meta_key]=%v&filter[meta_value]=%v", MediaHashMetaKey, hash)
+	err := forEachPage(params, func(params string) (*http.Response, int, error) {
+		var candidates []Media
+		var err error
+		candidates, resp, body, err = col.List(params)
+		if err != nil {
+			return resp, 0, err
+		}
+		for i := range candidates {
+			if storedHash(&candidates[i]) == hash {
+				found = &candidates[i]
+				return resp, 0, errHashFound
+			}
+		}
+		return resp, len(candidates), nil
+	})
+	if err != nil && err != errHashFound {
+		return nil, resp, body, err
+	}
+	return found, resp, body, nil
+}
+
+// storedHash returns the checksum in the MediaHashMetaKey meta of an attachment, or "" if the site does not
+// return it in the `meta` object
+func storedHash(media *Media) string {
+	return media.MetaValues.StringValue(MediaHashMetaKey)
+}
+
+// storeHash stores the checksum of an attachment in its MediaHashMetaKey meta, through the `meta` object, or the
+// meta route if the site does not register the key (a failure is then an *UnregisteredMetaError)
+func storeHash(media *Media, hash string) error {
+	if media.collection == nil {
+		return fmt.Errorf("media %v has no collection to store its checksum with", media.ID)
+	}
+	values := MetaValues{MediaHashMetaKey: hash}
+	var updated Media
+	entityURL := fmt.Sprintf("%v/%v", media.collection.url, media.ID)
+	if _, _, err := media.collection.client.Update(entityURL, map[string]interface{}{"meta": values}, &updated); err != nil {
+		return err
+	}
+	err := saveUnregisteredMeta(media.Meta(), values, &updated.MetaValues)
+	media.MetaValues = updated.MetaValues
+	return err
+}
 func (col *MediaCollection) Get(id int, params interface{}) (*Media, *http.Response, []byte, error) {
 	var entity Media
 	entityURL := fmt.Sprintf("%v/%v", col.url, id)
 	resp, body, err := col.client.Get(entityURL, params, &entity)
+	entity.setCollection(col)
 	return &entity, resp, body, err
 }
+func (col *MediaCollection) Update(id int, media *Media) (*Media, *http.Response, []byte, error) {
+	var updated Media
+	entityURL := fmt.Sprintf("%v/%v", col.url, id)
+	resp, body, err := col.client.Update(entityURL, media, &updated)
+	updated.setCollection(col)
+	return &updated, resp, body, err
+}
+
+// Attach sets the post a media item is attached to; a postID of 0 detaches it
+func (col *MediaCollection) Attach(id int, postID int) (*Media, *http.Response, []byte, error) {
+	var updated Media
+	entityURL := fmt.Sprintf("%v/%v", col.url, id)
+	resp, body, err := col.client.Update(entityURL, map[string]interface{}{"post": postID}, &updated)
+	updated.setCollection(col)
+	return &updated, resp, body, err
+}
+
+// Download writes the file of a media item to w. size is the name of an intermediate image size,
+// or "" for the original file. It returns the number of bytes written.
+func (col *MediaCollection) Download(id int, size string, w io.Writer) (int64, *http.Response, error) {
+	media, resp, _, err := col.Get(id, nil)
+	if err != nil {
+		return 0, resp, err
+	}
+	sourceURL, err := media.SizeURL(size)
+	if err != nil {
+		return 0, resp, err
+	}
+	resp, err = col.client.GetStream(sourceURL, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, resp, errors.New(resp.Status)
+	}
+	n, err := io.Copy(w, resp.Body)
+	return n, resp, err
+}
 func (col *MediaCollection) Delete(id int, params interface{}) (*Media, *http.Response, []byte, error) {
 	var deleted Media
 	entityURL := fmt.Sprintf("%v/%v", col.url, id)
 	resp, body, err := col.client.Delete(entityURL, params, &deleted)
+	deleted.setCollection(col)
 	return &deleted, resp, body, err
 }
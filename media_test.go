@@ -1,10 +1,13 @@
 package wordpress_test
 
 import (
+	"encoding/json"
 	"github.com/sogko/go-wordpress"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -153,3 +156,210 @@ func TestMediaCreate(t *testing.T) {
 
 	cleanUpMedia(t, wp, newMedia.ID)
 }
+
+func TestMediaCreate_Stream(t *testing.T) {
+	wp := initTestClient()
+
+	file, err := os.Open("./test-data/test-media.jpg")
+	if err != nil {
+		t.Fatalf("Failed to open test media file to upload: %v", err.Error())
+	}
+	defer file.Close()
+	info, _ := file.Stat()
+
+	var sent, total int64
+	newMedia, resp, _, err := wp.Media().Create(&wordpress.MediaUploadOptions{
+		Filename:    "tëst média.jpg",
+		ContentType: "image/jpeg",
+		// hide the size of the file, to send it chunked
+		Reader: struct{ io.Reader }{file},
+		Progress: func(s int64, t int64) {
+			sent, total = s, t
+		},
+	})
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("Expected 201 Created, got %v", resp.Status)
+	}
+	if sent != info.Size() || total != -1 {
+		t.Errorf("Expected progress to reach %v of an unknown total, got %v of %v", info.Size(), sent, total)
+	}
+	if !strings.Contains(newMedia.SourceURL, "média") {
+		t.Errorf("Expected the non-ASCII filename to be kept, got %v", newMedia.SourceURL)
+	}
+
+	cleanUpMedia(t, wp, newMedia.ID)
+}
+
+func TestMediaCreate_Multipart(t *testing.T) {
+	wp := initTestClient()
+
+	file, err := os.Open("./test-data/test-media.jpg")
+	if err != nil {
+		t.Fatalf("Failed to open test media file to upload: %v", err.Error())
+	}
+	defer file.Close()
+	info, _ := file.Stat()
+
+	var total int64
+	newMedia, resp, _, err := wp.Media().Create(&wordpress.MediaUploadOptions{
+		Filename: "test-media.jpg",
+		Reader:   file,
+		Title:    "Multipart upload",
+		Caption:  "Uploaded with its caption",
+		AltText:  "Alternative text",
+		Progress: func(s int64, t int64) {
+			total = t
+		},
+	})
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("Expected 201 Created, got %v", resp.Status)
+	}
+	if total != info.Size() {
+		t.Errorf("Expected the size of the file to be known, got %v", total)
+	}
+	if newMedia.Title.Raw != "Multipart upload" || newMedia.AltText != "Alternative text" {
+		t.Errorf("Expected the fields to be set, got %v", newMedia)
+	}
+	if newMedia.Caption.Raw != "Uploaded with its caption" {
+		t.Errorf("Expected the caption to be set, got %v", newMedia.Caption)
+	}
+
+	cleanUpMedia(t, wp, newMedia.ID)
+}
+
+func TestMediaUpdate(t *testing.T) {
+	wp := initTestClient()
+
+	newMedia, _, _, err := wp.Media().Create(factoryMediaFileUpload(t))
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+
+	updatedMedia, resp, body, err := wp.Media().Update(newMedia.ID, &wordpress.Media{
+		Title:       wordpress.Title{Raw: "Updated title"},
+		AltText:     "Updated alternative text",
+		Caption:     wordpress.Caption{Raw: "Updated caption"},
+		Description: wordpress.Description{Raw: "Updated description"},
+	})
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 OK, got %v", resp.Status)
+	}
+	if body == nil {
+		t.Errorf("Should not return nil body")
+	}
+	if updatedMedia.ID != newMedia.ID {
+		t.Errorf("Expected the same media, got %v != %v", updatedMedia.ID, newMedia.ID)
+	}
+	if updatedMedia.Title.Raw != "Updated title" || updatedMedia.AltText != "Updated alternative text" {
+		t.Errorf("Expected the fields to be updated, got %v", updatedMedia)
+	}
+	if updatedMedia.Caption.Raw != "Updated caption" || !strings.Contains(updatedMedia.Caption.Rendered, "Updated caption") {
+		t.Errorf("Expected the caption to be updated, got %v", updatedMedia.Caption)
+	}
+	if updatedMedia.Description.Raw != "Updated description" || !strings.Contains(updatedMedia.Description.Rendered, "Updated description") {
+		t.Errorf("Expected the description to be updated, got %v", updatedMedia.Description)
+	}
+
+	cleanUpMedia(t, wp, newMedia.ID)
+}
+
+func TestMediaAttach(t *testing.T) {
+	wp := initTestClient()
+
+	post := getAnyOnePost(t, wp)
+	newMedia, _, _, err := wp.Media().Create(factoryMediaFileUpload(t))
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+
+	attachedMedia, _, _, err := wp.Media().Attach(newMedia.ID, post.ID)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if attachedMedia.Post != post.ID {
+		t.Errorf("Expected the media to be attached to post %v, got %v", post.ID, attachedMedia.Post)
+	}
+
+	detachedMedia, _, _, err := wp.Media().Attach(newMedia.ID, 0)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if detachedMedia.Post != 0 {
+		t.Errorf("Expected the media to be detached, got post %v", detachedMedia.Post)
+	}
+
+	cleanUpMedia(t, wp, newMedia.ID)
+}
+
+func TestCaption_UnmarshalJSON(t *testing.T) {
+	var media wordpress.Media
+	if err := json.Unmarshal([]byte(`{"caption":"plain","description":{"raw":"raw","rendered":"<p>raw</p>\n"}}`), &media); err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if media.Caption.Raw != "plain" || media.Caption.Rendered != "plain" {
+		t.Errorf("Expected a plain string caption to be accepted, got %v", media.Caption)
+	}
+	if media.Description.Raw != "raw" || media.Description.Rendered != "<p>raw</p>\n" {
+		t.Errorf("Expected a raw/rendered description, got %v", media.Description)
+	}
+}
+
+func TestMedia_Sizes(t *testing.T) {
+	var media wordpress.Media
+	err := json.Unmarshal([]byte(`{
+		"source_url": "http://example.com/a.jpg",
+		"mime_type": "image/jpeg",
+		"media_details": {
+			"width": 1200, "height": 800, "file": "2016/01/a.jpg",
+			"sizes": {
+				"thumbnail": {"file": "a-150x150.jpg", "width": 150, "height": 150, "source_url": "http://example.com/a-150x150.jpg"},
+				"medium": {"file": "a-300x200.jpg", "width": 300, "height": 200, "source_url": "http://example.com/a-300x200.jpg"},
+				"medium_large": {"file": "a-768x512.jpg", "width": 768, "height": 512, "source_url": "http://example.com/a-768x512.jpg"},
+				"post-thumbnail": {"file": "a-825x510.jpg", "width": 825, "height": 510, "source_url": "http://example.com/a-825x510.jpg"}
+			}
+		}
+	}`), &media)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if media.MediaDetails.Sizes["post-thumbnail"].Width != 825 {
+		t.Errorf("Expected theme sizes to be decoded, got %v", media.MediaDetails.Sizes)
+	}
+	if full := media.Sizes()[wordpress.MediaSizeFull]; full.Width != 1200 || full.SourceURL != "http://example.com/a.jpg" {
+		t.Errorf("Expected the full size to be added, got %v", full)
+	}
+
+	for _, test := range []struct {
+		width, height int
+		expected      string
+	}{
+		{100, 0, "thumbnail"},
+		{160, 0, "medium"},
+		{0, 500, "medium_large"},
+		{800, 0, "post-thumbnail"},
+		{800, 600, "full"},
+		{2000, 0, "full"},
+	} {
+		if name, _, ok := media.BestSize(test.width, test.height); !ok || name != test.expected {
+			t.Errorf("Expected %v for %vx%v, got %v", test.expected, test.width, test.height, name)
+		}
+	}
+
+	expected := "http://example.com/a-300x200.jpg 300w, http://example.com/a-768x512.jpg 768w, http://example.com/a.jpg 1200w"
+	if srcset := media.SrcSet(); srcset != expected {
+		t.Errorf("Expected srcset %q, got %q", expected, srcset)
+	}
+
+	if _, _, ok := (&wordpress.Media{}).BestSize(100, 100); ok {
+		t.Errorf("Expected no size for a media without sizes")
+	}
+}
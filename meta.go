@@ -1,15 +1,145 @@
 package wordpress
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 )
 
+// Meta is a meta entry. Value is decoded from JSON, so it may be a string (as WordPress stores scalar meta),
+// a number, a boolean, a list or a map; use the typed getters to read it.
 type Meta struct {
-	ID    int    `json:"id,omitempty"`
-	Key   string `json:"key,omitempty"`
-	Value string `json:"value,omitempty"`
+	ID    int         `json:"id,omitempty"`
+	Key   string      `json:"key,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// StringValue returns the value the way WordPress stores it: booleans as "1" or "", lists and maps as JSON
+func (meta Meta) StringValue() string {
+	return metaString(meta.Value)
+}
+
+// IntValue returns the value as an integer, parsing it if it is a string
+func (meta Meta) IntValue() (int, bool) {
+	return metaInt(meta.Value)
+}
+
+// FloatValue returns the value as a number, parsing it if it is a string
+func (meta Meta) FloatValue() (float64, bool) {
+	return metaFloat(meta.Value)
+}
+
+// BoolValue returns the value as a boolean, like rest_sanitize_boolean(): "", "0" and "false" are false
+func (meta Meta) BoolValue() bool {
+	return metaBool(meta.Value)
+}
+
+// DecodeValue decodes the value into v, like json.Unmarshal. A string value holding JSON is decoded too.
+func (meta Meta) DecodeValue(v interface{}) error {
+	return decodeMeta(meta.Value, v)
+}
+
+func metaString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		if v {
+			return "1"
+		}
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, json.Number:
+		return fmt.Sprint(v)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func metaFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func metaInt(value interface{}) (int, bool) {
+	if s, ok := value.(string); ok {
+		if i, err := strconv.Atoi(strings.TrimSpace(s)); err == nil {
+			return i, true
+		}
+	}
+	f, ok := metaFloat(value)
+	if !ok || f != math.Trunc(f) {
+		return 0, false
+	}
+	return int(f), true
+}
+
+func metaBool(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		v = strings.ToLower(strings.TrimSpace(v))
+		return v != "" && v != "0" && v != "false"
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	}
+	f, ok := metaFloat(value)
+	return !ok || f != 0
+}
+
+func decodeMeta(value interface{}, v interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(data, v)
+	if s, ok := value.(string); ok && err != nil {
+		return json.Unmarshal([]byte(s), v)
+	}
+	return err
+}
+
+// metaEqual compares meta values the way WordPress stores them, so that 42 equals "42"
+func metaEqual(a interface{}, b interface{}) bool {
+	return metaString(a) == metaString(b)
 }
 
 type MetaDeletedResponse struct {
@@ -52,3 +182,75 @@ func (col *MetaCollection) Delete(id int, params interface{}) (*MetaDeletedRespo
 	resp, body, err := col.client.Delete(entityURL, params, &response)
 	return &response, resp, body, err
 }
+
+// MetaSyncResult lists the meta entries created, updated and deleted by MetaCollection.Sync
+type MetaSyncResult struct {
+	Created []Meta
+	Updated []Meta
+	Deleted []Meta
+}
+
+// Changed returns true if Sync changed any meta entry
+func (result *MetaSyncResult) Changed() bool {
+	return len(result.Created)+len(result.Updated)+len(result.Deleted) > 0
+}
+
+// Set writes meta values, leaving the other keys alone: missing keys are created, differing values are updated,
+// and the keys set to nil are deleted. Only the first entry of a key is kept.
+func (col *MetaCollection) Set(values map[string]interface{}) (*MetaSyncResult, error) {
+	return col.apply(values, false)
+}
+
+// Sync makes the meta of the parent match desired, which maps keys to values: missing keys are created, differing
+// values are updated, and the keys that are not desired (or the extra entries of a key) are deleted.
+// Values are compared the way WordPress stores them, so that 42 matches "42". Protected meta (with keys starting
+// with `_`) is not listed by the API, and is left alone.
+// Sync stops at the first failing call; the result lists the changes made until then.
+func (col *MetaCollection) Sync(desired map[string]interface{}) (*MetaSyncResult, error) {
+	return col.apply(desired, true)
+}
+
+// apply writes values, deleting the keys that are not in values if deleteOthers is set
+func (col *MetaCollection) apply(values map[string]interface{}, deleteOthers bool) (*MetaSyncResult, error) {
+	existing, _, _, err := col.List(nil)
+	if err != nil {
+		return nil, err
+	}
+	result := &MetaSyncResult{}
+	synced := map[string]bool{}
+	for _, m := range existing {
+		value, ok := values[m.Key]
+		switch {
+		case !ok && !deleteOthers:
+			continue
+		case !ok || value == nil || synced[m.Key]:
+			if _, _, _, err := col.Delete(m.ID, "force=true"); err != nil {
+				return result, fmt.Errorf("deleting meta %v (%v): %v", m.ID, m.Key, err)
+			}
+			result.Deleted = append(result.Deleted, m)
+		case !metaEqual(m.Value, value):
+			updated, _, _, err := col.Update(m.ID, &Meta{Key: m.Key, Value: value})
+			if err != nil {
+				return result, fmt.Errorf("updating meta %v (%v): %v", m.ID, m.Key, err)
+			}
+			result.Updated = append(result.Updated, *updated)
+		}
+		synced[m.Key] = true
+	}
+
+	var keys []string
+	for key, value := range values {
+		if !synced[key] && value != nil {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		created, _, _, err := col.Create(&Meta{Key: key, Value: values[key]})
+		if err != nil {
+			return result, fmt.Errorf("creating meta %v: %v", key, err)
+		}
+		result.Created = append(result.Created, *created)
+	}
+	return result, nil
+}
@@ -0,0 +1,105 @@
+package wordpress_test
+
+import (
+	"github.com/sogko/go-wordpress"
+	"github.com/sogko/go-wordpress/wptest"
+	"testing"
+)
+
+func TestMeta_Values(t *testing.T) {
+	steps := []struct {
+		value    interface{}
+		str      string
+		integer  int
+		isInt    bool
+		boolean  bool
+		hasFloat bool
+	}{
+		{"42", "42", 42, true, true, true},
+		{42.0, "42", 42, true, true, true},
+		{2.5, "2.5", 0, false, true, true},
+		{true, "1", 1, true, true, true},
+		{false, "", 0, true, false, true},
+		{"false", "false", 0, false, false, false},
+		{"0", "0", 0, true, false, true},
+		{nil, "", 0, false, false, false},
+		{[]interface{}{"a", 1.0}, `["a",1]`, 0, false, true, false},
+	}
+	for _, step := range steps {
+		meta := wordpress.Meta{Key: "key", Value: step.value}
+		if s := meta.StringValue(); s != step.str {
+			t.Errorf("%#v: expected string %q, got %q", step.value, step.str, s)
+		}
+		if i, ok := meta.IntValue(); i != step.integer || ok != step.isInt {
+			t.Errorf("%#v: expected int %v (%v), got %v (%v)", step.value, step.integer, step.isInt, i, ok)
+		}
+		if b := meta.BoolValue(); b != step.boolean {
+			t.Errorf("%#v: expected bool %v, got %v", step.value, step.boolean, b)
+		}
+		if _, ok := meta.FloatValue(); ok != step.hasFloat {
+			t.Errorf("%#v: expected a number to be found: %v", step.value, step.hasFloat)
+		}
+	}
+
+	var list []string
+	if err := (wordpress.Meta{Value: []interface{}{"a", "b"}}).DecodeValue(&list); err != nil || len(list) != 2 {
+		t.Errorf("Expected a list, got %v (%v)", list, err)
+	}
+	var settings struct {
+		Width int `json:"width"`
+	}
+	if err := (wordpress.Meta{Value: `{"width":640}`}).DecodeValue(&settings); err != nil || settings.Width != 640 {
+		t.Errorf("Expected JSON in a string to be decoded, got %v (%v)", settings, err)
+	}
+}
+
+func TestMetaSync(t *testing.T) {
+	store := newFixtureStore(t)
+	post := store.AddPost(wptest.Object{"type": wptest.PostTypePost, "title": "Synced", "status": "publish"})
+	id := post.Int("id")
+	store.AddMeta("posts", id, "views", "42")
+	store.AddMeta("posts", id, "color", "red")
+	store.AddMeta("posts", id, "color", "blue")
+	store.AddMeta("posts", id, "obsolete", "1")
+	store.AddMeta("posts", id, "_edit_lock", "1")
+	server := wptest.NewServer(store)
+	defer server.Close()
+	wp := newFakeTestClient(server, nil)
+
+	desired := map[string]interface{}{
+		"views":    42,
+		"color":    "green",
+		"featured": true,
+		"sizes":    []interface{}{"s", "m"},
+	}
+	result, err := wp.Posts().Entity(id).Meta().Sync(desired)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if len(result.Created) != 2 || len(result.Updated) != 1 || len(result.Deleted) != 2 {
+		t.Errorf("Expected 2 created, 1 updated and 2 deleted meta, got %+v", result)
+	}
+	if result.Updated[0].Key != "color" || result.Updated[0].StringValue() != "green" {
+		t.Errorf("Expected the color to be updated, got %v", result.Updated)
+	}
+
+	meta, _, _, _ := wp.Posts().Entity(id).Meta().List(nil)
+	values := map[string]wordpress.Meta{}
+	for _, m := range meta {
+		values[m.Key] = m
+	}
+	if len(meta) != 4 || !values["featured"].BoolValue() || values["sizes"].StringValue() != `["s","m"]` {
+		t.Errorf("Expected the meta to match, got %v", meta)
+	}
+	if len(store.MetaOf("posts", id)) != 5 {
+		t.Errorf("Expected protected meta to be left alone")
+	}
+
+	result, err = wp.Posts().Entity(id).Meta().Sync(desired)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if result.Changed() {
+		t.Errorf("Expected no change syncing again, got %+v", result)
+	}
+}
@@ -0,0 +1,330 @@
+package wordpress
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// MirrorManifestFile is the file, at the root of a mirror, recording the mirrored files
+const MirrorManifestFile = ".wp-mirror.json"
+
+// MirrorStatus is the outcome of mirroring a file
+type MirrorStatus string
+
+const (
+	MirrorDownloaded MirrorStatus = "downloaded"
+	MirrorResumed    MirrorStatus = "resumed"
+	MirrorUnchanged  MirrorStatus = "unchanged"
+	MirrorFailed     MirrorStatus = "failed"
+)
+
+type MirrorOptions struct {
+	// Params filters the media to mirror, for eg. "media_type=image"
+	Params string
+
+	// Sizes are the intermediate image sizes mirrored along with the original files, for eg. "thumbnail";
+	// "*" mirrors every size
+	Sizes []string
+
+	// Progress, if set, is called with the result of each file
+	Progress func(result MirrorResult)
+}
+
+// MirrorResult is the result of mirroring a file of a media item
+type MirrorResult struct {
+	MediaID int
+	Size    string
+	// Path is the path of the file, relative to the mirror directory
+	Path   string
+	Status MirrorStatus
+	// Bytes is the number of bytes downloaded
+	Bytes int64
+	Err   error
+}
+
+// mirrorEntry records a mirrored file in the manifest of a mirror
+type mirrorEntry struct {
+	ID       int    `json:"id"`
+	Modified string `json:"modified"`
+	Size     int64  `json:"size,omitempty"`
+	SHA256   string `json:"sha256,omitempty"`
+
+	// Validator is the ETag or Last-Modified of a partial download, sent as If-Range to resume it
+	Validator string `json:"validator,omitempty"`
+}
+
+type mirror struct {
+	client   *Client
+	dir      string
+	manifest map[string]*mirrorEntry
+}
+
+// MirrorMedia downloads the files of every media item into dir, keeping the layout of the uploads directory
+// (for eg. `2016/01/photo.jpg`). Files are first downloaded to a `.part` file, which is resumed with a range request
+// if the mirror is interrupted, and their SHA-256 checksums recorded in MirrorManifestFile.
+// A file is skipped if its media item was not modified since it was mirrored and its checksum still matches.
+// The original file is verified against the MediaHashMetaKey meta of its media item, if the site returns one (see
+// MediaUploadOptions.Deduplicate); other files are not verified, and their checksums only detect local changes.
+//
+// Failing files do not stop the mirror; they are reported in the results. The returned error is set if
+// the media could not be listed or the manifest could not be written.
+func MirrorMedia(client *Client, dir string, options *MirrorOptions) ([]MirrorResult, error) {
+	if options == nil {
+		options = &MirrorOptions{}
+	}
+	m := &mirror{client: client, dir: dir, manifest: map[string]*mirrorEntry{}}
+	if err := m.load(); err != nil {
+		return nil, err
+	}
+
+	var media []Media
+	err := forEachPage(options.Params, func(params string) (*http.Response, int, error) {
+		page, resp, _, err := client.Media().List(params)
+		media = append(media, page...)
+		return resp, len(page), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []MirrorResult
+	for i := range media {
+		for _, f := range mirrorFiles(&media[i], options.Sizes) {
+			result := m.file(&media[i], f.size, f.url, f.path)
+			if result.Err == errManifest {
+				return results, result.Err
+			}
+			results = append(results, result)
+			if options.Progress != nil {
+				options.Progress(result)
+			}
+		}
+	}
+	return results, nil
+}
+
+type mirrorFile struct {
+	size, url, path string
+}
+
+// mirrorFiles returns the files of a media item to mirror, with their paths relative to the mirror
+func mirrorFiles(media *Media, sizes []string) []mirrorFile {
+	if media.SourceURL == "" {
+		return nil
+	}
+	file := media.MediaDetails.File
+	if file == "" {
+		file = uploadsPath(media.SourceURL, fmt.Sprintf("%v", media.ID))
+	}
+	files := []mirrorFile{{MediaSizeFull, media.SourceURL, file}}
+
+	for _, name := range sortedSizeNames(media.MediaDetails.Sizes) {
+		item := media.MediaDetails.Sizes[name]
+		if name == MediaSizeFull || item.SourceURL == "" || !mirrorSize(sizes, name) {
+			continue
+		}
+		sizePath := path.Join(path.Dir(file), item.File)
+		if item.File == "" {
+			sizePath = uploadsPath(item.SourceURL, path.Dir(file))
+		}
+		files = append(files, mirrorFile{name, item.SourceURL, sizePath})
+	}
+	return files
+}
+
+func mirrorSize(sizes []string, name string) bool {
+	for _, size := range sizes {
+		if size == "*" || size == name {
+			return true
+		}
+	}
+	return false
+}
+
+// uploadsPath returns the path of a URL relative to the uploads directory, or its file name in dir
+func uploadsPath(fileURL string, dir string) string {
+	u, err := neturl.Parse(fileURL)
+	if err != nil {
+		return path.Join(dir, path.Base(fileURL))
+	}
+	if i := strings.Index(u.Path, "/uploads/"); i >= 0 {
+		return u.Path[i+len("/uploads/"):]
+	}
+	return path.Join(dir, path.Base(u.Path))
+}
+
+// errManifest is returned by mirror.file if the manifest could not be written, which stops the mirror
+var errManifest = errors.New("failed to write the mirror manifest")
+
+func (m *mirror) file(media *Media, size string, fileURL string, relPath string) MirrorResult {
+	result := MirrorResult{MediaID: media.ID, Size: size, Status: MirrorFailed}
+
+	// the path comes from the server: keep it inside the mirror
+	relPath = strings.TrimPrefix(path.Clean("/"+relPath), "/")
+	if relPath == "" || relPath == MirrorManifestFile {
+		result.Err = fmt.Errorf("invalid path for media %v: %q", media.ID, relPath)
+		return result
+	}
+	result.Path = relPath
+	local := filepath.Join(m.dir, filepath.FromSlash(relPath))
+	part := local + ".part"
+
+	entry := m.manifest[relPath]
+	sameVersion := entry != nil && entry.ID == media.ID && media.Modified != "" && entry.Modified == media.Modified
+	if sameVersion && entry.SHA256 != "" {
+		if sum, _, err := fileChecksum(local); err == nil && sum == entry.SHA256 {
+			result.Status = MirrorUnchanged
+			return result
+		}
+	}
+
+	var offset int64
+	header := http.Header{}
+	if info, err := os.Stat(part); err == nil && sameVersion && entry.Validator != "" {
+		offset = info.Size()
+		header.Set("Range", fmt.Sprintf("bytes=%v-", offset))
+		header.Set("If-Range", entry.Validator)
+	}
+	resp, err := m.client.GetStream(fileURL, header)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		flags |= os.O_APPEND
+		result.Status = MirrorResumed
+	case resp.StatusCode == http.StatusOK:
+		flags |= os.O_TRUNC
+		offset = 0
+		result.Status = MirrorDownloaded
+	default:
+		if resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+			// start over on the next mirror
+			os.Remove(part)
+		}
+		result.Err = errors.New(resp.Status)
+		return result
+	}
+
+	// record the download before it starts, so it can be resumed
+	entry = &mirrorEntry{ID: media.ID, Modified: media.Modified, Validator: resp.Header.Get("ETag")}
+	if entry.Validator == "" || strings.HasPrefix(entry.Validator, "W/") {
+		entry.Validator = resp.Header.Get("Last-Modified")
+	}
+	m.manifest[relPath] = entry
+	if err := m.save(); err != nil {
+		result.Status, result.Err = MirrorFailed, errManifest
+		return result
+	}
+
+	if err := os.MkdirAll(filepath.Dir(local), 0755); err != nil {
+		result.Status, result.Err = MirrorFailed, err
+		return result
+	}
+	f, err := os.OpenFile(part, flags, 0644)
+	if err != nil {
+		result.Status, result.Err = MirrorFailed, err
+		return result
+	}
+	result.Bytes, err = io.Copy(f, resp.Body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && resp.ContentLength >= 0 && result.Bytes != resp.ContentLength {
+		err = fmt.Errorf("short download: %v of %v bytes", result.Bytes, resp.ContentLength)
+	}
+	if err != nil {
+		result.Status, result.Err = MirrorFailed, err
+		return result
+	}
+
+	sum, n, err := fileChecksum(part)
+	if err == nil && size == MediaSizeFull {
+		err = m.verify(media, sum, part)
+	}
+	if err == nil {
+		err = os.Rename(part, local)
+	}
+	if err != nil {
+		result.Status, result.Err = MirrorFailed, err
+		return result
+	}
+	entry.SHA256, entry.Size, entry.Validator = sum, n, ""
+	if err := m.save(); err != nil {
+		result.Status, result.Err = MirrorFailed, errManifest
+	}
+	return result
+}
+
+// verify compares the checksum of a downloaded original file with the one stored on the media item, if any.
+// A file that does not match is removed, to be downloaded again on the next mirror.
+func (m *mirror) verify(media *Media, sum string, part string) error {
+	stored := storedHash(media)
+	if stored == "" || stored == sum {
+		return nil
+	}
+	os.Remove(part)
+	return fmt.Errorf("checksum mismatch for media %v: got %v, expected %v", media.ID, sum, stored)
+}
+
+func (m *mirror) load() error {
+	data, err := ioutil.ReadFile(filepath.Join(m.dir, MirrorManifestFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &m.manifest)
+}
+
+// save writes the manifest through a temporary file, so an interrupted mirror does not leave it truncated
+func (m *mirror) save() error {
+	data, err := json.MarshalIndent(m.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return err
+	}
+	filename := filepath.Join(m.dir, MirrorManifestFile)
+	if err := ioutil.WriteFile(filename+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(filename+".tmp", filename)
+}
+
+// fileChecksum returns the hex-encoded SHA-256 checksum and the size of a file
+func fileChecksum(filename string) (string, int64, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	return checksum(f)
+}
+
+// checksum returns the hex-encoded SHA-256 checksum of the content of a reader, and its size
+func checksum(r io.Reader) (string, int64, error) {
+	h := sha256.New()
+	n, err := io.Copy(h, r)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
@@ -0,0 +1,204 @@
+package wordpress_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/sogko/go-wordpress"
+	"github.com/sogko/go-wordpress/wptest"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// cuttingTransport fails the download of uploaded files after Limit bytes
+type cuttingTransport struct {
+	Limit int
+}
+
+func (t *cuttingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err == nil && strings.HasPrefix(req.URL.Path, wptest.UploadsPath) {
+		resp.Body = &cutReader{ReadCloser: resp.Body, left: t.Limit}
+	}
+	return resp, err
+}
+
+type cutReader struct {
+	io.ReadCloser
+	left int
+}
+
+func (r *cutReader) Read(p []byte) (int, error) {
+	if r.left <= 0 {
+		return 0, errors.New("connection reset")
+	}
+	if len(p) > r.left {
+		p = p[:r.left]
+	}
+	n, err := r.ReadCloser.Read(p)
+	r.left -= n
+	return n, err
+}
+
+func countStatus(results []wordpress.MirrorResult, status wordpress.MirrorStatus) int {
+	n := 0
+	for _, result := range results {
+		if result.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+func TestMediaDownload(t *testing.T) {
+	server := newFixtureServer(t)
+	defer server.Close()
+	wp := newFakeTestClient(server, nil)
+
+	upload := factoryMediaFileUpload(t)
+	media, _, _, err := wp.Media().Create(upload)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+
+	var buf bytes.Buffer
+	n, resp, err := wp.Media().Download(media.ID, "thumbnail", &buf)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if resp.StatusCode != http.StatusOK || n != int64(len(upload.Data)) || !bytes.Equal(buf.Bytes(), upload.Data) {
+		t.Errorf("Expected the thumbnail to be downloaded, got %v (%v bytes)", resp.Status, n)
+	}
+
+	if _, _, err := wp.Media().Download(media.ID, "no-such-size", ioutil.Discard); err == nil {
+		t.Errorf("Expected an error for an unknown size")
+	}
+}
+
+func TestMirrorMedia(t *testing.T) {
+	server := newFixtureServer(t)
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "go-wordpress-mirror")
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	upload := factoryMediaFileUpload(t)
+	media, _, _, err := newFakeTestClient(server, nil).Media().Create(upload)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+
+	// the first mirror is interrupted after 100 bytes of each file
+	options := &wordpress.MirrorOptions{Sizes: []string{"thumbnail"}}
+	results, err := wordpress.MirrorMedia(newFakeTestClient(server, &cuttingTransport{Limit: 100}), dir, options)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	// the fixture image and the upload, with their thumbnails
+	if len(results) != 4 || countStatus(results, wordpress.MirrorFailed) != 4 {
+		t.Fatalf("Expected 4 failed files, got %v", results)
+	}
+
+	transport := &countingTransport{}
+	wp := newFakeTestClient(server, transport)
+	results, err = wordpress.MirrorMedia(wp, dir, options)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if countStatus(results, wordpress.MirrorResumed) != 4 {
+		t.Fatalf("Expected 4 resumed files, got %v", results)
+	}
+	for _, result := range results {
+		var expected bytes.Buffer
+		wp.Media().Download(result.MediaID, result.Size, &expected)
+		if result.Bytes != int64(expected.Len()-100) {
+			t.Errorf("Expected the rest of %v to be downloaded, got %v bytes", result.Path, result.Bytes)
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, result.Path))
+		if err != nil || !bytes.Equal(data, expected.Bytes()) {
+			t.Errorf("Expected %v to be mirrored, got %v bytes (%v)", result.Path, len(data), err)
+		}
+	}
+
+	// unchanged files are not downloaded again, unless they were altered locally
+	var uploaded string
+	for _, result := range results {
+		if result.MediaID == media.ID && result.Size == wordpress.MediaSizeFull {
+			uploaded = filepath.Join(dir, result.Path)
+		}
+	}
+	ioutil.WriteFile(uploaded, []byte("corrupted"), 0644)
+	requests := len(transport.Requests())
+	results, _ = wordpress.MirrorMedia(wp, dir, options)
+	if countStatus(results, wordpress.MirrorUnchanged) != 3 || countStatus(results, wordpress.MirrorDownloaded) != 1 {
+		t.Errorf("Expected only the altered file to be downloaded, got %v", results)
+	}
+	if n := len(transport.Requests()) - requests; n != 2 {
+		t.Errorf("Expected a list and a file request, got %v", n)
+	}
+
+	// modified media are downloaded again
+	if _, _, _, err := wp.Media().Update(media.ID, &wordpress.Media{AltText: "Modified"}); err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	results, _ = wordpress.MirrorMedia(wp, dir, options)
+	if countStatus(results, wordpress.MirrorDownloaded) != 2 {
+		t.Errorf("Expected the files of the modified media to be downloaded, got %v", results)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, wordpress.MirrorManifestFile)); err != nil {
+		t.Errorf("Expected the manifest to be written: %v", err)
+	}
+}
+
+func TestMirrorMedia_Verify(t *testing.T) {
+	server := newFixtureServer(t)
+	defer server.Close()
+	server.Store.RegisterMeta("post", wordpress.MediaHashMetaKey)
+	dir, err := ioutil.TempDir("", "go-wordpress-mirror")
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	defer os.RemoveAll(dir)
+	wp := newFakeTestClient(server, nil)
+
+	upload := factoryMediaFileUpload(t)
+	upload.Deduplicate = true
+	verified, _, _, err := wp.Media().Create(upload)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	other := factoryMediaFileUpload(t)
+	other.Filename = "other.jpg"
+	mismatched, _, _, err := wp.Media().Create(other)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	server.Store.AddMeta("media", mismatched.ID, wordpress.MediaHashMetaKey, strings.Repeat("0", 64))
+
+	results, err := wordpress.MirrorMedia(wp, dir, nil)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	for _, result := range results {
+		switch result.MediaID {
+		case verified.ID:
+			if result.Status != wordpress.MirrorDownloaded {
+				t.Errorf("Expected the verified file to be downloaded, got %v (%v)", result.Status, result.Err)
+			}
+		case mismatched.ID:
+			if result.Status != wordpress.MirrorFailed || result.Err == nil || !strings.Contains(result.Err.Error(), "checksum mismatch") {
+				t.Errorf("Expected a checksum mismatch, got %v (%v)", result.Status, result.Err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, result.Path)); !os.IsNotExist(err) {
+				t.Errorf("Expected the mismatched file not to be kept")
+			}
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package wordpress
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type OrphanedMediaOptions struct {
+	// Params filters the media to check, for eg. "media_type=image"
+	Params string
+
+	// Trash, if set, moves the orphaned attachments to the trash. They are never deleted permanently;
+	// trashing attachments requires `define( 'MEDIA_TRASH', true );` on the site.
+	Trash bool
+}
+
+// OrphanedMedia is an attachment found by FindOrphanedMedia
+type OrphanedMedia struct {
+	Media Media
+
+	// Unverified is set when unpublished posts and pages could not be checked, so the attachment may be used by one
+	Unverified bool
+
+	// Trashed is set once the attachment was moved to the trash; Err is the error trashing it, if any
+	Trashed bool
+	Err     error
+}
+
+var (
+	// imageClass matches the class WordPress adds to inserted images, for eg. `wp-image-12`
+	imageClass = regexp.MustCompile(`\bwp-image-(\d+)\b`)
+
+	// galleryIDs matches the attachment IDs of `[gallery ids="1,2,3"]` shortcodes
+	galleryIDs = regexp.MustCompile(`\[gallery[^\]]*\bids=["']?([\d,\s]+)`)
+)
+
+// FindOrphanedMedia reports the attachments that are not attached to a post, not used as the featured image
+// of a post or page, and not referenced from the raw or rendered content of the posts, pages and media
+// (by the URL of their file or of one of their sizes, a `wp-image-{id}` class, or a gallery shortcode).
+// Posts and pages of any status but trash are checked, which requires a user who can edit them; otherwise only
+// published content is checked, the orphans are reported as Unverified, and trashing is refused.
+func FindOrphanedMedia(client *Client, options *OrphanedMediaOptions) ([]OrphanedMedia, error) {
+	if options == nil {
+		options = &OrphanedMediaOptions{}
+	}
+
+	mediaParams := "context=edit"
+	if options.Params != "" {
+		mediaParams += "&" + options.Params
+	}
+	var media []Media
+	err := forEachPage(mediaParams, func(params string) (*http.Response, int, error) {
+		page, resp, _, err := client.Media().List(params)
+		media = append(media, page...)
+		return resp, len(page), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// what references media: content, and featured images. Without the permission to list unpublished posts and
+	// pages, only published content is checked; the media used by drafts may then look orphaned, so none is trashed.
+	unverified := false
+	contents, featured, err := listReferences(client, "context=edit&status=any")
+	if err != nil {
+		if options.Trash {
+			return nil, fmt.Errorf("refusing to trash media, unpublished posts and pages cannot be listed: %v", err)
+		}
+		unverified = true
+		if contents, featured, err = listReferences(client, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	// IDs referenced by image classes and galleries; media may reference each other (for eg. in a description),
+	// but not themselves
+	referencedIDs := map[int]bool{}
+	for _, content := range contents {
+		for _, id := range contentReferences(content) {
+			referencedIDs[id] = true
+		}
+	}
+	mediaContents := map[int][]string{}
+	for _, m := range media {
+		mediaContents[m.ID] = []string{m.Caption.Raw, m.Caption.Rendered, m.Description.Raw, m.Description.Rendered}
+		for _, content := range mediaContents[m.ID] {
+			for _, id := range contentReferences(content) {
+				if id != m.ID {
+					referencedIDs[id] = true
+				}
+			}
+		}
+	}
+
+	var orphans []OrphanedMedia
+	for _, m := range media {
+		if m.Post != 0 || featured[m.ID] || referencedIDs[m.ID] {
+			continue
+		}
+		paths := mediaPaths(&m)
+		referenced := containsAny(contents, paths)
+		for id, content := range mediaContents {
+			if referenced {
+				break
+			}
+			referenced = id != m.ID && containsAny(content, paths)
+		}
+		if referenced {
+			continue
+		}
+
+		orphan := OrphanedMedia{Media: m, Unverified: unverified}
+		if options.Trash {
+			if _, _, _, err := client.Media().Delete(m.ID, nil); err != nil {
+				orphan.Err = fmt.Errorf("failed to trash media %v: %v", m.ID, err)
+			} else {
+				orphan.Trashed = true
+			}
+		}
+		orphans = append(orphans, orphan)
+	}
+	return orphans, nil
+}
+
+// listReferences returns the contents of the posts and pages listed with params, and their featured images
+func listReferences(client *Client, params string) ([]string, map[int]bool, error) {
+	var contents []string
+	featured := map[int]bool{}
+	err := forEachPage(params, func(params string) (*http.Response, int, error) {
+		posts, resp, _, err := client.Posts().List(params)
+		for _, post := range posts {
+			contents = append(contents, post.Content.Raw, post.Content.Rendered, post.Excerpt.Raw, post.Excerpt.Rendered)
+			featured[post.FeaturedImage] = true
+		}
+		return resp, len(posts), err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	err = forEachPage(params, func(params string) (*http.Response, int, error) {
+		pages, resp, _, err := client.Pages().List(params)
+		for _, page := range pages {
+			contents = append(contents, page.Content.Raw, page.Content.Rendered, page.Excerpt.Raw, page.Excerpt.Rendered)
+			featured[page.FeaturedImage] = true
+		}
+		return resp, len(pages), err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return contents, featured, nil
+}
+
+// mediaPaths returns the paths of the files of an attachment relative to the uploads directory,
+// so that both absolute and relative URLs in content match
+func mediaPaths(media *Media) []string {
+	var paths []string
+	if media.SourceURL != "" {
+		paths = append(paths, uploadsPath(media.SourceURL, ""))
+	}
+	dir := path.Dir(media.MediaDetails.File)
+	for _, size := range media.MediaDetails.Sizes {
+		if size.SourceURL != "" {
+			paths = append(paths, uploadsPath(size.SourceURL, dir))
+		}
+	}
+	return paths
+}
+
+// contentReferences returns the IDs of the attachments referenced by `wp-image-{id}` classes and
+// `[gallery ids="1,2,3"]` shortcodes in content
+func contentReferences(content string) []int {
+	var ids []int
+	for _, match := range imageClass.FindAllStringSubmatch(content, -1) {
+		if id, err := strconv.Atoi(match[1]); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	for _, match := range galleryIDs.FindAllStringSubmatch(content, -1) {
+		for _, id := range strings.Split(match[1], ",") {
+			if id, err := strconv.Atoi(strings.TrimSpace(id)); err == nil {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+func containsAny(contents []string, needles []string) bool {
+	for _, content := range contents {
+		for _, needle := range needles {
+			if needle != "" && strings.Contains(content, needle) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package wordpress_test
+
+import (
+	"fmt"
+	"github.com/sogko/go-wordpress"
+	"github.com/sogko/go-wordpress/wptest"
+	"strings"
+	"testing"
+)
+
+func TestFindOrphanedMedia(t *testing.T) {
+	store := newFixtureStore(t)
+	server := wptest.NewServer(store)
+	defer server.Close()
+	wp := newFakeTestClient(server, nil)
+
+	upload := func(name string) *wordpress.Media {
+		options := factoryMediaFileUpload(t)
+		options.Filename = name + ".jpg"
+		media, _, _, err := wp.Media().Create(options)
+		if err != nil {
+			t.Fatalf("Should not return error: %v", err.Error())
+		}
+		return media
+	}
+	unused := upload("unused")
+	attached := upload("attached")
+	featured := upload("featured")
+	linked := upload("linked")
+	inserted := upload("inserted")
+	gallery := upload("gallery")
+	selfReferenced := upload("self-referenced")
+
+	wp.Media().Attach(attached.ID, 1)
+	wp.Media().Update(selfReferenced.ID, &wordpress.Media{
+		Description: wordpress.Description{Raw: fmt.Sprintf(`<img src="%v" class="wp-image-%v">`, selfReferenced.SourceURL, selfReferenced.ID)},
+	})
+	wp.Posts().Create(&wordpress.Post{
+		Title:         wordpress.Title{Raw: "Featured"},
+		Status:        "draft",
+		FeaturedImage: featured.ID,
+		// a relative URL
+		Content: wordpress.Content{Raw: fmt.Sprintf(`<a href="%v">Linked</a>`, strings.TrimPrefix(linked.SourceURL, server.URL))},
+	})
+	wp.Pages().Create(&wordpress.Page{
+		Title:   wordpress.Title{Raw: "Gallery"},
+		Status:  "publish",
+		Content: wordpress.Content{Raw: fmt.Sprintf(`<img class="size-full wp-image-%v"> [gallery ids="1, %v"]`, inserted.ID, gallery.ID)},
+	})
+
+	orphaned := func(orphans []wordpress.OrphanedMedia) map[int]wordpress.OrphanedMedia {
+		ids := map[int]wordpress.OrphanedMedia{}
+		for _, orphan := range orphans {
+			ids[orphan.Media.ID] = orphan
+		}
+		return ids
+	}
+
+	orphans, err := wordpress.FindOrphanedMedia(wp, nil)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	ids := orphaned(orphans)
+	for _, media := range []*wordpress.Media{unused, selfReferenced} {
+		if _, ok := ids[media.ID]; !ok {
+			t.Errorf("Expected %v to be orphaned", media.Slug)
+		}
+	}
+	for _, media := range []*wordpress.Media{attached, featured, linked, inserted, gallery} {
+		if _, ok := ids[media.ID]; ok {
+			t.Errorf("Expected %v not to be orphaned", media.Slug)
+		}
+	}
+
+	// without MEDIA_TRASH, attachments cannot be trashed; they are not deleted either
+	orphans, _ = wordpress.FindOrphanedMedia(wp, &wordpress.OrphanedMediaOptions{Trash: true})
+	if orphan := orphaned(orphans)[unused.ID]; orphan.Trashed || orphan.Err == nil {
+		t.Errorf("Expected trashing to fail, got %v", orphan)
+	}
+	if _, _, _, err := wp.Media().Get(unused.ID, nil); err != nil {
+		t.Errorf("Expected the media to be kept: %v", err)
+	}
+
+	store.MediaTrash = true
+	orphans, _ = wordpress.FindOrphanedMedia(wp, &wordpress.OrphanedMediaOptions{Trash: true})
+	if orphan := orphaned(orphans)[unused.ID]; !orphan.Trashed || orphan.Err != nil {
+		t.Errorf("Expected the media to be trashed, got %v", orphan)
+	}
+	trashed, _, _, _ := wp.Media().Get(unused.ID, "context=edit")
+	if trashed.Status != "trash" {
+		t.Errorf("Expected the media to be in the trash, got %v", trashed.Status)
+	}
+	orphans, _ = wordpress.FindOrphanedMedia(wp, nil)
+	if _, ok := orphaned(orphans)[unused.ID]; ok {
+		t.Errorf("Expected trashed media not to be reported again")
+	}
+}
+
+func TestFindOrphanedMedia_Unpublished(t *testing.T) {
+	store := newFixtureStore(t)
+	store.MediaTrash = true
+	store.AddUser(wptest.Object{"username": "writer", "email": "writer@example.com", "password": "secret", "roles": []string{wptest.RoleAuthor}})
+	server := wptest.NewServer(store)
+	defer server.Close()
+	wp := newFakeTestClient(server, nil)
+
+	media, _, _, err := wp.Media().Create(factoryMediaFileUpload(t))
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	wp.Posts().Create(&wordpress.Post{Title: wordpress.Title{Raw: "Draft"}, Status: "draft", FeaturedImage: media.ID})
+
+	// an author cannot list unpublished pages, and the draft using the media is not seen
+	writer := wordpress.NewClient(&wordpress.Options{BaseAPIURL: server.BaseAPIURL(), Username: "writer", Password: "secret"})
+	if _, err := wordpress.FindOrphanedMedia(writer, &wordpress.OrphanedMediaOptions{Trash: true}); err == nil {
+		t.Errorf("Expected trashing to be refused")
+	}
+	if trashed, _, _, _ := wp.Media().Get(media.ID, "context=edit"); trashed.Status == "trash" {
+		t.Errorf("Expected the media not to be trashed")
+	}
+	orphans, err := wordpress.FindOrphanedMedia(writer, nil)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	found := false
+	for _, orphan := range orphans {
+		found = found || orphan.Media.ID == media.ID
+		if !orphan.Unverified {
+			t.Errorf("Expected %v to be unverified", orphan.Media.Slug)
+		}
+	}
+	if !found {
+		t.Errorf("Expected the media used by the draft to look orphaned")
+	}
+
+	orphans, _ = wordpress.FindOrphanedMedia(wp, nil)
+	for _, orphan := range orphans {
+		if orphan.Media.ID == media.ID || orphan.Unverified {
+			t.Errorf("Expected the media used by a draft not to be orphaned, got %v", orphan.Media.Slug)
+		}
+	}
+}
@@ -29,6 +29,9 @@ type Page struct {
 	PingStatus    string  `json:"ping_status,omitempty"`
 	MenuOrder     int     `json:"menu_order,omitempty"`
 	Template      string  `json:"template,omitempty"`
+
+	// MetaValues are the registered meta keys; see MetaValues
+	MetaValues MetaValues `json:"meta,omitempty"`
 }
 
 func (entity *Page) setCollection(col *PagesCollection) {
@@ -60,6 +63,19 @@ func (entity *Page) Revisions() *RevisionsCollection {
 		url:        fmt.Sprintf("%v/%v/%v", entity.collection.url, entity.ID, CollectionRevisions),
 	}
 }
+func (entity *Page) Autosaves() *AutosavesCollection {
+	if entity.collection == nil {
+		// missing page.collection parent. Probably Page struct was initialized manually, not fetched from API
+		_warning("Missing parent page collection")
+		return nil
+	}
+	return &AutosavesCollection{
+		client:     entity.collection.client,
+		parent:     entity,
+		parentType: CollectionPages,
+		url:        fmt.Sprintf("%v/%v/%v", entity.collection.url, entity.ID, CollectionAutosaves),
+	}
+}
 
 func (entity *Page) Populate(params interface{}) (*Page, *http.Response, []byte, error) {
 	return entity.collection.Get(entity.ID, params)
@@ -87,6 +103,9 @@ func (col *PagesCollection) Create(new *Page) (*Page, *http.Response, []byte, er
 	resp, body, err := col.client.Create(col.url, new, &created)
 
 	created.setCollection(col)
+	if err == nil {
+		err = saveUnregisteredMeta(created.Meta(), new.MetaValues, &created.MetaValues)
+	}
 
 	return &created, resp, body, err
 }
@@ -115,6 +134,9 @@ func (col *PagesCollection) Update(id int, page *Page) (*Page, *http.Response, [
 
 	// set collection object for each entity which has sub-collection
 	updated.setCollection(col)
+	if err == nil {
+		err = saveUnregisteredMeta(updated.Meta(), page.MetaValues, &updated.MetaValues)
+	}
 
 	return &updated, resp, body, err
 }
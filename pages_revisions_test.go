@@ -34,7 +34,7 @@ func TestPagesRevisions_InvalidCall(t *testing.T) {
 	invalidPage := wordpress.Page{}
 	invalidRevisions := invalidPage.Revisions()
 	if invalidRevisions != nil {
-		t.Error("Expected revisions to be nil, %v", invalidRevisions)
+		t.Errorf("Expected revisions to be nil, %v", invalidRevisions)
 	}
 }
 
@@ -53,7 +53,7 @@ type Excerpt struct {
 }
 
 type Post struct {
-	collection *PostsCollection `json:"-,omitempty"`
+	collection *PostsCollection `json:"-"`
 
 	ID            int     `json:"id,omitempty"`
 	Date          string  `json:"date,omitempty"`
@@ -75,6 +75,9 @@ type Post struct {
 	PingStatus    string  `json:"ping_status,omitempty"`
 	Format        string  `json:"format,omitempty"`
 	Sticky        bool    `json:"sticky,omitempty"`
+
+	// MetaValues are the registered meta keys; see MetaValues
+	MetaValues MetaValues `json:"meta,omitempty"`
 }
 
 func (entity *Post) setCollection(col *PostsCollection) {
@@ -106,6 +109,19 @@ func (entity *Post) Revisions() *RevisionsCollection {
 		url:        fmt.Sprintf("%v/%v/%v", entity.collection.url, entity.ID, CollectionRevisions),
 	}
 }
+func (entity *Post) Autosaves() *AutosavesCollection {
+	if entity.collection == nil {
+		// missing post.collection parent. Probably Post struct was initialized manually, not fetched from API
+		_warning("Missing parent post collection")
+		return nil
+	}
+	return &AutosavesCollection{
+		client:     entity.collection.client,
+		parent:     entity,
+		parentType: CollectionPosts,
+		url:        fmt.Sprintf("%v/%v/%v", entity.collection.url, entity.ID, CollectionAutosaves),
+	}
+}
 func (entity *Post) Terms() *PostsTermsCollection {
 	if entity.collection == nil {
 		// missing post.collection parent. Probably Post struct was initialized manually, not fetched from API
@@ -145,6 +161,9 @@ func (col *PostsCollection) Create(new *Post) (*Post, *http.Response, []byte, er
 	resp, body, err := col.client.Create(col.url, new, &created)
 
 	created.setCollection(col)
+	if err == nil {
+		err = saveUnregisteredMeta(created.Meta(), new.MetaValues, &created.MetaValues)
+	}
 
 	return &created, resp, body, err
 }
@@ -173,6 +192,9 @@ func (col *PostsCollection) Update(id int, post *Post) (*Post, *http.Response, [
 
 	// set collection object for each entity which has sub-collection
 	updated.setCollection(col)
+	if err == nil {
+		err = saveUnregisteredMeta(updated.Meta(), post.MetaValues, &updated.MetaValues)
+	}
 
 	return &updated, resp, body, err
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/sogko/go-wordpress"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 )
@@ -34,7 +35,7 @@ func TestPostsRevisions_InvalidCall(t *testing.T) {
 	invalidPost := wordpress.Post{}
 	invalidRevisions := invalidPost.Revisions()
 	if invalidRevisions != nil {
-		t.Error("Expected revisions to be nil, %v", invalidRevisions)
+		t.Errorf("Expected revisions to be nil, %v", invalidRevisions)
 	}
 }
 
@@ -167,3 +168,62 @@ func TestPostsRevisionsDelete_Lazy(t *testing.T) {
 		t.Errorf("Should not return false (bool) response")
 	}
 }
+
+func TestPostsRevisionsDiffAndRestore(t *testing.T) {
+	wp := initTestClient()
+
+	post, resp, _, _ := wp.Posts().Create(&wordpress.Post{
+		Title:  wordpress.Title{Raw: "Revisions"},
+		Status: "draft",
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201 Created, got %v", resp.Status)
+	}
+	defer cleanUpPost(t, post.ID)
+	// each update saves a revision
+	// plain content, which is rendered with <p> markup, and a shortcode
+	for _, content := range []string{"The quick brown fox\n\n[gallery ids=\"1\"]", "The quick red fox\n\n[gallery ids=\"1\"]"} {
+		post.Content.Raw = content
+		post, resp, _, _ = wp.Posts().Update(post.ID, post)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected 200 OK, got %v", resp.Status)
+		}
+	}
+	revisions, _, _, _ := post.Revisions().List(nil)
+	if len(revisions) < 1 {
+		t.Fatalf("Should not return empty revisions")
+	}
+	oldest := revisions[len(revisions)-1]
+
+	diff, err := post.Revisions().Diff(oldest.ID, wordpress.LiveRevision, wordpress.DiffWords)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if diff.From != oldest.ID || diff.To != wordpress.LiveRevision {
+		t.Errorf("Expected a diff from %v to the live post, got %v to %v", oldest.ID, diff.From, diff.To)
+	}
+	if diff.Title.Changed() {
+		t.Errorf("Expected the title not to change, got %v", diff.Title)
+	}
+	if !diff.Content.Changed() || !strings.Contains(diff.Content.String(), "[-brown-]{+red+}") {
+		t.Errorf("Expected the content to change, got %v", diff.Content)
+	}
+
+	resp, body, err := post.Revisions().Restore(oldest.ID)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if body == nil {
+		t.Errorf("Should not return nil body")
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 OK, got %v", resp.Status)
+	}
+	if post.Content.Raw != "The quick brown fox\n\n[gallery ids=\"1\"]" || post.Title.Raw != "Revisions" {
+		t.Errorf("Expected the raw content to be restored in place, got %q", post.Content.Raw)
+	}
+	diff, _ = post.Revisions().Diff(oldest.ID, wordpress.LiveRevision, wordpress.DiffLines)
+	if diff.Title.Changed() || diff.Content.Changed() || diff.Excerpt.Changed() {
+		t.Errorf("Expected no difference after restoring, got %v", diff.Content)
+	}
+}
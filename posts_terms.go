@@ -29,14 +29,14 @@ func (col *PostsTermsCollection) List(taxonomy string, params interface{}) ([]Po
 	resp, body, err := col.client.List(url, params, &terms)
 	return terms, resp, body, err
 }
-func (col *PostsTermsCollection) Tag() *PostsTermsTaxonomyCollection {
+func (col *PostsTermsCollection) Tag() PostsTermsTaxonomyService {
 	return &PostsTermsTaxonomyCollection{
 		client:       col.client,
 		url:          fmt.Sprintf("%v/tag", col.url),
 		taxonomyBase: "tag",
 	}
 }
-func (col *PostsTermsCollection) Category() *PostsTermsTaxonomyCollection {
+func (col *PostsTermsCollection) Category() PostsTermsTaxonomyService {
 	return &PostsTermsTaxonomyCollection{
 		client:       col.client,
 		url:          fmt.Sprintf("%v/category", col.url),
@@ -0,0 +1,107 @@
+package wordpress
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MetaValues is the `meta` object of posts, pages, users, comments and terms, holding the meta keys registered
+// with `show_in_rest` (WordPress 4.7+). Values set to nil are deleted when the entity is updated.
+type MetaValues map[string]interface{}
+
+// UnmarshalJSON accepts the empty list WordPress returns when no meta key is registered, as PHP encodes empty arrays
+func (values *MetaValues) UnmarshalJSON(data []byte) error {
+	if strings.TrimSpace(string(data)) == "[]" {
+		*values = nil
+		return nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	*values = m
+	return nil
+}
+
+// Has returns true if the meta object holds the key
+func (values MetaValues) Has(key string) bool {
+	_, ok := values[key]
+	return ok
+}
+
+// StringValue returns the value of the key the way WordPress stores it, see Meta.StringValue
+func (values MetaValues) StringValue(key string) string {
+	return metaString(values[key])
+}
+
+// IntValue returns the value of the key as an integer, parsing it if it is a string
+func (values MetaValues) IntValue(key string) (int, bool) {
+	return metaInt(values[key])
+}
+
+// FloatValue returns the value of the key as a number, parsing it if it is a string
+func (values MetaValues) FloatValue(key string) (float64, bool) {
+	return metaFloat(values[key])
+}
+
+// BoolValue returns the value of the key as a boolean, see Meta.BoolValue
+func (values MetaValues) BoolValue(key string) bool {
+	return metaBool(values[key])
+}
+
+// DecodeValue decodes the value of the key into v, see Meta.DecodeValue
+func (values MetaValues) DecodeValue(key string, v interface{}) error {
+	return decodeMeta(values[key], v)
+}
+
+// UnregisteredMetaError is returned by Create() and Update() when the entity was saved, but some of its meta
+// values were not registered and could not be written through the meta route either. The saved entity is returned
+// with it, so the call should not be retried as a whole; Keys can be written again with Meta().Set().
+type UnregisteredMetaError struct {
+	Keys []string
+
+	// Err is the error writing the keys through the meta route, or nil if the entity has no meta route
+	Err error
+}
+
+func (e *UnregisteredMetaError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("meta %v is not registered", strings.Join(e.Keys, ", "))
+	}
+	return fmt.Sprintf("meta %v is not registered, and writing it through the meta route failed: %v", strings.Join(e.Keys, ", "), e.Err)
+}
+
+// saveUnregisteredMeta writes the meta values that the site left out of the `meta` object it returned through the
+// meta route of the entity, for sites that do not register the keys (or predate WordPress 4.7) but have the meta
+// endpoints plugin. The values written are added to returned; a failure is an *UnregisteredMetaError.
+func saveUnregisteredMeta(col *MetaCollection, sent MetaValues, returned *MetaValues) error {
+	missing := map[string]interface{}{}
+	var keys []string
+	for key, value := range sent {
+		if !returned.Has(key) {
+			missing[key] = value
+			keys = append(keys, key)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Strings(keys)
+	if col == nil {
+		return &UnregisteredMetaError{Keys: keys}
+	}
+	if _, err := col.Set(missing); err != nil {
+		return &UnregisteredMetaError{Keys: keys, Err: err}
+	}
+	if *returned == nil {
+		*returned = MetaValues{}
+	}
+	for key, value := range missing {
+		if value != nil {
+			(*returned)[key] = value
+		}
+	}
+	return nil
+}
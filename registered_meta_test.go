@@ -0,0 +1,132 @@
+package wordpress_test
+
+import (
+	"encoding/json"
+	"github.com/sogko/go-wordpress"
+	"github.com/sogko/go-wordpress/wptest"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMetaValues_Unmarshal(t *testing.T) {
+	var post wordpress.Post
+	if err := json.Unmarshal([]byte(`{"id":1,"meta":[]}`), &post); err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if post.MetaValues != nil {
+		t.Errorf("Expected no meta values, got %v", post.MetaValues)
+	}
+	if err := json.Unmarshal([]byte(`{"id":1,"meta":{"views":"12","rating":4.5,"featured":true}}`), &post); err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if views, ok := post.MetaValues.IntValue("views"); !ok || views != 12 {
+		t.Errorf("Expected 12 views, got %v", post.MetaValues["views"])
+	}
+	if rating, _ := post.MetaValues.FloatValue("rating"); rating != 4.5 || !post.MetaValues.BoolValue("featured") {
+		t.Errorf("Unexpected meta values: %v", post.MetaValues)
+	}
+	if post.MetaValues.Has("missing") || post.MetaValues.StringValue("missing") != "" {
+		t.Errorf("Expected a missing key to be empty")
+	}
+}
+
+func TestPostsMetaValues(t *testing.T) {
+	store := newFixtureStore(t)
+	store.RegisterMeta("post", "views")
+	server := wptest.NewServer(store)
+	defer server.Close()
+	wp := newFakeTestClient(server, nil)
+
+	// `legacy` is not registered, and is written through the meta route
+	post, _, _, err := wp.Posts().Create(&wordpress.Post{
+		Title:      wordpress.Title{Raw: "Counted"},
+		Status:     wordpress.PostStatusPublish,
+		MetaValues: wordpress.MetaValues{"views": 3, "legacy": "kept"},
+	})
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if views, _ := post.MetaValues.IntValue("views"); views != 3 || post.MetaValues.StringValue("legacy") != "kept" {
+		t.Errorf("Expected both meta values, got %v", post.MetaValues)
+	}
+	fetched, _, _, _ := wp.Posts().Get(post.ID, "context=edit")
+	if len(fetched.MetaValues) != 1 || !fetched.MetaValues.Has("views") {
+		t.Errorf("Expected only the registered key in the meta object, got %v", fetched.MetaValues)
+	}
+	if len(store.MetaOf("posts", post.ID)) != 2 {
+		t.Errorf("Expected two meta entries, got %v", store.MetaOf("posts", post.ID))
+	}
+
+	updated, _, _, err := wp.Posts().Update(post.ID, &wordpress.Post{MetaValues: wordpress.MetaValues{"views": nil, "legacy": "changed"}})
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if updated.MetaValues["views"] != nil || updated.MetaValues.StringValue("legacy") != "changed" {
+		t.Errorf("Expected the views to be deleted and legacy changed, got %v", updated.MetaValues)
+	}
+	meta := store.MetaOf("posts", post.ID)
+	if len(meta) != 1 || meta[0].Value != "changed" {
+		t.Errorf("Expected a single meta entry, got %v", meta)
+	}
+
+	// keys registered for `post` apply to pages too
+	page, _, _, err := wp.Pages().Create(&wordpress.Page{Title: wordpress.Title{Raw: "Plain"}, MetaValues: wordpress.MetaValues{"legacy": 1}})
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if page.MetaValues.StringValue("legacy") != "1" {
+		t.Errorf("Expected the value written through the meta route, got %v", page.MetaValues)
+	}
+	if fetched, _, _, _ := wp.Pages().Get(page.ID, "context=edit"); len(fetched.MetaValues) != 1 || fetched.MetaValues["views"] != nil {
+		t.Errorf("Expected the registered key to be unset, got %v", fetched.MetaValues)
+	}
+}
+
+func TestUsersMetaValues(t *testing.T) {
+	store := newFixtureStore(t)
+	store.RegisterMeta("user", "team")
+	server := wptest.NewServer(store)
+	defer server.Close()
+	wp := newFakeTestClient(server, nil)
+
+	user, _, _, err := wp.Users().Create(&wordpress.User{
+		Username:   "member",
+		Email:      "member@example.com",
+		Password:   "secret",
+		MetaValues: wordpress.MetaValues{"team": "docs", "legacy": "kept"},
+	})
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if user.MetaValues.StringValue("team") != "docs" || user.MetaValues.StringValue("legacy") != "kept" {
+		t.Errorf("Expected both meta values, got %v", user.MetaValues)
+	}
+	if meta, _, _, _ := user.Meta().List(nil); len(meta) != 2 {
+		t.Errorf("Expected two meta entries, got %v", meta)
+	}
+}
+
+func TestPostsMetaValues_Unregistered(t *testing.T) {
+	// a site without the meta route, which leaves unregistered keys out of the meta object
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/posts" {
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 1, "meta": {"views": 3}}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code": "rest_no_route", "message": "No route was found matching the URL and request method"}`))
+	}))
+	defer server.Close()
+	wp := wordpress.NewClient(&wordpress.Options{BaseAPIURL: server.URL})
+
+	post, _, _, err := wp.Posts().Create(&wordpress.Post{MetaValues: wordpress.MetaValues{"views": 3, "legacy": "lost"}})
+	metaErr, ok := err.(*wordpress.UnregisteredMetaError)
+	if !ok || len(metaErr.Keys) != 1 || metaErr.Keys[0] != "legacy" || metaErr.Err == nil {
+		t.Fatalf("Expected an *UnregisteredMetaError for the legacy key, got %v", err)
+	}
+	if post.ID != 1 || post.MetaValues.Has("legacy") {
+		t.Errorf("Expected the created post without the unregistered key, got %v", post)
+	}
+}
@@ -1,10 +1,15 @@
 package wordpress
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
+// LiveRevision stands for the current title, content and excerpt of the parent post or page in RevisionsCollection.Diff
+const LiveRevision = 0
+
 type Revision struct {
 	ID          int    `json:"id,omitempty"`
 	Author      string `json:"author,omitempty"` // TODO: File a WP-API bug, why am I getting string instead of int?
@@ -20,6 +25,15 @@ type Revision struct {
 	Excerpt     string `json:"excerpt,omitempty"`
 }
 
+// RevisionDiff is the difference between two revisions, returned by RevisionsCollection.Diff
+type RevisionDiff struct {
+	From    int
+	To      int
+	Title   TextDiff
+	Content TextDiff
+	Excerpt TextDiff
+}
+
 type RevisionsCollection struct {
 	client     *Client
 	url        string
@@ -47,3 +61,65 @@ func (col *RevisionsCollection) Delete(id int, params interface{}) (bool, *http.
 	resp, body, err := col.client.Delete(entityURL, "force=true", &response)
 	return response, resp, body, err
 }
+
+// Diff compares the title, content and excerpt of two revisions, either of which may be LiveRevision.
+// Revisions only have rendered fields, so they are compared with the rendered fields of the parent.
+func (col *RevisionsCollection) Diff(fromID int, toID int, granularity DiffGranularity) (*RevisionDiff, error) {
+	from, err := col.fields(fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := col.fields(toID)
+	if err != nil {
+		return nil, err
+	}
+	return &RevisionDiff{
+		From:    fromID,
+		To:      toID,
+		Title:   DiffText(from.Title, to.Title, granularity),
+		Content: DiffText(from.Content, to.Content, granularity),
+		Excerpt: DiffText(from.Excerpt, to.Excerpt, granularity),
+	}, nil
+}
+
+// Restore writes the raw title, content and excerpt of a revision back to its parent post or page with Update().
+// The parent (the entity Revisions() was called on) is updated in place from the response.
+// The revision is fetched in the `edit` context; an error is returned if the site does not send its raw fields,
+// as writing the rendered fields back would add the markup of wpautop() and expanded shortcodes to the parent.
+func (col *RevisionsCollection) Restore(id int) (*http.Response, []byte, error) {
+	var revision map[string]json.RawMessage
+	resp, body, err := col.client.Get(fmt.Sprintf("%v/%v", col.url, id), "context=edit", &revision)
+	if err != nil {
+		return resp, body, err
+	}
+	fields := map[string]interface{}{}
+	for _, field := range []string{"title", "content", "excerpt"} {
+		var value struct {
+			Raw *string `json:"raw"`
+		}
+		if err := json.Unmarshal(revision[field], &value); err != nil || value.Raw == nil {
+			return resp, body, fmt.Errorf("revision %v has no raw %v, and cannot be restored", id, field)
+		}
+		fields[field] = *value.Raw
+	}
+	return col.client.Update(col.parentURL(), fields, col.parent)
+}
+
+// fields returns a revision, or the rendered fields of the parent for LiveRevision
+func (col *RevisionsCollection) fields(id int) (*Revision, error) {
+	if id != LiveRevision {
+		revision, _, _, err := col.Get(id, nil)
+		return revision, err
+	}
+	var parent struct {
+		Title   Title   `json:"title"`
+		Content Content `json:"content"`
+		Excerpt Excerpt `json:"excerpt"`
+	}
+	_, _, err := col.client.Get(col.parentURL(), nil, &parent)
+	return &Revision{Title: parent.Title.Rendered, Content: parent.Content.Rendered, Excerpt: parent.Excerpt.Rendered}, err
+}
+
+func (col *RevisionsCollection) parentURL() string {
+	return strings.TrimSuffix(col.url, "/"+CollectionRevisions)
+}
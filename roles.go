@@ -0,0 +1,160 @@
+package wordpress
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Role is a user role
+type Role string
+
+const (
+	RoleAdministrator Role = "administrator"
+	RoleEditor        Role = "editor"
+	RoleAuthor        Role = "author"
+	RoleContributor   Role = "contributor"
+	RoleSubscriber    Role = "subscriber"
+)
+
+// HasRole returns true if the user has the role
+func (user *User) HasRole(role Role) bool {
+	for _, r := range user.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Can returns true if the user has the capability, for eg. "publish_posts".
+// Capabilities are only returned in the `edit` context, so the user must be fetched with "context=edit".
+func (user *User) Can(capability string) bool {
+	if granted, ok := user.ExtraCapabilities[capability]; ok {
+		return isGranted(granted)
+	}
+	return isGranted(user.Capabilities[capability])
+}
+
+// isGranted interprets a capability value, which WordPress returns as a boolean (but may store as 1 or "1")
+func isGranted(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v == "1" || v == "true"
+	}
+	return false
+}
+
+// SetRoles replaces the roles of a user
+func (col *UsersCollection) SetRoles(id int, roles ...Role) (*User, *http.Response, []byte, error) {
+	if len(roles) == 0 {
+		return nil, nil, nil, fmt.Errorf("user %v must have a role", id)
+	}
+	var updated User
+	entityURL := fmt.Sprintf("%v/%v", col.url, id)
+	resp, body, err := col.client.Update(entityURL, map[string]interface{}{"roles": roles}, &updated)
+	updated.setCollection(col)
+	return &updated, resp, body, err
+}
+
+// AddRole adds a role to a user, keeping the roles it has
+func (col *UsersCollection) AddRole(id int, role Role) (*User, *http.Response, []byte, error) {
+	user, resp, body, err := col.Get(id, "context=edit")
+	if err != nil || user.HasRole(role) {
+		return user, resp, body, err
+	}
+	return col.SetRoles(id, append(user.Roles, role)...)
+}
+
+// RemoveRole removes a role from a user. The last role of a user cannot be removed, as the API ignores
+// empty roles; use SetRoles to replace it instead.
+func (col *UsersCollection) RemoveRole(id int, role Role) (*User, *http.Response, []byte, error) {
+	user, resp, body, err := col.Get(id, "context=edit")
+	if err != nil || !user.HasRole(role) {
+		return user, resp, body, err
+	}
+	var roles []Role
+	for _, r := range user.Roles {
+		if r != role {
+			roles = append(roles, r)
+		}
+	}
+	return col.SetRoles(id, roles...)
+}
+
+// CapabilityReport lists which users hold which roles and capabilities
+type CapabilityReport struct {
+	Users []User
+
+	// Roles and Capabilities map each role and capability to the users holding it, sorted by ID
+	Roles        map[Role][]User
+	Capabilities map[string][]User
+}
+
+// UsersWith returns the users holding a capability
+func (report *CapabilityReport) UsersWith(capability string) []User {
+	return report.Capabilities[capability]
+}
+
+// String formats the report with a line per capability, for eg. `publish_posts: admin, editor`
+func (report *CapabilityReport) String() string {
+	var capabilities []string
+	for capability := range report.Capabilities {
+		capabilities = append(capabilities, capability)
+	}
+	sort.Strings(capabilities)
+	var lines []string
+	for _, capability := range capabilities {
+		var names []string
+		for _, user := range report.Capabilities[capability] {
+			names = append(names, user.Username)
+		}
+		lines = append(lines, fmt.Sprintf("%v: %v", capability, strings.Join(names, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// CapabilityReport lists the users of the site (across pages, filtered by params) and the roles and capabilities
+// they hold. It requires a user who can list users in the `edit` context.
+func (col *UsersCollection) CapabilityReport(params string) (*CapabilityReport, error) {
+	query := "context=edit"
+	if params != "" {
+		query += "&" + params
+	}
+	report := &CapabilityReport{Roles: map[Role][]User{}, Capabilities: map[string][]User{}}
+	err := forEachPage(query, func(params string) (*http.Response, int, error) {
+		users, resp, _, err := col.List(params)
+		report.Users = append(report.Users, users...)
+		return resp, len(users), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(report.Users, func(i, j int) bool {
+		return report.Users[i].ID < report.Users[j].ID
+	})
+
+	for _, user := range report.Users {
+		for _, role := range user.Roles {
+			report.Roles[role] = append(report.Roles[role], user)
+		}
+		granted := map[string]bool{}
+		for _, capabilities := range []map[string]interface{}{user.Capabilities, user.ExtraCapabilities} {
+			for capability := range capabilities {
+				// roles are listed among capabilities too
+				if !user.HasRole(Role(capability)) && user.Can(capability) {
+					granted[capability] = true
+				}
+			}
+		}
+		for capability := range granted {
+			report.Capabilities[capability] = append(report.Capabilities[capability], user)
+		}
+	}
+	return report, nil
+}
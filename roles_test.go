@@ -0,0 +1,85 @@
+package wordpress_test
+
+import (
+	"github.com/sogko/go-wordpress"
+	"github.com/sogko/go-wordpress/wptest"
+	"strings"
+	"testing"
+)
+
+func TestUser_Can(t *testing.T) {
+	user := wordpress.User{
+		Roles:             []wordpress.Role{wordpress.RoleEditor},
+		Capabilities:      map[string]interface{}{"edit_posts": true, "editor": true, "manage_options": false, "upload_files": true},
+		ExtraCapabilities: map[string]interface{}{"editor": true, "upload_files": false, "export": "1"},
+	}
+	for capability, expected := range map[string]bool{
+		"edit_posts":     true,
+		"manage_options": false,
+		"upload_files":   false, // denied to the user
+		"export":         true,  // granted to the user
+		"delete_users":   false,
+	} {
+		if user.Can(capability) != expected {
+			t.Errorf("Expected Can(%v) to be %v", capability, expected)
+		}
+	}
+	if !user.HasRole(wordpress.RoleEditor) || user.HasRole(wordpress.RoleAdministrator) {
+		t.Errorf("Expected the user to be an editor only, got %v", user.Roles)
+	}
+}
+
+func TestUsersRoles(t *testing.T) {
+	store := newFixtureStore(t)
+	author := store.AddUser(wptest.Object{"username": "writer", "email": "writer@example.com", "roles": []string{wptest.RoleAuthor}})
+	store.AddUser(wptest.Object{"username": "reader", "email": "reader@example.com"})
+	server := wptest.NewServer(store)
+	defer server.Close()
+	wp := newFakeTestClient(server, nil)
+	id := author.Int("id")
+
+	user, _, _, err := wp.Users().AddRole(id, wordpress.RoleEditor)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if !user.HasRole(wordpress.RoleAuthor) || !user.HasRole(wordpress.RoleEditor) || !user.Can("moderate_comments") {
+		t.Errorf("Expected the user to be an author and an editor, got %v", user.Roles)
+	}
+	user, _, _, err = wp.Users().RemoveRole(id, wordpress.RoleEditor)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if len(user.Roles) != 1 || user.Can("moderate_comments") {
+		t.Errorf("Expected the user to be an author only, got %v", user.Roles)
+	}
+	if _, _, _, err := wp.Users().RemoveRole(id, wordpress.RoleAuthor); err == nil {
+		t.Errorf("Expected an error removing the last role")
+	}
+	user, _, _, err = wp.Users().SetRoles(id, wordpress.RoleContributor)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if len(user.Roles) != 1 || !user.HasRole(wordpress.RoleContributor) || user.Can("publish_posts") {
+		t.Errorf("Expected the user to be a contributor, got %v", user.Roles)
+	}
+	if _, _, _, err := wp.Users().SetRoles(id, "overlord"); err == nil {
+		t.Errorf("Expected an error setting an invalid role")
+	}
+
+	report, err := wp.Users().CapabilityReport("")
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if len(report.Users) != 3 || len(report.Roles[wordpress.RoleSubscriber]) != 1 {
+		t.Errorf("Expected 3 users and a subscriber, got %v and %v", len(report.Users), report.Roles)
+	}
+	if holders := report.UsersWith("edit_posts"); len(holders) != 2 || holders[1].ID != id {
+		t.Errorf("Expected the admin and the contributor to edit posts, got %v", holders)
+	}
+	if _, ok := report.Capabilities[string(wordpress.RoleContributor)]; ok {
+		t.Errorf("Expected roles not to be listed as capabilities")
+	}
+	if !strings.Contains(report.String(), "manage_options: "+wptest.DefaultUsername+"\n") {
+		t.Errorf("Expected only the admin to manage options, got %v", report)
+	}
+}
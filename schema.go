@@ -0,0 +1,93 @@
+package wordpress
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// SchemaTypes holds the JSON Schema `type` of a property.
+// WP-API emits either a single type (`"string"`) or a list of types (`["string", "null"]`).
+type SchemaTypes []string
+
+func (t *SchemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = SchemaTypes{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+	*t = SchemaTypes(multiple)
+	return nil
+}
+
+// Has returns true if the given type is one of the allowed types
+func (t SchemaTypes) Has(name string) bool {
+	for _, v := range t {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+type SchemaProperty struct {
+	Description string                    `json:"description,omitempty"`
+	Type        SchemaTypes               `json:"type,omitempty"`
+	Format      string                    `json:"format,omitempty"`
+	Enum        []interface{}             `json:"enum,omitempty"`
+	Context     []string                  `json:"context,omitempty"`
+	ReadOnly    bool                      `json:"readonly,omitempty"`
+	Required    bool                      `json:"required,omitempty"`
+	Default     interface{}               `json:"default,omitempty"`
+	Properties  map[string]SchemaProperty `json:"properties,omitempty"`
+	Items       *SchemaProperty           `json:"items,omitempty"`
+}
+
+// Schema is the JSON Schema describing a resource, as returned in a route's `schema` field
+type Schema struct {
+	Schema     string                    `json:"$schema,omitempty"`
+	Title      string                    `json:"title,omitempty"`
+	Type       SchemaTypes               `json:"type,omitempty"`
+	Properties map[string]SchemaProperty `json:"properties,omitempty"`
+}
+
+type RouteEndpoint struct {
+	Methods []string                  `json:"methods,omitempty"`
+	Args    map[string]SchemaProperty `json:"args,omitempty"`
+}
+
+// Route describes a single WP-API route, as listed in the API index or returned by an OPTIONS request to the route
+type Route struct {
+	Namespace string          `json:"namespace,omitempty"`
+	Methods   []string        `json:"methods,omitempty"`
+	Endpoints []RouteEndpoint `json:"endpoints,omitempty"`
+	Schema    *Schema         `json:"schema,omitempty"`
+}
+
+// Index is the API index returned by the API root (`/wp-json`) or a namespace root (`/wp-json/wp/v2`)
+type Index struct {
+	Name        string           `json:"name,omitempty"`
+	Description string           `json:"description,omitempty"`
+	URL         string           `json:"url,omitempty"`
+	Namespace   string           `json:"namespace,omitempty"`
+	Namespaces  []string         `json:"namespaces,omitempty"`
+	Routes      map[string]Route `json:"routes,omitempty"`
+}
+
+// Index retrieves the API index at the client's BaseAPIURL.
+// Pass `context=help` as params to have the server include each route's schema.
+func (client *Client) Index(params interface{}) (*Index, *http.Response, []byte, error) {
+	var index Index
+	resp, body, err := client.Get(client.baseURL, params, &index)
+	return &index, resp, body, err
+}
+
+// Route retrieves the description and schema of the route at the given URL (OPTIONS request)
+func (client *Client) Route(url string) (*Route, *http.Response, []byte, error) {
+	var route Route
+	resp, body, err := client.Options(url, &route)
+	return &route, resp, body, err
+}
@@ -0,0 +1,198 @@
+package wordpress
+
+import (
+	"io"
+	"net/http"
+)
+
+//go:generate go run ./cmd/wpmock -o wpmock/mocks.go
+
+// API is the set of services a Client provides; depend on it (instead of *Client)
+// to substitute the mocks of package wpmock in tests.
+type API interface {
+	Users() UsersService
+	Posts() PostsService
+	Pages() PagesService
+	Media() MediaService
+	Comments() CommentsService
+	Taxonomies() TaxonomiesService
+	Terms() TermsService
+	Statuses() StatusesService
+	Types() TypesService
+	Index(params interface{}) (*Index, *http.Response, []byte, error)
+	Route(url string) (*Route, *http.Response, []byte, error)
+}
+
+// UsersService is implemented by UsersCollection
+type UsersService interface {
+	Me(params interface{}) (*User, *http.Response, []byte, error)
+	List(params interface{}) ([]User, *http.Response, []byte, error)
+	Create(new *User) (*User, *http.Response, []byte, error)
+	Get(id int, params interface{}) (*User, *http.Response, []byte, error)
+	Entity(id int) *User
+	Update(id int, user *User) (*User, *http.Response, []byte, error)
+	Delete(id int, params interface{}) (*User, *http.Response, []byte, error)
+	DeleteWithOptions(id int, options *UserDeleteOptions) (*UserDeletion, error)
+	SetRoles(id int, roles ...Role) (*User, *http.Response, []byte, error)
+	AddRole(id int, role Role) (*User, *http.Response, []byte, error)
+	RemoveRole(id int, role Role) (*User, *http.Response, []byte, error)
+	CapabilityReport(params string) (*CapabilityReport, error)
+}
+
+// PostsService is implemented by PostsCollection
+type PostsService interface {
+	List(params interface{}) ([]Post, *http.Response, []byte, error)
+	Create(new *Post) (*Post, *http.Response, []byte, error)
+	Get(id int, params interface{}) (*Post, *http.Response, []byte, error)
+	Entity(id int) *Post
+	Update(id int, post *Post) (*Post, *http.Response, []byte, error)
+	Delete(id int, params interface{}) (*Post, *http.Response, []byte, error)
+}
+
+// PagesService is implemented by PagesCollection
+type PagesService interface {
+	List(params interface{}) ([]Page, *http.Response, []byte, error)
+	Create(new *Page) (*Page, *http.Response, []byte, error)
+	Get(id int, params interface{}) (*Page, *http.Response, []byte, error)
+	Entity(id int) *Page
+	Update(id int, page *Page) (*Page, *http.Response, []byte, error)
+	Delete(id int, params interface{}) (*Page, *http.Response, []byte, error)
+}
+
+// MediaService is implemented by MediaCollection
+type MediaService interface {
+	List(params interface{}) ([]Media, *http.Response, []byte, error)
+	Create(options *MediaUploadOptions) (*Media, *http.Response, []byte, error)
+	Get(id int, params interface{}) (*Media, *http.Response, []byte, error)
+	Update(id int, media *Media) (*Media, *http.Response, []byte, error)
+	Attach(id int, postID int) (*Media, *http.Response, []byte, error)
+	Download(id int, size string, w io.Writer) (int64, *http.Response, error)
+	Delete(id int, params interface{}) (*Media, *http.Response, []byte, error)
+}
+
+// CommentsService is implemented by CommentsCollection
+type CommentsService interface {
+	List(params interface{}) ([]Comment, *http.Response, []byte, error)
+	Create(new *Comment) (*Comment, *http.Response, []byte, error)
+	Get(id int, params interface{}) (*Comment, *http.Response, []byte, error)
+	Entity(id int) *Comment
+	Update(id int, comment *Comment) (*Comment, *http.Response, []byte, error)
+	Delete(id int, params interface{}) (*Comment, *http.Response, []byte, error)
+	Tree(postID int, params string) (*CommentTree, error)
+	Approve(id int) (*Comment, *http.Response, []byte, error)
+	Unapprove(id int) (*Comment, *http.Response, []byte, error)
+	Spam(id int) (*Comment, *http.Response, []byte, error)
+	Trash(id int) (*Comment, *http.Response, []byte, error)
+	Restore(id int) (*Comment, *http.Response, []byte, error)
+	Moderate(id int, action CommentAction) (*Comment, *http.Response, []byte, error)
+	BulkModerate(filter *CommentFilter, action CommentAction) ([]ModerationResult, error)
+	RunModeration(pipeline *ModerationPipeline) (*ModerationReport, error)
+}
+
+// TaxonomiesService is implemented by TaxonomiesCollection
+type TaxonomiesService interface {
+	List(params interface{}) (map[string]Taxonomy, *http.Response, []byte, error)
+	Get(slug string, params interface{}) (*Taxonomy, *http.Response, []byte, error)
+}
+
+// TermsService is implemented by TermsCollection
+type TermsService interface {
+	List(taxonomy string, params interface{}) ([]Term, *http.Response, []byte, error)
+	Tag() TermsTaxonomyService
+	Category() TermsTaxonomyService
+}
+
+// TermsTaxonomyService is implemented by TermsTaxonomyCollection
+type TermsTaxonomyService interface {
+	List(params interface{}) ([]Term, *http.Response, []byte, error)
+	Create(new *Term) (*Term, *http.Response, []byte, error)
+	Get(id int, params interface{}) (*Term, *http.Response, []byte, error)
+	Entity(id int) *Term
+	Update(id int, term *Term) (*Term, *http.Response, []byte, error)
+	Delete(id int, params interface{}) (*Term, *http.Response, []byte, error)
+}
+
+// StatusesService is implemented by StatusesCollection
+type StatusesService interface {
+	List(params interface{}) (*Statuses, *http.Response, []byte, error)
+	Get(slug string, params interface{}) (*Status, *http.Response, []byte, error)
+}
+
+// TypesService is implemented by TypesCollection
+type TypesService interface {
+	List(params interface{}) (*Types, *http.Response, []byte, error)
+	Get(slug string, params interface{}) (*Type, *http.Response, []byte, error)
+}
+
+// MetaService is implemented by MetaCollection
+type MetaService interface {
+	List(params interface{}) ([]Meta, *http.Response, []byte, error)
+	Create(new *Meta) (*Meta, *http.Response, []byte, error)
+	Get(id int, params interface{}) (*Meta, *http.Response, []byte, error)
+	Update(id int, meta *Meta) (*Meta, *http.Response, []byte, error)
+	Delete(id int, params interface{}) (*MetaDeletedResponse, *http.Response, []byte, error)
+	Set(values map[string]interface{}) (*MetaSyncResult, error)
+	Sync(desired map[string]interface{}) (*MetaSyncResult, error)
+}
+
+// RevisionsService is implemented by RevisionsCollection
+type RevisionsService interface {
+	List(params interface{}) ([]Revision, *http.Response, []byte, error)
+	Get(id int, params interface{}) (*Revision, *http.Response, []byte, error)
+	Delete(id int, params interface{}) (bool, *http.Response, []byte, error)
+	Diff(fromID int, toID int, granularity DiffGranularity) (*RevisionDiff, error)
+	Restore(id int) (*http.Response, []byte, error)
+}
+
+// AutosavesService is implemented by AutosavesCollection
+type AutosavesService interface {
+	List(params interface{}) ([]Autosave, *http.Response, []byte, error)
+	Get(id int, params interface{}) (*Autosave, *http.Response, []byte, error)
+	Create(new *Autosave) (*Autosave, *http.Response, []byte, error)
+	Preview(changes *Autosave) (*Preview, *http.Response, []byte, error)
+}
+
+// ApplicationPasswordsService is implemented by ApplicationPasswordsCollection
+type ApplicationPasswordsService interface {
+	List(params interface{}) ([]ApplicationPassword, *http.Response, []byte, error)
+	Create(new *ApplicationPassword) (*ApplicationPassword, *http.Response, []byte, error)
+	Get(uuid string, params interface{}) (*ApplicationPassword, *http.Response, []byte, error)
+	Introspect(params interface{}) (*ApplicationPassword, *http.Response, []byte, error)
+	Revoke(uuid string) (*ApplicationPasswordDeletedResponse, *http.Response, []byte, error)
+	Rotate(uuid string) (*ApplicationPassword, error)
+}
+
+// PostsTermsService is implemented by PostsTermsCollection
+type PostsTermsService interface {
+	List(taxonomy string, params interface{}) ([]PostsTerm, *http.Response, []byte, error)
+	Tag() PostsTermsTaxonomyService
+	Category() PostsTermsTaxonomyService
+}
+
+// PostsTermsTaxonomyService is implemented by PostsTermsTaxonomyCollection
+type PostsTermsTaxonomyService interface {
+	List(params interface{}) ([]PostsTerm, *http.Response, []byte, error)
+	Create(id int) (*PostsTerm, *http.Response, []byte, error)
+	Get(id int, params interface{}) (*PostsTerm, *http.Response, []byte, error)
+	Delete(id int, params interface{}) (*PostsTerm, *http.Response, []byte, error)
+}
+
+var (
+	_ API                         = (*Client)(nil)
+	_ UsersService                = (*UsersCollection)(nil)
+	_ PostsService                = (*PostsCollection)(nil)
+	_ PagesService                = (*PagesCollection)(nil)
+	_ MediaService                = (*MediaCollection)(nil)
+	_ CommentsService             = (*CommentsCollection)(nil)
+	_ TaxonomiesService           = (*TaxonomiesCollection)(nil)
+	_ TermsService                = (*TermsCollection)(nil)
+	_ TermsTaxonomyService        = (*TermsTaxonomyCollection)(nil)
+	_ StatusesService             = (*StatusesCollection)(nil)
+	_ TypesService                = (*TypesCollection)(nil)
+	_ MetaService                 = (*MetaCollection)(nil)
+	_ RevisionsService            = (*RevisionsCollection)(nil)
+	_ AutosavesService            = (*AutosavesCollection)(nil)
+	_ ApplicationPasswordsService = (*ApplicationPasswordsCollection)(nil)
+	_ PostsTermsService           = (*PostsTermsCollection)(nil)
+	_ PostsTermsTaxonomyService   = (*PostsTermsTaxonomyCollection)(nil)
+)
@@ -6,6 +6,8 @@ import (
 )
 
 type Term struct {
+	collection *TermsTaxonomyCollection `json:"-"`
+
 	ID          int    `json:"id,omitempty"`
 	Count       int    `json:"integer,omitempty"`
 	Description string `json:"description,omitempty"`
@@ -14,7 +16,28 @@ type Term struct {
 	Slug        string `json:"slug,omitempty"`
 	Taxonomy    string `json:"taxonomy,omitempty"`
 	Parent      int    `json:"parent,omitempty"`
+
+	// MetaValues are the registered meta keys; see MetaValues
+	MetaValues MetaValues `json:"meta,omitempty"`
 }
+
+func (entity *Term) setCollection(col *TermsTaxonomyCollection) {
+	entity.collection = col
+}
+func (entity *Term) Meta() *MetaCollection {
+	if entity.collection == nil {
+		// missing term.collection parent. Probably Term struct was initialized manually, not fetched from API
+		_warning("Missing parent term collection")
+		return nil
+	}
+	return &MetaCollection{
+		client:     entity.collection.client,
+		parent:     entity,
+		parentType: CollectionTerms,
+		url:        fmt.Sprintf("%v/%v/%v", entity.collection.url, entity.ID, CollectionMeta),
+	}
+}
+
 type TermsCollection struct {
 	client *Client
 	url    string
@@ -24,16 +47,23 @@ func (col *TermsCollection) List(taxonomy string, params interface{}) ([]Term, *
 	var terms []Term
 	url := fmt.Sprintf("%v/%v", col.url, taxonomy)
 	resp, body, err := col.client.List(url, params, &terms)
+
+	// set collection object for each entity which has sub-collection
+	taxonomyCol := &TermsTaxonomyCollection{client: col.client, url: url, taxonomyBase: taxonomy}
+	for i := range terms {
+		terms[i].setCollection(taxonomyCol)
+	}
+
 	return terms, resp, body, err
 }
-func (col *TermsCollection) Tag() *TermsTaxonomyCollection {
+func (col *TermsCollection) Tag() TermsTaxonomyService {
 	return &TermsTaxonomyCollection{
 		client:       col.client,
 		url:          fmt.Sprintf("%v/tag", col.url),
 		taxonomyBase: "tag",
 	}
 }
-func (col *TermsCollection) Category() *TermsTaxonomyCollection {
+func (col *TermsCollection) Category() TermsTaxonomyService {
 	return &TermsTaxonomyCollection{
 		client:       col.client,
 		url:          fmt.Sprintf("%v/category", col.url),
@@ -50,28 +80,51 @@ type TermsTaxonomyCollection struct {
 func (col *TermsTaxonomyCollection) List(params interface{}) ([]Term, *http.Response, []byte, error) {
 	var terms []Term
 	resp, body, err := col.client.List(col.url, params, &terms)
+
+	// set collection object for each entity which has sub-collection
+	for i := range terms {
+		terms[i].setCollection(col)
+	}
+
 	return terms, resp, body, err
 }
 func (col *TermsTaxonomyCollection) Create(new *Term) (*Term, *http.Response, []byte, error) {
 	var created Term
 	resp, body, err := col.client.Create(col.url, new, &created)
+	created.setCollection(col)
+	if err == nil {
+		err = saveUnregisteredMeta(created.Meta(), new.MetaValues, &created.MetaValues)
+	}
 	return &created, resp, body, err
 }
 func (col *TermsTaxonomyCollection) Get(id int, params interface{}) (*Term, *http.Response, []byte, error) {
 	var entity Term
 	entityURL := fmt.Sprintf("%v/%v", col.url, id)
 	resp, body, err := col.client.Get(entityURL, params, &entity)
+	entity.setCollection(col)
 	return &entity, resp, body, err
 }
+func (col *TermsTaxonomyCollection) Entity(id int) *Term {
+	entity := Term{
+		collection: col,
+		ID:         id,
+	}
+	return &entity
+}
 func (col *TermsTaxonomyCollection) Update(id int, post *Term) (*Term, *http.Response, []byte, error) {
 	var updated Term
 	entityURL := fmt.Sprintf("%v/%v", col.url, id)
 	resp, body, err := col.client.Update(entityURL, post, &updated)
+	updated.setCollection(col)
+	if err == nil {
+		err = saveUnregisteredMeta(updated.Meta(), post.MetaValues, &updated.MetaValues)
+	}
 	return &updated, resp, body, err
 }
 func (col *TermsTaxonomyCollection) Delete(id int, params interface{}) (*Term, *http.Response, []byte, error) {
 	var deleted Term
 	entityURL := fmt.Sprintf("%v/%v", col.url, id)
 	resp, body, err := col.client.Delete(entityURL, params, &deleted)
+	deleted.setCollection(col)
 	return &deleted, resp, body, err
 }
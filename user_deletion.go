@@ -0,0 +1,129 @@
+package wordpress
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// UserDeleteOptions are the options of UsersCollection.DeleteWithOptions
+type UserDeleteOptions struct {
+	// Reassign is the ID of the user the posts, pages and media of the deleted user are given to
+	Reassign int
+
+	// DiscardContent must be set to delete a user without reassigning its content, which deletes the content too
+	DiscardContent bool
+
+	// DryRun, if set, only lists the content that would be reassigned or deleted
+	DryRun bool
+}
+
+// UserDeletion lists the content of a deleted user, which was reassigned (or deleted if Reassign is 0)
+type UserDeletion struct {
+	User     User
+	Reassign int
+	DryRun   bool
+
+	Posts []Post
+	Pages []Page
+	Media []Media
+}
+
+// Count returns the number of posts, pages and media of the user
+func (deletion *UserDeletion) Count() int {
+	return len(deletion.Posts) + len(deletion.Pages) + len(deletion.Media)
+}
+
+// String summarizes the deletion, for eg. `user 3 (writer): 2 posts, 1 pages, 0 media reassigned to user 1`
+func (deletion *UserDeletion) String() string {
+	outcome := "deleted"
+	if deletion.Reassign != 0 {
+		outcome = fmt.Sprintf("reassigned to user %v", deletion.Reassign)
+	}
+	if deletion.DryRun {
+		outcome = "would be " + outcome
+	}
+	lines := []string{fmt.Sprintf("user %v (%v): %v posts, %v pages, %v media %v", deletion.User.ID, deletion.User.Username,
+		len(deletion.Posts), len(deletion.Pages), len(deletion.Media), outcome)}
+	for _, post := range deletion.Posts {
+		lines = append(lines, fmt.Sprintf("post #%v %v", post.ID, post.Title.Raw))
+	}
+	for _, page := range deletion.Pages {
+		lines = append(lines, fmt.Sprintf("page #%v %v", page.ID, page.Title.Raw))
+	}
+	for _, media := range deletion.Media {
+		lines = append(lines, fmt.Sprintf("media #%v %v", media.ID, media.Title.Raw))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// DeleteWithOptions deletes a user (users cannot be trashed), giving its content to the user set by
+// options.Reassign. It refuses to delete a user without reassigning its content, unless options.DiscardContent
+// is set. The content is listed in every status, which requires a user who can edit others' posts and pages.
+func (col *UsersCollection) DeleteWithOptions(id int, options *UserDeleteOptions) (*UserDeletion, error) {
+	if options == nil {
+		options = &UserDeleteOptions{}
+	}
+	switch {
+	case options.Reassign == 0 && !options.DiscardContent:
+		return nil, fmt.Errorf("refusing to delete user %v without reassigning its content, unless DiscardContent is set", id)
+	case options.Reassign != 0 && options.DiscardContent:
+		return nil, fmt.Errorf("cannot both reassign and discard the content of user %v", id)
+	case options.Reassign == id:
+		return nil, fmt.Errorf("cannot reassign the content of user %v to itself", id)
+	}
+
+	user, _, _, err := col.Get(id, "context=edit")
+	if err != nil {
+		return nil, err
+	}
+	if options.Reassign != 0 {
+		if _, _, _, err := col.Get(options.Reassign, nil); err != nil {
+			return nil, fmt.Errorf("user %v to reassign content to: %v", options.Reassign, err)
+		}
+	}
+	deletion := &UserDeletion{User: *user, Reassign: options.Reassign, DryRun: options.DryRun}
+	if err := col.listAuthored(deletion); err != nil {
+		return nil, err
+	}
+	if options.DryRun {
+		return deletion, nil
+	}
+
+	reassign := "false"
+	if options.Reassign != 0 {
+		reassign = fmt.Sprint(options.Reassign)
+	}
+	// deletion.User stays the user fetched above: older servers answer with the user, newer ones with `previous`
+	if _, _, _, err := col.Delete(id, fmt.Sprintf("force=true&reassign=%v", reassign)); err != nil {
+		return nil, err
+	}
+	return deletion, nil
+}
+
+// listAuthored lists the posts, pages and media of the user, in any status including trash
+func (col *UsersCollection) listAuthored(deletion *UserDeletion) error {
+	client := col.client
+	author := deletion.User.ID
+	err := forEachPage(fmt.Sprintf("context=edit&author=%v&status=any,trash", author), func(params string) (*http.Response, int, error) {
+		posts, resp, _, err := client.Posts().List(params)
+		deletion.Posts = append(deletion.Posts, posts...)
+		return resp, len(posts), err
+	})
+	if err != nil {
+		return err
+	}
+	err = forEachPage(fmt.Sprintf("context=edit&author=%v&status=any,trash", author), func(params string) (*http.Response, int, error) {
+		pages, resp, _, err := client.Pages().List(params)
+		deletion.Pages = append(deletion.Pages, pages...)
+		return resp, len(pages), err
+	})
+	if err != nil {
+		return err
+	}
+	return forEachPage(fmt.Sprintf("context=edit&author=%v&status=inherit,private,trash", author), func(params string) (*http.Response, int, error) {
+		media, resp, _, err := client.Media().List(params)
+		deletion.Media = append(deletion.Media, media...)
+		return resp, len(media), err
+	})
+}
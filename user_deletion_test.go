@@ -0,0 +1,78 @@
+package wordpress_test
+
+import (
+	"fmt"
+	"github.com/sogko/go-wordpress"
+	"github.com/sogko/go-wordpress/wptest"
+	"strings"
+	"testing"
+)
+
+func TestUsersDeleteWithOptions(t *testing.T) {
+	store := newFixtureStore(t)
+	writer := store.AddUser(wptest.Object{"username": "writer", "email": "writer@example.com", "roles": []string{wptest.RoleAuthor}}).Int("id")
+	heir := store.AddUser(wptest.Object{"username": "heir", "email": "heir@example.com", "roles": []string{wptest.RoleEditor}}).Int("id")
+	leaving := store.AddUser(wptest.Object{"username": "leaving", "email": "leaving@example.com", "roles": []string{wptest.RoleAuthor}}).Int("id")
+	published := store.AddPost(wptest.Object{"type": wptest.PostTypePost, "title": "Published", "status": "publish", "author": writer}).Int("id")
+	store.AddPost(wptest.Object{"type": wptest.PostTypePost, "title": "Draft", "status": "draft", "author": writer})
+	store.AddPost(wptest.Object{"type": wptest.PostTypePost, "title": "Trashed", "status": "trash", "author": writer})
+	store.AddPost(wptest.Object{"type": wptest.PostTypePage, "title": "About", "status": "publish", "author": writer})
+	discarded := store.AddPost(wptest.Object{"type": wptest.PostTypePost, "title": "Goodbye", "status": "publish", "author": leaving}).Int("id")
+	server := wptest.NewServer(store)
+	defer server.Close()
+	wp := newFakeTestClient(server, nil)
+
+	for _, options := range []*wordpress.UserDeleteOptions{
+		nil,
+		{DryRun: true},
+		{Reassign: heir, DiscardContent: true},
+		{Reassign: writer},
+		{Reassign: 999},
+	} {
+		if _, err := wp.Users().DeleteWithOptions(writer, options); err == nil {
+			t.Errorf("Expected an error deleting with %v", options)
+		}
+	}
+
+	deletion, err := wp.Users().DeleteWithOptions(writer, &wordpress.UserDeleteOptions{Reassign: heir, DryRun: true})
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if len(deletion.Posts) != 3 || len(deletion.Pages) != 1 || len(deletion.Media) != 0 || deletion.Count() != 4 {
+		t.Errorf("Expected 3 posts and a page to be listed, got %v", deletion)
+	}
+	if !strings.HasPrefix(deletion.String(), "user ") || !strings.Contains(deletion.String(), "would be reassigned to user") {
+		t.Errorf("Unexpected summary: %v", deletion)
+	}
+	if _, ok := store.User(writer); !ok {
+		t.Fatalf("Expected a dry run to keep the user")
+	}
+
+	deletion, err = wp.Users().DeleteWithOptions(writer, &wordpress.UserDeleteOptions{Reassign: heir})
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if deletion.User.ID != writer || deletion.Count() != 4 || deletion.DryRun {
+		t.Errorf("Unexpected deletion: %v", deletion)
+	}
+	if summary := strings.SplitN(deletion.String(), "\n", 2)[0]; summary != fmt.Sprintf("user %v (writer): 3 posts, 1 pages, 0 media reassigned to user %v", writer, heir) {
+		t.Errorf("Unexpected summary: %v", summary)
+	}
+	if _, ok := store.User(writer); ok {
+		t.Errorf("Expected the user to be deleted")
+	}
+	if post, ok := store.Post(published); !ok || post.Int("author") != heir {
+		t.Errorf("Expected the post to be reassigned, got %v", post)
+	}
+
+	deletion, err = wp.Users().DeleteWithOptions(leaving, &wordpress.UserDeleteOptions{DiscardContent: true})
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if len(deletion.Posts) != 1 || !strings.Contains(deletion.String(), "media deleted") {
+		t.Errorf("Expected a post to be deleted, got %v", deletion)
+	}
+	if _, ok := store.Post(discarded); ok {
+		t.Errorf("Expected the content to be deleted")
+	}
+}
@@ -11,6 +11,8 @@ type AvatarURLS struct {
 	Size96 string `json:"96,omitempty"`
 }
 type User struct {
+	collection *UsersCollection `json:"-"`
+
 	ID                int                    `json:"id,omitempty"`
 	AvatarURL         string                 `json:"avatar_url,omitempty"`
 	AvatarURLs        AvatarURLS             `json:"avatar_urls,omitempty"`
@@ -24,11 +26,44 @@ type User struct {
 	Name              string                 `json:"name,omitempty"`
 	Nickname          string                 `json:"nickname,omitempty"`
 	RegisteredDate    string                 `json:"registered_date,omitempty"`
-	Roles             []string               `json:"roles,omitempty"`
+	Roles             []Role                 `json:"roles,omitempty"`
 	Slug              string                 `json:"slug,omitempty"`
 	URL               string                 `json:"url,omitempty"`
 	Username          string                 `json:"username,omitempty"`
 	Password          string                 `json:"password,omitempty"`
+
+	// MetaValues are the registered meta keys; see MetaValues
+	MetaValues MetaValues `json:"meta,omitempty"`
+}
+
+func (entity *User) setCollection(col *UsersCollection) {
+	entity.collection = col
+}
+func (entity *User) Meta() *MetaCollection {
+	if entity.collection == nil {
+		// missing user.collection parent. Probably User struct was initialized manually, not fetched from API
+		_warning("Missing parent user collection")
+		return nil
+	}
+	return &MetaCollection{
+		client:     entity.collection.client,
+		parent:     entity,
+		parentType: CollectionUsers,
+		url:        fmt.Sprintf("%v/%v/%v", entity.collection.url, entity.ID, CollectionMeta),
+	}
+}
+func (entity *User) ApplicationPasswords() *ApplicationPasswordsCollection {
+	if entity.collection == nil {
+		// missing user.collection parent. Probably User struct was initialized manually, not fetched from API
+		_warning("Missing parent user collection")
+		return nil
+	}
+	return &ApplicationPasswordsCollection{
+		client:     entity.collection.client,
+		parent:     entity,
+		parentType: CollectionUsers,
+		url:        fmt.Sprintf("%v/%v/%v", entity.collection.url, entity.ID, CollectionApplicationPasswords),
+	}
 }
 
 type UsersCollection struct {
@@ -40,33 +75,65 @@ func (col *UsersCollection) Me(params interface{}) (*User, *http.Response, []byt
 	url := fmt.Sprintf("%v/me", col.url)
 	var user User
 	resp, body, err := col.client.Get(url, params, &user)
+	user.setCollection(col)
 	return &user, resp, body, err
 }
 func (col *UsersCollection) List(params interface{}) ([]User, *http.Response, []byte, error) {
 	var users []User
 	resp, body, err := col.client.List(col.url, params, &users)
+
+	// set collection object for each entity which has sub-collection
+	for i := range users {
+		users[i].setCollection(col)
+	}
+
 	return users, resp, body, err
 }
 func (col *UsersCollection) Create(new *User) (*User, *http.Response, []byte, error) {
 	var created User
 	resp, body, err := col.client.Create(col.url, new, &created)
+	created.setCollection(col)
+	if err == nil {
+		err = saveUnregisteredMeta(created.Meta(), new.MetaValues, &created.MetaValues)
+	}
 	return &created, resp, body, err
 }
 func (col *UsersCollection) Get(id int, params interface{}) (*User, *http.Response, []byte, error) {
 	var entity User
 	entityURL := fmt.Sprintf("%v/%v", col.url, id)
 	resp, body, err := col.client.Get(entityURL, params, &entity)
+	entity.setCollection(col)
 	return &entity, resp, body, err
 }
+func (col *UsersCollection) Entity(id int) *User {
+	entity := User{
+		collection: col,
+		ID:         id,
+	}
+	return &entity
+}
 func (col *UsersCollection) Update(id int, post *User) (*User, *http.Response, []byte, error) {
 	var updated User
 	entityURL := fmt.Sprintf("%v/%v", col.url, id)
 	resp, body, err := col.client.Update(entityURL, post, &updated)
+	updated.setCollection(col)
+	if err == nil {
+		err = saveUnregisteredMeta(updated.Meta(), post.MetaValues, &updated.MetaValues)
+	}
 	return &updated, resp, body, err
 }
 func (col *UsersCollection) Delete(id int, params interface{}) (*User, *http.Response, []byte, error) {
-	var deleted User
+	// WordPress 4.7+ answers `{"deleted": true, "previous": <user>}` instead of the user itself
+	var response struct {
+		User
+		Previous *User `json:"previous,omitempty"`
+	}
 	entityURL := fmt.Sprintf("%v/%v", col.url, id)
-	resp, body, err := col.client.Delete(entityURL, params, &deleted)
+	resp, body, err := col.client.Delete(entityURL, params, &response)
+	deleted := response.User
+	if response.Previous != nil {
+		deleted = *response.Previous
+	}
+	deleted.setCollection(col)
 	return &deleted, resp, body, err
 }
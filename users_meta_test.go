@@ -0,0 +1,53 @@
+package wordpress_test
+
+import (
+	"github.com/sogko/go-wordpress"
+	"net/http"
+	"testing"
+)
+
+func TestUsersMeta_InvalidCall(t *testing.T) {
+	invalidUser := wordpress.User{}
+	invalidMeta := invalidUser.Meta()
+	if invalidMeta != nil {
+		t.Errorf("Expected meta to be nil, %v", invalidMeta)
+	}
+}
+
+func TestUsersMeta(t *testing.T) {
+	wp := initTestClient()
+	user := getAnyOneUser(t, wp)
+
+	newMeta, resp, _, err := user.Meta().Create(&wordpress.Meta{Key: "testKey", Value: "testValue"})
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("Expected 201 Created, got %v", resp.Status)
+	}
+
+	meta, _, _, err := user.Meta().List(nil)
+	if err != nil {
+		t.Errorf("Should not return error: %v", err.Error())
+	}
+	if len(meta) != 1 || meta[0].ID != newMeta.ID || meta[0].Value != "testValue" {
+		t.Errorf("Expected the new meta to be listed, got %v", meta)
+	}
+
+	updatedMeta, _, _, err := wp.Users().Entity(user.ID).Meta().Update(newMeta.ID, &wordpress.Meta{Value: "newTestValue"})
+	if err != nil {
+		t.Errorf("Failed to update user meta: %v", err.Error())
+	}
+	if updatedMeta.Key != newMeta.Key || updatedMeta.Value != "newTestValue" {
+		t.Errorf("Expected the value to be updated, got %v", updatedMeta)
+	}
+
+	// note: Need to pass in `force=true` param in order to delete user meta
+	deletedMeta, resp, _, err := user.Meta().Delete(newMeta.ID, "force=true")
+	if err != nil {
+		t.Errorf("Failed to clean up new user meta: %v", err.Error())
+	}
+	if resp.StatusCode != http.StatusOK || deletedMeta.Message != "Deleted meta" {
+		t.Errorf("Unexpected response to deleted meta: %v", deletedMeta.Message)
+	}
+}
@@ -9,6 +9,9 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 )
 
 var DEBUG bool = (os.Getenv("DEBUG") == "1")
@@ -60,3 +63,68 @@ func UnmarshallServerError(body []byte) ([]GeneralError, error) {
 	}
 	return resp, nil
 }
+
+// maxPerPage is the largest page WP-API returns
+const maxPerPage = 100
+
+// forEachPage calls list for each page of a collection, with params extended with `page` and `per_page`,
+// until the last page (as given by the X-WP-TotalPages header, or the first page that is not full).
+// list returns the number of items of the page.
+func forEachPage(params string, list func(params string) (*http.Response, int, error)) error {
+	if params != "" {
+		params += "&"
+	}
+	for page := 1; ; page++ {
+		resp, n, err := list(fmt.Sprintf("%vpage=%v&per_page=%v", params, page, maxPerPage))
+		if err != nil {
+			return err
+		}
+		if totalPages, err := strconv.Atoi(resp.Header.Get("X-WP-TotalPages")); err == nil {
+			if page >= totalPages {
+				return nil
+			}
+		} else if n < maxPerPage {
+			return nil
+		}
+	}
+}
+
+// contentDisposition formats a Content-Disposition header value, for eg. `attachment; filename="a.jpg"`.
+// Non-ASCII filenames are sent as an ASCII fallback, plus their RFC 5987 encoding in the filename* parameter.
+func contentDisposition(dispositionType string, name string, filename string) string {
+	value := dispositionType
+	if name != "" {
+		value += fmt.Sprintf("; name=%v", quoteParam(name))
+	}
+	ascii := true
+	fallback := strings.Map(func(r rune) rune {
+		if r >= utf8.RuneSelf || r < ' ' {
+			ascii = false
+			return '_'
+		}
+		return r
+	}, filename)
+	value += fmt.Sprintf("; filename=%v", quoteParam(fallback))
+	if !ascii {
+		value += "; filename*=UTF-8''" + encodeExtValue(filename)
+	}
+	return value
+}
+
+func quoteParam(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// encodeExtValue percent-encodes every byte but the RFC 5987 attr-chars
+func encodeExtValue(s string) string {
+	var encoded []byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("!#$&+-.^_`|~", c) >= 0 {
+			encoded = append(encoded, c)
+			continue
+		}
+		encoded = append(encoded, fmt.Sprintf("%%%02X", c)...)
+	}
+	return string(encoded)
+}
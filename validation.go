@@ -0,0 +1,297 @@
+package wordpress
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FieldError describes a single payload field that does not satisfy the route's schema
+type FieldError struct {
+	Field  string      `json:"field"`
+	Value  interface{} `json:"value,omitempty"`
+	Reason string      `json:"reason"`
+}
+
+func (e FieldError) String() string {
+	return fmt.Sprintf("%v: %v", e.Field, e.Reason)
+}
+
+// ValidationError is returned by Create() and Update() when Options.ValidateSchema is enabled
+// and the payload does not satisfy the route's schema. No request is sent in that case.
+type ValidationError struct {
+	URL    string       `json:"url"`
+	Fields []FieldError `json:"fields"`
+}
+
+func (e *ValidationError) Error() string {
+	var fields []string
+	for _, f := range e.Fields {
+		fields = append(fields, f.String())
+	}
+	return fmt.Sprintf("invalid payload for %v: %v", e.URL, strings.Join(fields, "; "))
+}
+
+// Validate checks the payload for the given HTTP method against the route's schema and endpoint arguments.
+// Required fields are only enforced for `POST` (create) requests. Read-only fields and fields unknown to
+// the schema are ignored, since WP-API ignores them as well.
+// Returns a *ValidationError listing every offending field, or nil.
+func (route *Route) Validate(method string, content interface{}) error {
+	payload, err := payloadMap(content)
+	if err != nil {
+		return err
+	}
+
+	properties := map[string]SchemaProperty{}
+	required := map[string]bool{}
+	for _, endpoint := range route.Endpoints {
+		if !hasMethod(endpoint.Methods, method) {
+			continue
+		}
+		for name, arg := range endpoint.Args {
+			properties[name] = arg
+			if arg.Required {
+				required[name] = true
+			}
+		}
+	}
+	if route.Schema != nil {
+		for name, prop := range route.Schema.Properties {
+			// the schema is more detailed than the endpoint args, but keep required flags from both
+			properties[name] = prop
+			if prop.Required {
+				required[name] = true
+			}
+		}
+	}
+
+	var errs []FieldError
+	if method == "POST" {
+		for name := range required {
+			if isEmptyValue(payload[name]) {
+				errs = append(errs, FieldError{Field: name, Reason: "is required"})
+			}
+		}
+	}
+	for name, value := range payload {
+		prop, ok := properties[name]
+		if !ok || prop.ReadOnly {
+			continue
+		}
+		errs = append(errs, validateValue(name, value, prop)...)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Field < errs[j].Field
+	})
+	return &ValidationError{Fields: errs}
+}
+
+// payloadMap returns the payload the way it will be sent over the wire
+func payloadMap(content interface{}) (map[string]interface{}, error) {
+	payload := map[string]interface{}{}
+	if content == nil {
+		return payload, nil
+	}
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		// not a JSON object (for eg. a raw string payload); nothing to validate
+		return map[string]interface{}{}, nil
+	}
+	return payload, nil
+}
+
+func validateValue(field string, value interface{}, prop SchemaProperty) []FieldError {
+	if len(prop.Type) > 0 && !prop.Type.Has(jsonType(value)) {
+		// integers are valid numbers
+		if !(jsonType(value) == "integer" && prop.Type.Has("number")) {
+			return []FieldError{{
+				Field:  field,
+				Value:  value,
+				Reason: fmt.Sprintf("must be of type %v", strings.Join(prop.Type, " or ")),
+			}}
+		}
+	}
+
+	var errs []FieldError
+	if len(prop.Enum) > 0 && !inEnum(value, prop.Enum) {
+		errs = append(errs, FieldError{
+			Field:  field,
+			Value:  value,
+			Reason: fmt.Sprintf("must be one of %v", prop.Enum),
+		})
+	}
+	if s, ok := value.(string); ok && prop.Format != "" && !validFormat(prop.Format, s) {
+		errs = append(errs, FieldError{
+			Field:  field,
+			Value:  value,
+			Reason: fmt.Sprintf("must be a valid %v", prop.Format),
+		})
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, nested := range v {
+			nestedProp, ok := prop.Properties[name]
+			if !ok || nestedProp.ReadOnly {
+				continue
+			}
+			errs = append(errs, validateValue(field+"."+name, nested, nestedProp)...)
+		}
+	case []interface{}:
+		if prop.Items != nil {
+			for i, item := range v {
+				errs = append(errs, validateValue(fmt.Sprintf("%v[%v]", field, i), item, *prop.Items)...)
+			}
+		}
+	}
+	return errs
+}
+
+// isEmptyValue reports whether a payload value is absent.
+// Struct fields such as `Title` are never dropped by `omitempty`, so an empty object counts as absent too.
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case map[string]interface{}:
+		for _, nested := range v {
+			if !isEmptyValue(nested) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return ""
+}
+
+func inEnum(value interface{}, enum []interface{}) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func validFormat(format string, value string) bool {
+	switch format {
+	case "email":
+		_, err := mail.ParseAddress(value)
+		return err == nil
+	case "uri":
+		u, err := url.Parse(value)
+		return err == nil && u.Scheme != "" && u.Host != ""
+	case "ip":
+		return net.ParseIP(value) != nil
+	case "date-time":
+		// WP-API sends and accepts dates without a timezone offset
+		for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05"} {
+			if _, err := time.Parse(layout, value); err == nil {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func hasMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaFailureTTL is how long a route that could not be described is sent without validation before it is
+// described again
+const schemaFailureTTL = 5 * time.Minute
+
+// schemaCache holds route descriptions fetched for validation, keyed by collection URL,
+// and when describing a route failed
+type schemaCache struct {
+	mu       sync.Mutex
+	routes   map[string]*Route
+	failures map[string]time.Time
+}
+
+var trailingIDRegexp = regexp.MustCompile(`/\d+$`)
+
+// validate checks a Create() or Update() payload against the (cached) schema of its collection route.
+// If the route cannot be described by the server, the payload is sent without validation, and the route is not
+// described again before schemaFailureTTL.
+func (client *Client) validate(method string, url string, content interface{}) error {
+	if content == nil {
+		return nil
+	}
+	collectionURL := trailingIDRegexp.ReplaceAllString(url, "")
+
+	client.schemas.mu.Lock()
+	route, ok := client.schemas.routes[collectionURL]
+	failed, recentlyFailed := client.schemas.failures[collectionURL]
+	recentlyFailed = recentlyFailed && time.Since(failed) < schemaFailureTTL
+	client.schemas.mu.Unlock()
+	if recentlyFailed {
+		return nil
+	}
+	if !ok {
+		var err error
+		route, _, _, err = client.Route(collectionURL)
+		client.schemas.mu.Lock()
+		if err != nil {
+			client.schemas.failures[collectionURL] = time.Now()
+		} else {
+			client.schemas.routes[collectionURL] = route
+			delete(client.schemas.failures, collectionURL)
+		}
+		client.schemas.mu.Unlock()
+		if err != nil {
+			_warning("Unable to fetch schema for validation", collectionURL, err)
+			return nil
+		}
+	}
+
+	err := route.Validate(method, content)
+	if verr, ok := err.(*ValidationError); ok {
+		verr.URL = url
+	}
+	return err
+}
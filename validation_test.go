@@ -0,0 +1,204 @@
+package wordpress_test
+
+import (
+	"encoding/json"
+	"github.com/sogko/go-wordpress"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testPostsRoute = `{
+  "namespace": "wp/v2",
+  "methods": ["GET", "POST"],
+  "endpoints": [
+    {"methods": ["GET"], "args": {"page": {"type": "integer"}}},
+    {"methods": ["POST"], "args": {"title": {"required": true}}}
+  ],
+  "schema": {
+    "title": "post",
+    "type": "object",
+    "properties": {
+      "id": {"type": "integer", "readonly": true},
+      "link": {"type": "string", "format": "uri", "readonly": true},
+      "date": {"type": ["string", "null"], "format": "date-time"},
+      "title": {
+        "type": "object",
+        "properties": {
+          "raw": {"type": "string"},
+          "rendered": {"type": "string", "readonly": true}
+        }
+      },
+      "status": {"type": "string", "enum": ["publish", "future", "draft", "pending", "private"]},
+      "format": {"type": "string", "enum": ["standard", "aside", "gallery", "image", "link", "status", "quote", "video", "chat"]},
+      "comment_status": {"type": "string", "enum": ["open", "closed"]},
+      "author": {"type": "integer"},
+      "sticky": {"type": "boolean"},
+      "author_email": {"type": "string", "format": "email"},
+      "categories": {"type": "array", "items": {"type": "integer"}}
+    }
+  }
+}`
+
+func testRoute(t *testing.T) *wordpress.Route {
+	var route wordpress.Route
+	if err := json.Unmarshal([]byte(testPostsRoute), &route); err != nil {
+		t.Fatalf("Failed to parse test route: %v", err.Error())
+	}
+	return &route
+}
+
+func TestRouteValidate_Valid(t *testing.T) {
+	route := testRoute(t)
+
+	post := factoryPost()
+	post.Link = "not a uri, but read-only"
+	if err := route.Validate("POST", &post); err != nil {
+		t.Errorf("Should not return error: %v", err.Error())
+	}
+}
+
+func TestRouteValidate_InvalidFields(t *testing.T) {
+	route := testRoute(t)
+
+	post := factoryPost()
+	post.Status = "published"
+	post.Format = "picture"
+	post.CommentStatus = wordpress.CommentStatusApproved
+	post.Date = "yesterday"
+
+	err := route.Validate("POST", &post)
+	if err == nil {
+		t.Fatalf("Should return error")
+	}
+	verr, ok := err.(*wordpress.ValidationError)
+	if !ok {
+		t.Fatalf("Should return a *ValidationError, got %T", err)
+	}
+	expected := []string{"comment_status", "date", "format", "status"}
+	if len(verr.Fields) != len(expected) {
+		t.Fatalf("Expected %v offending fields, got %v", len(expected), verr.Fields)
+	}
+	for i, field := range expected {
+		if verr.Fields[i].Field != field {
+			t.Errorf("Expected offending field %v, got %v", field, verr.Fields[i].Field)
+		}
+	}
+}
+
+func TestRouteValidate_TypesAndFormats(t *testing.T) {
+	route := testRoute(t)
+
+	payload := map[string]interface{}{
+		"title":        map[string]interface{}{"raw": 42},
+		"author":       "admin",
+		"author_email": "not-an-email",
+		"categories":   []interface{}{1, "two"},
+	}
+	err := route.Validate("PUT", payload)
+	if err == nil {
+		t.Fatalf("Should return error")
+	}
+	verr := err.(*wordpress.ValidationError)
+	expected := []string{"author", "author_email", "categories[1]", "title.raw"}
+	if len(verr.Fields) != len(expected) {
+		t.Fatalf("Expected %v offending fields, got %v", len(expected), verr.Fields)
+	}
+	for i, field := range expected {
+		if verr.Fields[i].Field != field {
+			t.Errorf("Expected offending field %v, got %v", field, verr.Fields[i].Field)
+		}
+	}
+}
+
+func TestRouteValidate_Required(t *testing.T) {
+	route := testRoute(t)
+
+	post := wordpress.Post{Status: wordpress.PostStatusDraft}
+	err := route.Validate("POST", &post)
+	if err == nil {
+		t.Fatalf("Should return error for missing required field on create")
+	}
+	verr := err.(*wordpress.ValidationError)
+	if len(verr.Fields) != 1 || verr.Fields[0].Field != "title" {
+		t.Errorf("Expected missing `title`, got %v", verr.Fields)
+	}
+
+	// required fields are not enforced on update
+	if err := route.Validate("PUT", &post); err != nil {
+		t.Errorf("Should not return error: %v", err.Error())
+	}
+}
+
+func TestClientCreate_ValidateSchema(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "OPTIONS" {
+			w.Write([]byte(testPostsRoute))
+			return
+		}
+		requests++
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	wp := wordpress.NewClient(&wordpress.Options{
+		BaseAPIURL:     server.URL,
+		ValidateSchema: true,
+	})
+
+	post := factoryPost()
+	post.Status = "published"
+	_, resp, _, err := wp.Posts().Create(&post)
+	if _, ok := err.(*wordpress.ValidationError); !ok {
+		t.Errorf("Should return a *ValidationError, got %v", err)
+	}
+	if resp != nil || requests != 0 {
+		t.Errorf("Invalid payload should not be sent")
+	}
+
+	post = factoryPost()
+	newPost, resp, _, err := wp.Posts().Create(&post)
+	if err != nil {
+		t.Errorf("Should not return error: %v", err.Error())
+	}
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("Expected 201 Created, got %v", resp.Status)
+	}
+	if newPost.ID != 1 {
+		t.Errorf("Expected created post, got %v", newPost)
+	}
+}
+
+func TestClientCreate_ValidateSchemaUnavailable(t *testing.T) {
+	describes, requests := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "OPTIONS" {
+			describes++
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		requests++
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	wp := wordpress.NewClient(&wordpress.Options{
+		BaseAPIURL:     server.URL,
+		ValidateSchema: true,
+	})
+
+	// payloads are sent without validation, and the failed description is not repeated
+	for i := 0; i < 3; i++ {
+		post := factoryPost()
+		post.Status = "published"
+		if _, _, _, err := wp.Posts().Create(&post); err != nil {
+			t.Errorf("Should not return error: %v", err.Error())
+		}
+	}
+	if describes != 1 || requests != 3 {
+		t.Errorf("Expected the route to be described once and 3 posts created, got %v and %v", describes, requests)
+	}
+}
@@ -0,0 +1,64 @@
+// Package wpmock provides mock implementations of the services of package wordpress.
+//
+// Each mock records its calls and returns the results scripted through its `<Method>Func` fields,
+// or zero values when a method is not scripted. Accessors of sub-services (e.g. API.Posts(),
+// TermsService.Tag()) return the mock stored in their `<Method>Mock` field, created on first use.
+//
+//	api := &wpmock.API{}
+//	api.Posts().(*wpmock.PostsService).GetFunc = func(id int, params interface{}) (*wordpress.Post, *http.Response, []byte, error) {
+//		return &wordpress.Post{ID: id}, nil, nil, nil
+//	}
+//	publish(api) // code under test, depending on wordpress.API
+//	if len(api.PostsMock.CallsTo("Update")) != 1 { ... }
+//
+// The mocks in mocks.go are generated from the interfaces of package wordpress by cmd/wpmock.
+package wpmock
+
+import (
+	"sync"
+)
+
+// Call is a recorded method call
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Mock records the calls made to a mock
+type Mock struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Calls returns every call made to the mock, in order
+func (m *Mock) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// CallsTo returns the calls made to the given method, in order
+func (m *Mock) CallsTo(method string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var calls []Call
+	for _, call := range m.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset forgets the recorded calls
+func (m *Mock) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = nil
+}
+
+func (m *Mock) record(method string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+}
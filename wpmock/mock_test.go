@@ -0,0 +1,77 @@
+package wpmock_test
+
+import (
+	"errors"
+	"github.com/sogko/go-wordpress"
+	"github.com/sogko/go-wordpress/wpmock"
+	"net/http"
+	"testing"
+)
+
+// publish is an example of code depending on wordpress.API
+func publish(api wordpress.API, id int) error {
+	post, _, _, err := api.Posts().Get(id, "context=edit")
+	if err != nil {
+		return err
+	}
+	post.Status = wordpress.PostStatusPublish
+	_, _, _, err = api.Posts().Update(id, post)
+	return err
+}
+
+func TestAPI(t *testing.T) {
+	api := &wpmock.API{}
+	api.Posts().(*wpmock.PostsService).GetFunc = func(id int, params interface{}) (*wordpress.Post, *http.Response, []byte, error) {
+		return &wordpress.Post{ID: id, Status: wordpress.PostStatusDraft}, nil, nil, nil
+	}
+
+	if err := publish(api, 42); err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+
+	posts := api.PostsMock
+	if calls := posts.Calls(); len(calls) != 2 || calls[0].Method != "Get" || calls[1].Method != "Update" {
+		t.Fatalf("Expected Get then Update, got %v", calls)
+	}
+	update := posts.CallsTo("Update")[0]
+	if update.Args[0] != 42 || update.Args[1].(*wordpress.Post).Status != wordpress.PostStatusPublish {
+		t.Errorf("Expected post 42 to be published, got %v", update.Args)
+	}
+	if len(api.CallsTo("Posts")) != 3 {
+		t.Errorf("Expected 3 calls to Posts(), got %v", len(api.CallsTo("Posts")))
+	}
+
+	posts.Reset()
+	if len(posts.Calls()) != 0 {
+		t.Errorf("Expected no calls after Reset()")
+	}
+}
+
+func TestAPI_Errors(t *testing.T) {
+	api := &wpmock.API{}
+	api.Posts().(*wpmock.PostsService).GetFunc = func(id int, params interface{}) (*wordpress.Post, *http.Response, []byte, error) {
+		return nil, nil, nil, errors.New("not found")
+	}
+	if err := publish(api, 1); err == nil || err.Error() != "not found" {
+		t.Errorf("Expected the scripted error, got %v", err)
+	}
+	if len(api.PostsMock.CallsTo("Update")) != 0 {
+		t.Errorf("Should not update a post that could not be fetched")
+	}
+}
+
+func TestTermsService(t *testing.T) {
+	terms := &wpmock.TermsService{}
+
+	// unscripted methods return zero values
+	list, resp, body, err := terms.Tag().List(nil)
+	if list != nil || resp != nil || body != nil || err != nil {
+		t.Errorf("Expected zero values, got %v %v %v %v", list, resp, body, err)
+	}
+	if terms.Tag() != terms.TagMock || terms.Category() == terms.Tag() {
+		t.Errorf("Expected each accessor to return its own mock")
+	}
+	if len(terms.TagMock.CallsTo("List")) != 1 {
+		t.Errorf("Expected a call to Tag().List()")
+	}
+}
@@ -0,0 +1,1132 @@
+// Code generated by wpmock. DO NOT EDIT.
+
+package wpmock
+
+import (
+	"github.com/sogko/go-wordpress"
+	"io"
+	"net/http"
+)
+
+// API is a mock of wordpress.API
+type API struct {
+	Mock
+
+	UsersFunc      func() wordpress.UsersService
+	UsersMock      *UsersService
+	PostsFunc      func() wordpress.PostsService
+	PostsMock      *PostsService
+	PagesFunc      func() wordpress.PagesService
+	PagesMock      *PagesService
+	MediaFunc      func() wordpress.MediaService
+	MediaMock      *MediaService
+	CommentsFunc   func() wordpress.CommentsService
+	CommentsMock   *CommentsService
+	TaxonomiesFunc func() wordpress.TaxonomiesService
+	TaxonomiesMock *TaxonomiesService
+	TermsFunc      func() wordpress.TermsService
+	TermsMock      *TermsService
+	StatusesFunc   func() wordpress.StatusesService
+	StatusesMock   *StatusesService
+	TypesFunc      func() wordpress.TypesService
+	TypesMock      *TypesService
+	IndexFunc      func(params interface{}) (*wordpress.Index, *http.Response, []byte, error)
+	RouteFunc      func(url string) (*wordpress.Route, *http.Response, []byte, error)
+}
+
+var _ wordpress.API = (*API)(nil)
+
+func (m *API) Users() (r0 wordpress.UsersService) {
+	m.record("Users")
+	if m.UsersFunc != nil {
+		return m.UsersFunc()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.UsersMock == nil {
+		m.UsersMock = &UsersService{}
+	}
+	return m.UsersMock
+}
+
+func (m *API) Posts() (r0 wordpress.PostsService) {
+	m.record("Posts")
+	if m.PostsFunc != nil {
+		return m.PostsFunc()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.PostsMock == nil {
+		m.PostsMock = &PostsService{}
+	}
+	return m.PostsMock
+}
+
+func (m *API) Pages() (r0 wordpress.PagesService) {
+	m.record("Pages")
+	if m.PagesFunc != nil {
+		return m.PagesFunc()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.PagesMock == nil {
+		m.PagesMock = &PagesService{}
+	}
+	return m.PagesMock
+}
+
+func (m *API) Media() (r0 wordpress.MediaService) {
+	m.record("Media")
+	if m.MediaFunc != nil {
+		return m.MediaFunc()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.MediaMock == nil {
+		m.MediaMock = &MediaService{}
+	}
+	return m.MediaMock
+}
+
+func (m *API) Comments() (r0 wordpress.CommentsService) {
+	m.record("Comments")
+	if m.CommentsFunc != nil {
+		return m.CommentsFunc()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.CommentsMock == nil {
+		m.CommentsMock = &CommentsService{}
+	}
+	return m.CommentsMock
+}
+
+func (m *API) Taxonomies() (r0 wordpress.TaxonomiesService) {
+	m.record("Taxonomies")
+	if m.TaxonomiesFunc != nil {
+		return m.TaxonomiesFunc()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.TaxonomiesMock == nil {
+		m.TaxonomiesMock = &TaxonomiesService{}
+	}
+	return m.TaxonomiesMock
+}
+
+func (m *API) Terms() (r0 wordpress.TermsService) {
+	m.record("Terms")
+	if m.TermsFunc != nil {
+		return m.TermsFunc()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.TermsMock == nil {
+		m.TermsMock = &TermsService{}
+	}
+	return m.TermsMock
+}
+
+func (m *API) Statuses() (r0 wordpress.StatusesService) {
+	m.record("Statuses")
+	if m.StatusesFunc != nil {
+		return m.StatusesFunc()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.StatusesMock == nil {
+		m.StatusesMock = &StatusesService{}
+	}
+	return m.StatusesMock
+}
+
+func (m *API) Types() (r0 wordpress.TypesService) {
+	m.record("Types")
+	if m.TypesFunc != nil {
+		return m.TypesFunc()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.TypesMock == nil {
+		m.TypesMock = &TypesService{}
+	}
+	return m.TypesMock
+}
+
+func (m *API) Index(params interface{}) (r0 *wordpress.Index, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Index", params)
+	if m.IndexFunc != nil {
+		return m.IndexFunc(params)
+	}
+	return
+}
+
+func (m *API) Route(url string) (r0 *wordpress.Route, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Route", url)
+	if m.RouteFunc != nil {
+		return m.RouteFunc(url)
+	}
+	return
+}
+
+// UsersService is a mock of wordpress.UsersService
+type UsersService struct {
+	Mock
+
+	MeFunc                func(params interface{}) (*wordpress.User, *http.Response, []byte, error)
+	ListFunc              func(params interface{}) ([]wordpress.User, *http.Response, []byte, error)
+	CreateFunc            func(new *wordpress.User) (*wordpress.User, *http.Response, []byte, error)
+	GetFunc               func(id int, params interface{}) (*wordpress.User, *http.Response, []byte, error)
+	EntityFunc            func(id int) *wordpress.User
+	UpdateFunc            func(id int, user *wordpress.User) (*wordpress.User, *http.Response, []byte, error)
+	DeleteFunc            func(id int, params interface{}) (*wordpress.User, *http.Response, []byte, error)
+	DeleteWithOptionsFunc func(id int, options *wordpress.UserDeleteOptions) (*wordpress.UserDeletion, error)
+	SetRolesFunc          func(id int, roles ...wordpress.Role) (*wordpress.User, *http.Response, []byte, error)
+	AddRoleFunc           func(id int, role wordpress.Role) (*wordpress.User, *http.Response, []byte, error)
+	RemoveRoleFunc        func(id int, role wordpress.Role) (*wordpress.User, *http.Response, []byte, error)
+	CapabilityReportFunc  func(params string) (*wordpress.CapabilityReport, error)
+}
+
+var _ wordpress.UsersService = (*UsersService)(nil)
+
+func (m *UsersService) Me(params interface{}) (r0 *wordpress.User, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Me", params)
+	if m.MeFunc != nil {
+		return m.MeFunc(params)
+	}
+	return
+}
+
+func (m *UsersService) List(params interface{}) (r0 []wordpress.User, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("List", params)
+	if m.ListFunc != nil {
+		return m.ListFunc(params)
+	}
+	return
+}
+
+func (m *UsersService) Create(new *wordpress.User) (r0 *wordpress.User, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Create", new)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(new)
+	}
+	return
+}
+
+func (m *UsersService) Get(id int, params interface{}) (r0 *wordpress.User, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Get", id, params)
+	if m.GetFunc != nil {
+		return m.GetFunc(id, params)
+	}
+	return
+}
+
+func (m *UsersService) Entity(id int) (r0 *wordpress.User) {
+	m.record("Entity", id)
+	if m.EntityFunc != nil {
+		return m.EntityFunc(id)
+	}
+	return
+}
+
+func (m *UsersService) Update(id int, user *wordpress.User) (r0 *wordpress.User, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Update", id, user)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(id, user)
+	}
+	return
+}
+
+func (m *UsersService) Delete(id int, params interface{}) (r0 *wordpress.User, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Delete", id, params)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id, params)
+	}
+	return
+}
+
+func (m *UsersService) DeleteWithOptions(id int, options *wordpress.UserDeleteOptions) (r0 *wordpress.UserDeletion, r1 error) {
+	m.record("DeleteWithOptions", id, options)
+	if m.DeleteWithOptionsFunc != nil {
+		return m.DeleteWithOptionsFunc(id, options)
+	}
+	return
+}
+
+func (m *UsersService) SetRoles(id int, roles ...wordpress.Role) (r0 *wordpress.User, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("SetRoles", id, roles)
+	if m.SetRolesFunc != nil {
+		return m.SetRolesFunc(id, roles...)
+	}
+	return
+}
+
+func (m *UsersService) AddRole(id int, role wordpress.Role) (r0 *wordpress.User, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("AddRole", id, role)
+	if m.AddRoleFunc != nil {
+		return m.AddRoleFunc(id, role)
+	}
+	return
+}
+
+func (m *UsersService) RemoveRole(id int, role wordpress.Role) (r0 *wordpress.User, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("RemoveRole", id, role)
+	if m.RemoveRoleFunc != nil {
+		return m.RemoveRoleFunc(id, role)
+	}
+	return
+}
+
+func (m *UsersService) CapabilityReport(params string) (r0 *wordpress.CapabilityReport, r1 error) {
+	m.record("CapabilityReport", params)
+	if m.CapabilityReportFunc != nil {
+		return m.CapabilityReportFunc(params)
+	}
+	return
+}
+
+// PostsService is a mock of wordpress.PostsService
+type PostsService struct {
+	Mock
+
+	ListFunc   func(params interface{}) ([]wordpress.Post, *http.Response, []byte, error)
+	CreateFunc func(new *wordpress.Post) (*wordpress.Post, *http.Response, []byte, error)
+	GetFunc    func(id int, params interface{}) (*wordpress.Post, *http.Response, []byte, error)
+	EntityFunc func(id int) *wordpress.Post
+	UpdateFunc func(id int, post *wordpress.Post) (*wordpress.Post, *http.Response, []byte, error)
+	DeleteFunc func(id int, params interface{}) (*wordpress.Post, *http.Response, []byte, error)
+}
+
+var _ wordpress.PostsService = (*PostsService)(nil)
+
+func (m *PostsService) List(params interface{}) (r0 []wordpress.Post, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("List", params)
+	if m.ListFunc != nil {
+		return m.ListFunc(params)
+	}
+	return
+}
+
+func (m *PostsService) Create(new *wordpress.Post) (r0 *wordpress.Post, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Create", new)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(new)
+	}
+	return
+}
+
+func (m *PostsService) Get(id int, params interface{}) (r0 *wordpress.Post, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Get", id, params)
+	if m.GetFunc != nil {
+		return m.GetFunc(id, params)
+	}
+	return
+}
+
+func (m *PostsService) Entity(id int) (r0 *wordpress.Post) {
+	m.record("Entity", id)
+	if m.EntityFunc != nil {
+		return m.EntityFunc(id)
+	}
+	return
+}
+
+func (m *PostsService) Update(id int, post *wordpress.Post) (r0 *wordpress.Post, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Update", id, post)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(id, post)
+	}
+	return
+}
+
+func (m *PostsService) Delete(id int, params interface{}) (r0 *wordpress.Post, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Delete", id, params)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id, params)
+	}
+	return
+}
+
+// PagesService is a mock of wordpress.PagesService
+type PagesService struct {
+	Mock
+
+	ListFunc   func(params interface{}) ([]wordpress.Page, *http.Response, []byte, error)
+	CreateFunc func(new *wordpress.Page) (*wordpress.Page, *http.Response, []byte, error)
+	GetFunc    func(id int, params interface{}) (*wordpress.Page, *http.Response, []byte, error)
+	EntityFunc func(id int) *wordpress.Page
+	UpdateFunc func(id int, page *wordpress.Page) (*wordpress.Page, *http.Response, []byte, error)
+	DeleteFunc func(id int, params interface{}) (*wordpress.Page, *http.Response, []byte, error)
+}
+
+var _ wordpress.PagesService = (*PagesService)(nil)
+
+func (m *PagesService) List(params interface{}) (r0 []wordpress.Page, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("List", params)
+	if m.ListFunc != nil {
+		return m.ListFunc(params)
+	}
+	return
+}
+
+func (m *PagesService) Create(new *wordpress.Page) (r0 *wordpress.Page, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Create", new)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(new)
+	}
+	return
+}
+
+func (m *PagesService) Get(id int, params interface{}) (r0 *wordpress.Page, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Get", id, params)
+	if m.GetFunc != nil {
+		return m.GetFunc(id, params)
+	}
+	return
+}
+
+func (m *PagesService) Entity(id int) (r0 *wordpress.Page) {
+	m.record("Entity", id)
+	if m.EntityFunc != nil {
+		return m.EntityFunc(id)
+	}
+	return
+}
+
+func (m *PagesService) Update(id int, page *wordpress.Page) (r0 *wordpress.Page, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Update", id, page)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(id, page)
+	}
+	return
+}
+
+func (m *PagesService) Delete(id int, params interface{}) (r0 *wordpress.Page, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Delete", id, params)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id, params)
+	}
+	return
+}
+
+// MediaService is a mock of wordpress.MediaService
+type MediaService struct {
+	Mock
+
+	ListFunc     func(params interface{}) ([]wordpress.Media, *http.Response, []byte, error)
+	CreateFunc   func(options *wordpress.MediaUploadOptions) (*wordpress.Media, *http.Response, []byte, error)
+	GetFunc      func(id int, params interface{}) (*wordpress.Media, *http.Response, []byte, error)
+	UpdateFunc   func(id int, media *wordpress.Media) (*wordpress.Media, *http.Response, []byte, error)
+	AttachFunc   func(id int, postID int) (*wordpress.Media, *http.Response, []byte, error)
+	DownloadFunc func(id int, size string, w io.Writer) (int64, *http.Response, error)
+	DeleteFunc   func(id int, params interface{}) (*wordpress.Media, *http.Response, []byte, error)
+}
+
+var _ wordpress.MediaService = (*MediaService)(nil)
+
+func (m *MediaService) List(params interface{}) (r0 []wordpress.Media, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("List", params)
+	if m.ListFunc != nil {
+		return m.ListFunc(params)
+	}
+	return
+}
+
+func (m *MediaService) Create(options *wordpress.MediaUploadOptions) (r0 *wordpress.Media, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Create", options)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(options)
+	}
+	return
+}
+
+func (m *MediaService) Get(id int, params interface{}) (r0 *wordpress.Media, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Get", id, params)
+	if m.GetFunc != nil {
+		return m.GetFunc(id, params)
+	}
+	return
+}
+
+func (m *MediaService) Update(id int, media *wordpress.Media) (r0 *wordpress.Media, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Update", id, media)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(id, media)
+	}
+	return
+}
+
+func (m *MediaService) Attach(id int, postID int) (r0 *wordpress.Media, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Attach", id, postID)
+	if m.AttachFunc != nil {
+		return m.AttachFunc(id, postID)
+	}
+	return
+}
+
+func (m *MediaService) Download(id int, size string, w io.Writer) (r0 int64, r1 *http.Response, r2 error) {
+	m.record("Download", id, size, w)
+	if m.DownloadFunc != nil {
+		return m.DownloadFunc(id, size, w)
+	}
+	return
+}
+
+func (m *MediaService) Delete(id int, params interface{}) (r0 *wordpress.Media, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Delete", id, params)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id, params)
+	}
+	return
+}
+
+// CommentsService is a mock of wordpress.CommentsService
+type CommentsService struct {
+	Mock
+
+	ListFunc          func(params interface{}) ([]wordpress.Comment, *http.Response, []byte, error)
+	CreateFunc        func(new *wordpress.Comment) (*wordpress.Comment, *http.Response, []byte, error)
+	GetFunc           func(id int, params interface{}) (*wordpress.Comment, *http.Response, []byte, error)
+	EntityFunc        func(id int) *wordpress.Comment
+	UpdateFunc        func(id int, comment *wordpress.Comment) (*wordpress.Comment, *http.Response, []byte, error)
+	DeleteFunc        func(id int, params interface{}) (*wordpress.Comment, *http.Response, []byte, error)
+	TreeFunc          func(postID int, params string) (*wordpress.CommentTree, error)
+	ApproveFunc       func(id int) (*wordpress.Comment, *http.Response, []byte, error)
+	UnapproveFunc     func(id int) (*wordpress.Comment, *http.Response, []byte, error)
+	SpamFunc          func(id int) (*wordpress.Comment, *http.Response, []byte, error)
+	TrashFunc         func(id int) (*wordpress.Comment, *http.Response, []byte, error)
+	RestoreFunc       func(id int) (*wordpress.Comment, *http.Response, []byte, error)
+	ModerateFunc      func(id int, action wordpress.CommentAction) (*wordpress.Comment, *http.Response, []byte, error)
+	BulkModerateFunc  func(filter *wordpress.CommentFilter, action wordpress.CommentAction) ([]wordpress.ModerationResult, error)
+	RunModerationFunc func(pipeline *wordpress.ModerationPipeline) (*wordpress.ModerationReport, error)
+}
+
+var _ wordpress.CommentsService = (*CommentsService)(nil)
+
+func (m *CommentsService) List(params interface{}) (r0 []wordpress.Comment, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("List", params)
+	if m.ListFunc != nil {
+		return m.ListFunc(params)
+	}
+	return
+}
+
+func (m *CommentsService) Create(new *wordpress.Comment) (r0 *wordpress.Comment, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Create", new)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(new)
+	}
+	return
+}
+
+func (m *CommentsService) Get(id int, params interface{}) (r0 *wordpress.Comment, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Get", id, params)
+	if m.GetFunc != nil {
+		return m.GetFunc(id, params)
+	}
+	return
+}
+
+func (m *CommentsService) Entity(id int) (r0 *wordpress.Comment) {
+	m.record("Entity", id)
+	if m.EntityFunc != nil {
+		return m.EntityFunc(id)
+	}
+	return
+}
+
+func (m *CommentsService) Update(id int, comment *wordpress.Comment) (r0 *wordpress.Comment, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Update", id, comment)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(id, comment)
+	}
+	return
+}
+
+func (m *CommentsService) Delete(id int, params interface{}) (r0 *wordpress.Comment, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Delete", id, params)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id, params)
+	}
+	return
+}
+
+func (m *CommentsService) Tree(postID int, params string) (r0 *wordpress.CommentTree, r1 error) {
+	m.record("Tree", postID, params)
+	if m.TreeFunc != nil {
+		return m.TreeFunc(postID, params)
+	}
+	return
+}
+
+func (m *CommentsService) Approve(id int) (r0 *wordpress.Comment, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Approve", id)
+	if m.ApproveFunc != nil {
+		return m.ApproveFunc(id)
+	}
+	return
+}
+
+func (m *CommentsService) Unapprove(id int) (r0 *wordpress.Comment, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Unapprove", id)
+	if m.UnapproveFunc != nil {
+		return m.UnapproveFunc(id)
+	}
+	return
+}
+
+func (m *CommentsService) Spam(id int) (r0 *wordpress.Comment, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Spam", id)
+	if m.SpamFunc != nil {
+		return m.SpamFunc(id)
+	}
+	return
+}
+
+func (m *CommentsService) Trash(id int) (r0 *wordpress.Comment, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Trash", id)
+	if m.TrashFunc != nil {
+		return m.TrashFunc(id)
+	}
+	return
+}
+
+func (m *CommentsService) Restore(id int) (r0 *wordpress.Comment, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Restore", id)
+	if m.RestoreFunc != nil {
+		return m.RestoreFunc(id)
+	}
+	return
+}
+
+func (m *CommentsService) Moderate(id int, action wordpress.CommentAction) (r0 *wordpress.Comment, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Moderate", id, action)
+	if m.ModerateFunc != nil {
+		return m.ModerateFunc(id, action)
+	}
+	return
+}
+
+func (m *CommentsService) BulkModerate(filter *wordpress.CommentFilter, action wordpress.CommentAction) (r0 []wordpress.ModerationResult, r1 error) {
+	m.record("BulkModerate", filter, action)
+	if m.BulkModerateFunc != nil {
+		return m.BulkModerateFunc(filter, action)
+	}
+	return
+}
+
+func (m *CommentsService) RunModeration(pipeline *wordpress.ModerationPipeline) (r0 *wordpress.ModerationReport, r1 error) {
+	m.record("RunModeration", pipeline)
+	if m.RunModerationFunc != nil {
+		return m.RunModerationFunc(pipeline)
+	}
+	return
+}
+
+// TaxonomiesService is a mock of wordpress.TaxonomiesService
+type TaxonomiesService struct {
+	Mock
+
+	ListFunc func(params interface{}) (map[string]wordpress.Taxonomy, *http.Response, []byte, error)
+	GetFunc  func(slug string, params interface{}) (*wordpress.Taxonomy, *http.Response, []byte, error)
+}
+
+var _ wordpress.TaxonomiesService = (*TaxonomiesService)(nil)
+
+func (m *TaxonomiesService) List(params interface{}) (r0 map[string]wordpress.Taxonomy, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("List", params)
+	if m.ListFunc != nil {
+		return m.ListFunc(params)
+	}
+	return
+}
+
+func (m *TaxonomiesService) Get(slug string, params interface{}) (r0 *wordpress.Taxonomy, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Get", slug, params)
+	if m.GetFunc != nil {
+		return m.GetFunc(slug, params)
+	}
+	return
+}
+
+// TermsService is a mock of wordpress.TermsService
+type TermsService struct {
+	Mock
+
+	ListFunc     func(taxonomy string, params interface{}) ([]wordpress.Term, *http.Response, []byte, error)
+	TagFunc      func() wordpress.TermsTaxonomyService
+	TagMock      *TermsTaxonomyService
+	CategoryFunc func() wordpress.TermsTaxonomyService
+	CategoryMock *TermsTaxonomyService
+}
+
+var _ wordpress.TermsService = (*TermsService)(nil)
+
+func (m *TermsService) List(taxonomy string, params interface{}) (r0 []wordpress.Term, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("List", taxonomy, params)
+	if m.ListFunc != nil {
+		return m.ListFunc(taxonomy, params)
+	}
+	return
+}
+
+func (m *TermsService) Tag() (r0 wordpress.TermsTaxonomyService) {
+	m.record("Tag")
+	if m.TagFunc != nil {
+		return m.TagFunc()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.TagMock == nil {
+		m.TagMock = &TermsTaxonomyService{}
+	}
+	return m.TagMock
+}
+
+func (m *TermsService) Category() (r0 wordpress.TermsTaxonomyService) {
+	m.record("Category")
+	if m.CategoryFunc != nil {
+		return m.CategoryFunc()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.CategoryMock == nil {
+		m.CategoryMock = &TermsTaxonomyService{}
+	}
+	return m.CategoryMock
+}
+
+// TermsTaxonomyService is a mock of wordpress.TermsTaxonomyService
+type TermsTaxonomyService struct {
+	Mock
+
+	ListFunc   func(params interface{}) ([]wordpress.Term, *http.Response, []byte, error)
+	CreateFunc func(new *wordpress.Term) (*wordpress.Term, *http.Response, []byte, error)
+	GetFunc    func(id int, params interface{}) (*wordpress.Term, *http.Response, []byte, error)
+	EntityFunc func(id int) *wordpress.Term
+	UpdateFunc func(id int, term *wordpress.Term) (*wordpress.Term, *http.Response, []byte, error)
+	DeleteFunc func(id int, params interface{}) (*wordpress.Term, *http.Response, []byte, error)
+}
+
+var _ wordpress.TermsTaxonomyService = (*TermsTaxonomyService)(nil)
+
+func (m *TermsTaxonomyService) List(params interface{}) (r0 []wordpress.Term, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("List", params)
+	if m.ListFunc != nil {
+		return m.ListFunc(params)
+	}
+	return
+}
+
+func (m *TermsTaxonomyService) Create(new *wordpress.Term) (r0 *wordpress.Term, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Create", new)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(new)
+	}
+	return
+}
+
+func (m *TermsTaxonomyService) Get(id int, params interface{}) (r0 *wordpress.Term, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Get", id, params)
+	if m.GetFunc != nil {
+		return m.GetFunc(id, params)
+	}
+	return
+}
+
+func (m *TermsTaxonomyService) Entity(id int) (r0 *wordpress.Term) {
+	m.record("Entity", id)
+	if m.EntityFunc != nil {
+		return m.EntityFunc(id)
+	}
+	return
+}
+
+func (m *TermsTaxonomyService) Update(id int, term *wordpress.Term) (r0 *wordpress.Term, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Update", id, term)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(id, term)
+	}
+	return
+}
+
+func (m *TermsTaxonomyService) Delete(id int, params interface{}) (r0 *wordpress.Term, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Delete", id, params)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id, params)
+	}
+	return
+}
+
+// StatusesService is a mock of wordpress.StatusesService
+type StatusesService struct {
+	Mock
+
+	ListFunc func(params interface{}) (*wordpress.Statuses, *http.Response, []byte, error)
+	GetFunc  func(slug string, params interface{}) (*wordpress.Status, *http.Response, []byte, error)
+}
+
+var _ wordpress.StatusesService = (*StatusesService)(nil)
+
+func (m *StatusesService) List(params interface{}) (r0 *wordpress.Statuses, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("List", params)
+	if m.ListFunc != nil {
+		return m.ListFunc(params)
+	}
+	return
+}
+
+func (m *StatusesService) Get(slug string, params interface{}) (r0 *wordpress.Status, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Get", slug, params)
+	if m.GetFunc != nil {
+		return m.GetFunc(slug, params)
+	}
+	return
+}
+
+// TypesService is a mock of wordpress.TypesService
+type TypesService struct {
+	Mock
+
+	ListFunc func(params interface{}) (*wordpress.Types, *http.Response, []byte, error)
+	GetFunc  func(slug string, params interface{}) (*wordpress.Type, *http.Response, []byte, error)
+}
+
+var _ wordpress.TypesService = (*TypesService)(nil)
+
+func (m *TypesService) List(params interface{}) (r0 *wordpress.Types, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("List", params)
+	if m.ListFunc != nil {
+		return m.ListFunc(params)
+	}
+	return
+}
+
+func (m *TypesService) Get(slug string, params interface{}) (r0 *wordpress.Type, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Get", slug, params)
+	if m.GetFunc != nil {
+		return m.GetFunc(slug, params)
+	}
+	return
+}
+
+// MetaService is a mock of wordpress.MetaService
+type MetaService struct {
+	Mock
+
+	ListFunc   func(params interface{}) ([]wordpress.Meta, *http.Response, []byte, error)
+	CreateFunc func(new *wordpress.Meta) (*wordpress.Meta, *http.Response, []byte, error)
+	GetFunc    func(id int, params interface{}) (*wordpress.Meta, *http.Response, []byte, error)
+	UpdateFunc func(id int, meta *wordpress.Meta) (*wordpress.Meta, *http.Response, []byte, error)
+	DeleteFunc func(id int, params interface{}) (*wordpress.MetaDeletedResponse, *http.Response, []byte, error)
+	SetFunc    func(values map[string]interface{}) (*wordpress.MetaSyncResult, error)
+	SyncFunc   func(desired map[string]interface{}) (*wordpress.MetaSyncResult, error)
+}
+
+var _ wordpress.MetaService = (*MetaService)(nil)
+
+func (m *MetaService) List(params interface{}) (r0 []wordpress.Meta, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("List", params)
+	if m.ListFunc != nil {
+		return m.ListFunc(params)
+	}
+	return
+}
+
+func (m *MetaService) Create(new *wordpress.Meta) (r0 *wordpress.Meta, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Create", new)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(new)
+	}
+	return
+}
+
+func (m *MetaService) Get(id int, params interface{}) (r0 *wordpress.Meta, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Get", id, params)
+	if m.GetFunc != nil {
+		return m.GetFunc(id, params)
+	}
+	return
+}
+
+func (m *MetaService) Update(id int, meta *wordpress.Meta) (r0 *wordpress.Meta, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Update", id, meta)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(id, meta)
+	}
+	return
+}
+
+func (m *MetaService) Delete(id int, params interface{}) (r0 *wordpress.MetaDeletedResponse, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Delete", id, params)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id, params)
+	}
+	return
+}
+
+func (m *MetaService) Set(values map[string]interface{}) (r0 *wordpress.MetaSyncResult, r1 error) {
+	m.record("Set", values)
+	if m.SetFunc != nil {
+		return m.SetFunc(values)
+	}
+	return
+}
+
+func (m *MetaService) Sync(desired map[string]interface{}) (r0 *wordpress.MetaSyncResult, r1 error) {
+	m.record("Sync", desired)
+	if m.SyncFunc != nil {
+		return m.SyncFunc(desired)
+	}
+	return
+}
+
+// RevisionsService is a mock of wordpress.RevisionsService
+type RevisionsService struct {
+	Mock
+
+	ListFunc    func(params interface{}) ([]wordpress.Revision, *http.Response, []byte, error)
+	GetFunc     func(id int, params interface{}) (*wordpress.Revision, *http.Response, []byte, error)
+	DeleteFunc  func(id int, params interface{}) (bool, *http.Response, []byte, error)
+	DiffFunc    func(fromID int, toID int, granularity wordpress.DiffGranularity) (*wordpress.RevisionDiff, error)
+	RestoreFunc func(id int) (*http.Response, []byte, error)
+}
+
+var _ wordpress.RevisionsService = (*RevisionsService)(nil)
+
+func (m *RevisionsService) List(params interface{}) (r0 []wordpress.Revision, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("List", params)
+	if m.ListFunc != nil {
+		return m.ListFunc(params)
+	}
+	return
+}
+
+func (m *RevisionsService) Get(id int, params interface{}) (r0 *wordpress.Revision, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Get", id, params)
+	if m.GetFunc != nil {
+		return m.GetFunc(id, params)
+	}
+	return
+}
+
+func (m *RevisionsService) Delete(id int, params interface{}) (r0 bool, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Delete", id, params)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id, params)
+	}
+	return
+}
+
+func (m *RevisionsService) Diff(fromID int, toID int, granularity wordpress.DiffGranularity) (r0 *wordpress.RevisionDiff, r1 error) {
+	m.record("Diff", fromID, toID, granularity)
+	if m.DiffFunc != nil {
+		return m.DiffFunc(fromID, toID, granularity)
+	}
+	return
+}
+
+func (m *RevisionsService) Restore(id int) (r0 *http.Response, r1 []byte, r2 error) {
+	m.record("Restore", id)
+	if m.RestoreFunc != nil {
+		return m.RestoreFunc(id)
+	}
+	return
+}
+
+// AutosavesService is a mock of wordpress.AutosavesService
+type AutosavesService struct {
+	Mock
+
+	ListFunc    func(params interface{}) ([]wordpress.Autosave, *http.Response, []byte, error)
+	GetFunc     func(id int, params interface{}) (*wordpress.Autosave, *http.Response, []byte, error)
+	CreateFunc  func(new *wordpress.Autosave) (*wordpress.Autosave, *http.Response, []byte, error)
+	PreviewFunc func(changes *wordpress.Autosave) (*wordpress.Preview, *http.Response, []byte, error)
+}
+
+var _ wordpress.AutosavesService = (*AutosavesService)(nil)
+
+func (m *AutosavesService) List(params interface{}) (r0 []wordpress.Autosave, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("List", params)
+	if m.ListFunc != nil {
+		return m.ListFunc(params)
+	}
+	return
+}
+
+func (m *AutosavesService) Get(id int, params interface{}) (r0 *wordpress.Autosave, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Get", id, params)
+	if m.GetFunc != nil {
+		return m.GetFunc(id, params)
+	}
+	return
+}
+
+func (m *AutosavesService) Create(new *wordpress.Autosave) (r0 *wordpress.Autosave, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Create", new)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(new)
+	}
+	return
+}
+
+func (m *AutosavesService) Preview(changes *wordpress.Autosave) (r0 *wordpress.Preview, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Preview", changes)
+	if m.PreviewFunc != nil {
+		return m.PreviewFunc(changes)
+	}
+	return
+}
+
+// ApplicationPasswordsService is a mock of wordpress.ApplicationPasswordsService
+type ApplicationPasswordsService struct {
+	Mock
+
+	ListFunc       func(params interface{}) ([]wordpress.ApplicationPassword, *http.Response, []byte, error)
+	CreateFunc     func(new *wordpress.ApplicationPassword) (*wordpress.ApplicationPassword, *http.Response, []byte, error)
+	GetFunc        func(uuid string, params interface{}) (*wordpress.ApplicationPassword, *http.Response, []byte, error)
+	IntrospectFunc func(params interface{}) (*wordpress.ApplicationPassword, *http.Response, []byte, error)
+	RevokeFunc     func(uuid string) (*wordpress.ApplicationPasswordDeletedResponse, *http.Response, []byte, error)
+	RotateFunc     func(uuid string) (*wordpress.ApplicationPassword, error)
+}
+
+var _ wordpress.ApplicationPasswordsService = (*ApplicationPasswordsService)(nil)
+
+func (m *ApplicationPasswordsService) List(params interface{}) (r0 []wordpress.ApplicationPassword, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("List", params)
+	if m.ListFunc != nil {
+		return m.ListFunc(params)
+	}
+	return
+}
+
+func (m *ApplicationPasswordsService) Create(new *wordpress.ApplicationPassword) (r0 *wordpress.ApplicationPassword, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Create", new)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(new)
+	}
+	return
+}
+
+func (m *ApplicationPasswordsService) Get(uuid string, params interface{}) (r0 *wordpress.ApplicationPassword, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Get", uuid, params)
+	if m.GetFunc != nil {
+		return m.GetFunc(uuid, params)
+	}
+	return
+}
+
+func (m *ApplicationPasswordsService) Introspect(params interface{}) (r0 *wordpress.ApplicationPassword, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Introspect", params)
+	if m.IntrospectFunc != nil {
+		return m.IntrospectFunc(params)
+	}
+	return
+}
+
+func (m *ApplicationPasswordsService) Revoke(uuid string) (r0 *wordpress.ApplicationPasswordDeletedResponse, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Revoke", uuid)
+	if m.RevokeFunc != nil {
+		return m.RevokeFunc(uuid)
+	}
+	return
+}
+
+func (m *ApplicationPasswordsService) Rotate(uuid string) (r0 *wordpress.ApplicationPassword, r1 error) {
+	m.record("Rotate", uuid)
+	if m.RotateFunc != nil {
+		return m.RotateFunc(uuid)
+	}
+	return
+}
+
+// PostsTermsService is a mock of wordpress.PostsTermsService
+type PostsTermsService struct {
+	Mock
+
+	ListFunc     func(taxonomy string, params interface{}) ([]wordpress.PostsTerm, *http.Response, []byte, error)
+	TagFunc      func() wordpress.PostsTermsTaxonomyService
+	TagMock      *PostsTermsTaxonomyService
+	CategoryFunc func() wordpress.PostsTermsTaxonomyService
+	CategoryMock *PostsTermsTaxonomyService
+}
+
+var _ wordpress.PostsTermsService = (*PostsTermsService)(nil)
+
+func (m *PostsTermsService) List(taxonomy string, params interface{}) (r0 []wordpress.PostsTerm, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("List", taxonomy, params)
+	if m.ListFunc != nil {
+		return m.ListFunc(taxonomy, params)
+	}
+	return
+}
+
+func (m *PostsTermsService) Tag() (r0 wordpress.PostsTermsTaxonomyService) {
+	m.record("Tag")
+	if m.TagFunc != nil {
+		return m.TagFunc()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.TagMock == nil {
+		m.TagMock = &PostsTermsTaxonomyService{}
+	}
+	return m.TagMock
+}
+
+func (m *PostsTermsService) Category() (r0 wordpress.PostsTermsTaxonomyService) {
+	m.record("Category")
+	if m.CategoryFunc != nil {
+		return m.CategoryFunc()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.CategoryMock == nil {
+		m.CategoryMock = &PostsTermsTaxonomyService{}
+	}
+	return m.CategoryMock
+}
+
+// PostsTermsTaxonomyService is a mock of wordpress.PostsTermsTaxonomyService
+type PostsTermsTaxonomyService struct {
+	Mock
+
+	ListFunc   func(params interface{}) ([]wordpress.PostsTerm, *http.Response, []byte, error)
+	CreateFunc func(id int) (*wordpress.PostsTerm, *http.Response, []byte, error)
+	GetFunc    func(id int, params interface{}) (*wordpress.PostsTerm, *http.Response, []byte, error)
+	DeleteFunc func(id int, params interface{}) (*wordpress.PostsTerm, *http.Response, []byte, error)
+}
+
+var _ wordpress.PostsTermsTaxonomyService = (*PostsTermsTaxonomyService)(nil)
+
+func (m *PostsTermsTaxonomyService) List(params interface{}) (r0 []wordpress.PostsTerm, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("List", params)
+	if m.ListFunc != nil {
+		return m.ListFunc(params)
+	}
+	return
+}
+
+func (m *PostsTermsTaxonomyService) Create(id int) (r0 *wordpress.PostsTerm, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Create", id)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(id)
+	}
+	return
+}
+
+func (m *PostsTermsTaxonomyService) Get(id int, params interface{}) (r0 *wordpress.PostsTerm, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Get", id, params)
+	if m.GetFunc != nil {
+		return m.GetFunc(id, params)
+	}
+	return
+}
+
+func (m *PostsTermsTaxonomyService) Delete(id int, params interface{}) (r0 *wordpress.PostsTerm, r1 *http.Response, r2 []byte, r3 error) {
+	m.record("Delete", id, params)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id, params)
+	}
+	return
+}
@@ -0,0 +1,185 @@
+package wptest
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const applicationPasswordChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// notAlphanumeric matches the characters WordPress strips from application passwords before checking them,
+// so that they can be given with the spaces they are shown with
+var notAlphanumeric = regexp.MustCompile(`[^a-zA-Z0-9]`)
+
+// newUUID returns a random (version 4) UUID
+func newUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// newApplicationPassword returns a random password of 24 alphanumerics, like WP_Application_Passwords
+func newApplicationPassword() string {
+	b := make([]byte, 24)
+	rand.Read(b)
+	for i := range b {
+		b[i] = applicationPasswordChars[int(b[i])%len(applicationPasswordChars)]
+	}
+	return string(b)
+}
+
+// chunkApplicationPassword splits a password in groups of 4 characters, the way WordPress shows it
+func chunkApplicationPassword(password string) string {
+	var chunks []string
+	for i := 0; i < len(password); i += 4 {
+		end := i + 4
+		if end > len(password) {
+			end = len(password)
+		}
+		chunks = append(chunks, password[i:end])
+	}
+	return strings.Join(chunks, " ")
+}
+
+// useApplicationPassword returns the application password of the user matching the password, if any, and records
+// when and where from it was used
+func (s *Store) useApplicationPassword(user Object, password string, remoteAddr string) Object {
+	password = notAlphanumeric.ReplaceAllString(password, "")
+	for _, applicationPassword := range s.applicationPasswords {
+		if applicationPassword.Int("user") != user.Int("id") || applicationPassword.String("password") != password {
+			continue
+		}
+		if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+			remoteAddr = host
+		}
+		applicationPassword["last_used"] = s.now()
+		applicationPassword["last_ip"] = remoteAddr
+		return applicationPassword
+	}
+	return nil
+}
+
+// applicationPasswordsOf returns the application passwords of a user, oldest first
+func (s *Store) applicationPasswordsOf(userID int) []Object {
+	var out []Object
+	for _, applicationPassword := range s.applicationPasswords {
+		if applicationPassword.Int("user") == userID {
+			out = append(out, applicationPassword)
+		}
+	}
+	q := &listQuery{order: "asc", orderBy: "created"}
+	q.sort(out)
+	return out
+}
+
+func renderApplicationPassword(applicationPassword Object) Object {
+	out := applicationPassword.copy()
+	delete(out, "user")
+	delete(out, "password")
+	return out
+}
+
+// serveApplicationPasswords handles `/users/{id}/application-passwords`, `/users/{id}/application-passwords/introspect`
+// and `/users/{id}/application-passwords/{uuid}`
+func (s *Server) serveApplicationPasswords(w http.ResponseWriter, req *request, userSegment string, rest []string) {
+	user, ok := s.userOfSegment(w, req, userSegment)
+	if !ok {
+		return
+	}
+	if req.user == nil || (req.user.Int("id") != user.Int("id") && !can(req.user, "edit_users")) {
+		s.checkPermission(w, req, "edit_users", "rest_cannot_manage_application_passwords")
+		return
+	}
+	userID := user.Int("id")
+
+	if len(rest) == 0 {
+		switch req.method {
+		case "GET", "HEAD":
+			out := []Object{}
+			for _, applicationPassword := range s.Store.applicationPasswordsOf(userID) {
+				out = append(out, renderApplicationPassword(applicationPassword))
+			}
+			writeJSON(w, http.StatusOK, out)
+		case "POST":
+			name := strings.TrimSpace(req.body.String("name"))
+			if name == "" {
+				writeError(w, http.StatusBadRequest, "rest_invalid_param", "Invalid parameter(s): name")
+				return
+			}
+			password := newApplicationPassword()
+			applicationPassword := Object{
+				"uuid":      newUUID(),
+				"app_id":    req.body.String("app_id"),
+				"name":      name,
+				"created":   s.Store.now(),
+				"last_used": nil,
+				"last_ip":   nil,
+				"user":      userID,
+				"password":  password,
+			}
+			s.Store.applicationPasswords[applicationPassword.String("uuid")] = applicationPassword
+			out := renderApplicationPassword(applicationPassword)
+			out["password"] = chunkApplicationPassword(password)
+			writeJSON(w, http.StatusCreated, out)
+		case "DELETE":
+			count := 0
+			for _, applicationPassword := range s.Store.applicationPasswordsOf(userID) {
+				delete(s.Store.applicationPasswords, applicationPassword.String("uuid"))
+				count++
+			}
+			writeJSON(w, http.StatusOK, Object{"deleted": true, "count": count})
+		default:
+			writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+		}
+		return
+	}
+	if len(rest) > 1 {
+		writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+		return
+	}
+
+	if rest[0] == "introspect" {
+		if req.method != "GET" && req.method != "HEAD" {
+			writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+			return
+		}
+		if req.user.Int("id") != userID {
+			writeError(w, http.StatusForbidden, "rest_cannot_introspect_app_password_for_non_authenticated_user",
+				"The authenticated application password can only be introspected for the current user.")
+			return
+		}
+		if req.applicationPassword == nil {
+			writeError(w, http.StatusNotFound, "rest_no_authenticated_app_password",
+				"Cannot introspect application password.")
+			return
+		}
+		writeJSON(w, http.StatusOK, renderApplicationPassword(req.applicationPassword))
+		return
+	}
+
+	applicationPassword, ok := s.Store.applicationPasswords[rest[0]]
+	if !ok || applicationPassword.Int("user") != userID {
+		writeError(w, http.StatusNotFound, "application_password_not_found", "Application password not found.")
+		return
+	}
+	switch req.method {
+	case "GET", "HEAD":
+		writeJSON(w, http.StatusOK, renderApplicationPassword(applicationPassword))
+	case "POST", "PUT", "PATCH":
+		if name, ok := req.body["name"]; ok {
+			applicationPassword["name"] = strings.TrimSpace(fmt.Sprint(name))
+		}
+		writeJSON(w, http.StatusOK, renderApplicationPassword(applicationPassword))
+	case "DELETE":
+		delete(s.Store.applicationPasswords, rest[0])
+		writeJSON(w, http.StatusOK, Object{"deleted": true, "previous": renderApplicationPassword(applicationPassword)})
+	default:
+		writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+	}
+}
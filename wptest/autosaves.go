@@ -0,0 +1,206 @@
+package wptest
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// autosaveSlug is the slug WordPress gives to the autosave of a post, see wp_create_post_autosave()
+func autosaveSlug(parentID int) string {
+	return fmt.Sprintf("%v-autosave-v1", parentID)
+}
+
+// autosaveOf returns the autosave of a post by the given user, if any
+func (s *Store) autosaveOf(parentID int, author int) Object {
+	for _, post := range s.posts {
+		if post.String("type") == PostTypeRevision && post.Int("parent") == parentID &&
+			post.String("slug") == autosaveSlug(parentID) && post.Int("author") == author {
+			return post
+		}
+	}
+	return nil
+}
+
+// previewNonce stands for wp_create_nonce( 'post_preview_' . $post_id ), created for the given user
+func previewNonce(postID int, userID int) string {
+	return fmt.Sprintf("%x", sha1.Sum([]byte(fmt.Sprintf("post_preview_%v|%v", postID, userID))))[:10]
+}
+
+// previewLink returns the link to preview a post, with the nonce of the user for autosaves, like get_preview_post_link()
+func (s *Server) previewLink(post Object, autosave Object) string {
+	id := post.Int("id")
+	query := url.Values{}
+	if post.String("type") == PostTypePage {
+		query.Set("page_id", fmt.Sprint(id))
+	} else {
+		query.Set("p", fmt.Sprint(id))
+	}
+	if autosave != nil {
+		query.Set("preview_id", fmt.Sprint(id))
+		query.Set("preview_nonce", previewNonce(id, autosave.Int("author")))
+	}
+	query.Set("preview", "true")
+	return fmt.Sprintf("%v/?%v", s.URL, query.Encode())
+}
+
+// renderAutosave returns an autosave, or a draft autosaved in place, in the shape of the WordPress 5.0+ API
+func (s *Server) renderAutosave(autosave Object) Object {
+	out := Object{}
+	for _, field := range []string{"id", "author", "date", "date_gmt", "modified", "modified_gmt", "parent", "slug", "title", "content", "excerpt"} {
+		out[field] = autosave[field]
+	}
+	out["guid"] = renderedField("guid", fmt.Sprintf("%v/?p=%v", s.URL, autosave.Int("id")))
+	if autosave.String("type") == PostTypeRevision {
+		out["preview_link"] = s.previewLink(s.Store.posts[autosave.Int("parent")], autosave)
+	} else {
+		out["preview_link"] = s.previewLink(autosave, nil)
+	}
+	return out
+}
+
+func (s *Server) serveAutosaves(w http.ResponseWriter, req *request, postType string, parentSegment string, rest []string) {
+	parent, ok := s.Store.posts[parseID(parentSegment)]
+	if !ok || parent.String("type") != postType {
+		writeInvalidID(w, "rest_post_invalid_parent")
+		return
+	}
+	if !s.checkPermission(w, req, editCapability(postType), "rest_cannot_read") {
+		return
+	}
+
+	if len(rest) == 0 {
+		switch req.method {
+		case "GET", "HEAD":
+			var autosaves []Object
+			for _, post := range s.Store.posts {
+				if post.String("type") == PostTypeRevision && post.Int("parent") == parent.Int("id") &&
+					post.String("slug") == autosaveSlug(parent.Int("id")) {
+					autosaves = append(autosaves, post)
+				}
+			}
+			q := &listQuery{order: "desc", orderBy: "date"}
+			q.sort(autosaves)
+			out := []Object{}
+			for _, autosave := range autosaves {
+				out = append(out, s.renderAutosave(autosave))
+			}
+			writeJSON(w, http.StatusOK, out)
+		case "POST":
+			s.createAutosave(w, req, parent)
+		default:
+			writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+		}
+		return
+	}
+
+	autosave, ok := s.Store.posts[parseID(rest[0])]
+	if len(rest) > 1 || !ok || autosave.String("type") != PostTypeRevision || autosave.Int("parent") != parent.Int("id") ||
+		autosave.String("slug") != autosaveSlug(parent.Int("id")) {
+		writeInvalidID(w, "rest_post_invalid_id")
+		return
+	}
+	if req.method != "GET" && req.method != "HEAD" {
+		writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.renderAutosave(autosave))
+}
+
+// createAutosave saves the changes like WP_REST_Autosaves_Controller: a draft edited by its author is updated in
+// place (without a revision), otherwise the autosave of the user is created or replaced
+func (s *Server) createAutosave(w http.ResponseWriter, req *request, parent Object) {
+	user := req.user.Int("id")
+	changed := parent.copy()
+	for _, field := range []string{"title", "content", "excerpt"} {
+		if value, ok := req.body[field]; ok {
+			changed[field] = renderedField(field, value)
+		}
+	}
+	now := s.Store.now()
+
+	if parent.String("status") == "draft" && parent.Int("author") == user {
+		changed["modified"] = now
+		changed["modified_gmt"] = now
+		s.Store.posts[parent.Int("id")] = changed
+		writeJSON(w, http.StatusOK, s.renderAutosave(changed))
+		return
+	}
+
+	existing := s.Store.autosaveOf(parent.Int("id"), user)
+	different := false
+	for _, field := range []string{"title", "content", "excerpt"} {
+		if changed.Raw(field) != parent.Raw(field) {
+			different = true
+		}
+	}
+	if !different {
+		if existing != nil {
+			delete(s.Store.posts, existing.Int("id"))
+		}
+		writeError(w, http.StatusBadRequest, "rest_autosave_no_changes", "There is nothing to save. The autosave and the post content are the same.")
+		return
+	}
+
+	autosave := Object{
+		"parent":       parent.Int("id"),
+		"author":       user,
+		"title":        changed["title"],
+		"content":      changed["content"],
+		"excerpt":      changed["excerpt"],
+		"slug":         autosaveSlug(parent.Int("id")),
+		"date":         now,
+		"date_gmt":     now,
+		"modified":     now,
+		"modified_gmt": now,
+	}
+	if existing != nil {
+		autosave["id"] = existing.Int("id")
+		autosave["date"] = existing["date"]
+		autosave["date_gmt"] = existing["date_gmt"]
+	}
+	autosave = s.Store.insertPost(PostTypeRevision, autosave)
+	writeJSON(w, http.StatusOK, s.renderAutosave(autosave))
+}
+
+// servePreview renders a post like the front end does for preview links. With a preview nonce, the autosave of the
+// user it was created for is shown; without one, the post itself is shown to users who can edit it (with basic auth
+// here, instead of the login cookies WordPress requires).
+func (s *Server) servePreview(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	id := parseID(query.Get("p"))
+	if id == 0 {
+		id = parseID(query.Get("page_id"))
+	}
+	post, ok := s.Store.posts[id]
+	if !ok || (post.String("type") != PostTypePost && post.String("type") != PostTypePage) {
+		http.NotFound(w, r)
+		return
+	}
+
+	preview := post
+	if nonce := query.Get("preview_nonce"); nonce != "" {
+		var user Object
+		for _, u := range s.Store.users {
+			if previewNonce(id, u.Int("id")) == nonce {
+				user = u
+			}
+		}
+		if user == nil || parseID(query.Get("preview_id")) != id {
+			http.Error(w, "The link you followed has expired.", http.StatusForbidden)
+			return
+		}
+		if autosave := s.Store.autosaveOf(id, user.Int("id")); autosave != nil {
+			preview = autosave
+		}
+	} else if user, _ := s.authenticate(r); !s.isPublic(post) && !can(user, editCapability(post.String("type"))) {
+		http.NotFound(w, r)
+		return
+	}
+
+	title := preview.Rendered("title")
+	content := preview.Rendered("content")
+	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+	fmt.Fprintf(w, "<!DOCTYPE html>\n<html>\n<head><title>%v</title></head>\n<body>\n<h1>%v</h1>\n%v</body>\n</html>\n", title, title, content)
+}
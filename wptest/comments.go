@@ -0,0 +1,287 @@
+package wptest
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const (
+	CommentStatusApproved = "approved"
+	CommentStatusHold     = "hold"
+	CommentStatusSpam     = "spam"
+	CommentStatusTrash    = "trash"
+)
+
+// commentEditOnlyFields are only returned in the `edit` context
+var commentEditOnlyFields = []string{"author_email", "author_ip", "author_user_agent", "karma"}
+
+// commentFields are the fields of a comment that can be written through the API
+var commentFields = []string{
+	"author", "author_email", "author_ip", "author_name", "author_url", "author_user_agent", "content", "date",
+	"date_gmt", "karma", "parent", "post", "status", "type",
+}
+
+// commentTrashMetaStatus is the comment meta keeping the status of a comment before it was trashed or marked as spam
+const commentTrashMetaStatus = "_wp_trash_meta_status"
+
+// normalizeCommentStatus maps the status names accepted by WordPress to the ones WP-API returns
+func normalizeCommentStatus(status string) string {
+	switch status {
+	case "1", "approve", CommentStatusApproved:
+		return CommentStatusApproved
+	case "0", "unapproved", CommentStatusHold:
+		return CommentStatusHold
+	case CommentStatusSpam, CommentStatusTrash:
+		return status
+	}
+	return ""
+}
+
+// setCommentStatus changes the status of a comment like wp_set_comment_status() and friends. `untrash` and `unspam`
+// restore the status the comment had before it was trashed or marked as spam (or hold, if unknown).
+func (s *Store) setCommentStatus(comment Object, status string) {
+	id := comment.Int("id")
+	var previous string
+	for _, m := range s.metaOf("comments", id) {
+		if m.Key == commentTrashMetaStatus {
+			previous = fmt.Sprint(m.Value)
+			delete(s.meta, m.ID)
+		}
+	}
+	switch status {
+	case "untrash", "unspam":
+		status = previous
+		if status == "" {
+			status = CommentStatusHold
+		}
+	case CommentStatusTrash, CommentStatusSpam:
+		if id != 0 {
+			s.insertMeta("comments", id, commentTrashMetaStatus, comment.String("status"))
+		}
+	}
+	comment["status"] = status
+}
+
+// renderComment returns the comment as WP-API shows it in the given context
+func (s *Server) renderComment(comment Object, context string) Object {
+	out := comment.copy()
+	out["link"] = fmt.Sprintf("%v/?p=%v#comment-%v", s.URL, comment.Int("post"), comment.Int("id"))
+	out["meta"] = s.Store.renderRegisteredMeta("comments", comment.Int("id"))
+	return stripContext(out, context, commentEditOnlyFields)
+}
+
+func (s *Server) serveComments(w http.ResponseWriter, req *request) {
+	switch req.method {
+	case "GET", "HEAD":
+		s.listComments(w, req)
+	case "POST":
+		s.createComment(w, req)
+	default:
+		writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+	}
+}
+
+func (s *Server) serveComment(w http.ResponseWriter, req *request, segment string) {
+	comment, ok := s.Store.comments[parseID(segment)]
+	if !ok {
+		writeInvalidID(w, "rest_comment_invalid_id")
+		return
+	}
+	switch req.method {
+	case "GET", "HEAD":
+		s.getComment(w, req, comment)
+	case "POST", "PUT", "PATCH":
+		s.updateComment(w, req, comment)
+	case "DELETE":
+		s.deleteComment(w, req, comment)
+	default:
+		writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+	}
+}
+
+func (s *Server) listComments(w http.ResponseWriter, req *request) {
+	if req.context() == "edit" && !s.checkPermission(w, req, "moderate_comments", "rest_forbidden_context") {
+		return
+	}
+	q, err := req.listQuery("date", "desc")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "rest_invalid_param", err.Error())
+		return
+	}
+	status := CommentStatusApproved
+	if param := req.param("status", "status"); param != "" {
+		status = normalizeCommentStatus(param)
+		if status == "" && param != "all" {
+			writeError(w, http.StatusBadRequest, "rest_invalid_param", "Invalid parameter(s): status")
+			return
+		}
+	}
+	if status != CommentStatusApproved && !s.checkPermission(w, req, "moderate_comments", "rest_forbidden_param") {
+		return
+	}
+	posts := req.intList("post")
+	parents := req.intList("parent")
+	authors := req.intList("author")
+	authorEmail := req.param("author_email", "")
+
+	var comments []Object
+	for _, comment := range s.Store.comments {
+		// `all` lists every comment but spam and trash, like the WordPress admin
+		if status == "" && (comment.String("status") == CommentStatusSpam || comment.String("status") == CommentStatusTrash) {
+			continue
+		}
+		if status != "" && comment.String("status") != status {
+			continue
+		}
+		if len(posts) > 0 && !containsInt(posts, comment.Int("post")) {
+			continue
+		}
+		if len(parents) > 0 && !containsInt(parents, comment.Int("parent")) {
+			continue
+		}
+		if len(authors) > 0 && !containsInt(authors, comment.Int("author")) {
+			continue
+		}
+		if authorEmail != "" && comment.String("author_email") != authorEmail {
+			continue
+		}
+		comments = append(comments, comment)
+	}
+	comments = q.filter(comments, "content", "author_name", "author_email", "author_url")
+	q.sort(comments)
+
+	var out []Object
+	for _, comment := range comments {
+		out = append(out, s.renderComment(comment, req.context()))
+	}
+	s.writeList(w, req, q, out)
+}
+
+func (s *Server) getComment(w http.ResponseWriter, req *request, comment Object) {
+	if (req.context() == "edit" || comment.String("status") != CommentStatusApproved) &&
+		!s.checkPermission(w, req, "moderate_comments", "rest_forbidden_context") {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.renderComment(comment, req.context()))
+}
+
+// applyCommentFields copies the writable fields of the request body onto the comment
+func (s *Server) applyCommentFields(w http.ResponseWriter, req *request, comment Object) bool {
+	for _, field := range commentFields {
+		value, ok := req.body[field]
+		if !ok {
+			continue
+		}
+		switch field {
+		case "content":
+			comment[field] = renderedField(field, value)
+		case "status":
+			status := Object(req.body).String(field)
+			if status != "untrash" && status != "unspam" {
+				status = normalizeCommentStatus(status)
+			}
+			if status == "" {
+				writeError(w, http.StatusBadRequest, "rest_invalid_param", "Invalid parameter(s): status")
+				return false
+			}
+			if status != comment.String("status") && !s.checkPermission(w, req, "moderate_comments", "rest_comment_invalid_status") {
+				return false
+			}
+			if status != comment.String("status") {
+				s.Store.setCommentStatus(comment, status)
+			}
+		case "post":
+			post, ok := s.Store.posts[toInt(value)]
+			if !ok || post.String("type") == PostTypeRevision {
+				writeError(w, http.StatusBadRequest, "rest_comment_invalid_post_id", "Invalid post ID.")
+				return false
+			}
+			comment[field] = post.Int("id")
+		case "parent":
+			parent := toInt(value)
+			if _, ok := s.Store.comments[parent]; parent != 0 && !ok {
+				writeError(w, http.StatusBadRequest, "rest_comment_invalid_parent", "Invalid comment parent ID.")
+				return false
+			}
+			comment[field] = parent
+		case "author", "karma":
+			comment[field] = toInt(value)
+		default:
+			comment[field] = Object(req.body).String(field)
+		}
+	}
+	return true
+}
+
+func (s *Server) createComment(w http.ResponseWriter, req *request) {
+	if req.body.Int("id") != 0 {
+		writeError(w, http.StatusBadRequest, "rest_comment_exists", "Cannot create existing comment.")
+		return
+	}
+	post, ok := s.Store.posts[req.body.Int("post")]
+	if !ok || post.String("type") == PostTypeRevision {
+		writeError(w, http.StatusBadRequest, "rest_comment_invalid_post_id", "Invalid post ID.")
+		return
+	}
+	if post.String("comment_status") == "closed" && !can(req.user, "moderate_comments") {
+		writeError(w, http.StatusForbidden, "rest_comment_closed", "Sorry, comments are closed for this item.")
+		return
+	}
+
+	// comments by anyone but moderators are held for moderation
+	comment := Object{"status": CommentStatusHold, "author": req.user.Int("id")}
+	if can(req.user, "moderate_comments") {
+		comment["status"] = CommentStatusApproved
+	}
+	if req.user != nil {
+		comment["author_name"] = req.user.String("name")
+		comment["author_email"] = req.user.String("email")
+		comment["author_url"] = req.user.String("url")
+	}
+	comment["author_ip"] = req.RemoteAddr
+	comment["author_user_agent"] = req.UserAgent()
+	if !s.applyCommentFields(w, req, comment) {
+		return
+	}
+	created := s.Store.insertComment(comment)
+	s.Store.setRegisteredMeta("comments", created.Int("id"), req.body["meta"])
+	w.Header().Set("Location", fmt.Sprintf("%v/comments/%v", s.BaseAPIURL(), created.Int("id")))
+	writeJSON(w, http.StatusCreated, s.renderComment(created, "edit"))
+}
+
+func (s *Server) updateComment(w http.ResponseWriter, req *request, comment Object) {
+	isAuthor := req.user != nil && req.user.Int("id") == comment.Int("author")
+	if !isAuthor && !s.checkPermission(w, req, "moderate_comments", "rest_cannot_edit") {
+		return
+	}
+	updated := comment.copy()
+	if !s.applyCommentFields(w, req, updated) {
+		return
+	}
+	s.Store.comments[comment.Int("id")] = updated
+	s.Store.setRegisteredMeta("comments", comment.Int("id"), req.body["meta"])
+	writeJSON(w, http.StatusOK, s.renderComment(updated, "edit"))
+}
+
+func (s *Server) deleteComment(w http.ResponseWriter, req *request, comment Object) {
+	if !s.checkPermission(w, req, "moderate_comments", "rest_cannot_delete") {
+		return
+	}
+	id := comment.Int("id")
+	if !req.force() {
+		if comment.String("status") == CommentStatusTrash {
+			writeError(w, http.StatusGone, "rest_already_trashed", "The comment has already been trashed.")
+			return
+		}
+		trashed := comment.copy()
+		s.Store.setCommentStatus(trashed, CommentStatusTrash)
+		s.Store.comments[id] = trashed
+		writeJSON(w, http.StatusOK, s.renderComment(trashed, "edit"))
+		return
+	}
+	delete(s.Store.comments, id)
+	for _, m := range s.Store.metaOf("comments", id) {
+		delete(s.Store.meta, m.ID)
+	}
+	writeJSON(w, http.StatusOK, s.renderComment(comment, "edit"))
+}
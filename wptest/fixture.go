@@ -0,0 +1,78 @@
+package wptest
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"time"
+)
+
+const (
+	// DefaultUsername and DefaultPassword are the credentials of the administrator seeded by NewFixtureStore
+	DefaultUsername = "go-wordpress"
+	DefaultPassword = "go-wordpress"
+
+	// FixtureWXR is the WXR export the fixture is loaded from, relative to the root of the repository
+	FixtureWXR = "test-data/go-wordpress.wordpress.2015-08-23.xml"
+
+	// fixtureDate is the date of the export; objects created afterwards are dated after it
+	fixtureDate = "2015-08-23T11:42:21"
+)
+
+// NewFixtureStore returns a store seeded with the content the package tests assume: the content of the
+// WXR export read from wxr (FixtureWXR: the "Hello world!" post with its comment, the "Sample Page" page, ...),
+// imported for the administrator DefaultUsername, plus what the test environment setup adds by hand: a revision
+// of the most recent post and page, and an uploaded image.
+func NewFixtureStore(wxr io.Reader) (*Store, error) {
+	store := NewStore()
+	now, _ := time.Parse(dateLayout, fixtureDate)
+	store.Now = func() time.Time {
+		// keep dates after the seeded content, so new objects are listed first
+		now = now.Add(time.Second)
+		return now
+	}
+
+	store.AddUser(Object{
+		"id":       1,
+		"username": DefaultUsername,
+		"password": DefaultPassword,
+		"name":     "go-wordpress",
+		"email":    "sgk.sprm+go-wordpress@gmail.com",
+		"roles":    []string{RoleAdministrator},
+	})
+
+	if err := store.LoadWXR(wxr); err != nil {
+		return nil, fmt.Errorf("wptest: unable to load fixture: %v", err)
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	for _, id := range []int{1, 2} {
+		post, ok := store.posts[id]
+		if !ok {
+			return nil, fmt.Errorf("wptest: fixture has no post %v to revise", id)
+		}
+		store.insertRevision(post, 1)
+	}
+
+	attachment := store.newAttachment("go-wordpress.jpg", "image/jpeg", fixtureImage())
+	attachment["author"] = 1
+	store.insertPost(PostTypeAttachment, attachment)
+	return store, nil
+}
+
+// fixtureImage returns a small JPEG, large enough to have thumbnail and medium sizes
+func fixtureImage() []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 400, 300))
+	for x := 0; x < 400; x++ {
+		for y := 0; y < 300; y++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 0x80, 0xff})
+		}
+	}
+	var buf bytes.Buffer
+	jpeg.Encode(&buf, img, nil)
+	return buf.Bytes()
+}
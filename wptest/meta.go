@@ -0,0 +1,227 @@
+package wptest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// isProtectedMeta returns true for meta keys that WordPress hides from the API, such as `_edit_lock`
+func isProtectedMeta(key string) bool {
+	return strings.HasPrefix(key, "_")
+}
+
+// metaObjectType returns the object type register_meta() takes for the meta of a collection, for eg. `post` for `pages`
+func metaObjectType(parentType string) string {
+	switch parentType {
+	case "users":
+		return "user"
+	case "comments":
+		return "comment"
+	case "terms":
+		return "term"
+	}
+	return "post"
+}
+
+// RegisterMeta exposes a meta key in the `meta` object of the posts (pages and media included), users, comments or
+// terms, like register_meta() with `show_in_rest`. objectType is `post`, `user`, `comment` or `term`.
+func (s *Store) RegisterMeta(objectType string, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !containsString(s.registeredMeta[objectType], key) {
+		s.registeredMeta[objectType] = append(s.registeredMeta[objectType], key)
+	}
+}
+
+// renderRegisteredMeta returns the `meta` object of an object, with the first value of each registered key (null if
+// unset). Without registered keys, it is an empty list, as PHP encodes empty arrays.
+func (s *Store) renderRegisteredMeta(parentType string, parentID int) interface{} {
+	keys := s.registeredMeta[metaObjectType(parentType)]
+	if len(keys) == 0 {
+		return []interface{}{}
+	}
+	out := map[string]interface{}{}
+	for _, key := range keys {
+		out[key] = nil
+	}
+	entries := s.metaOf(parentType, parentID)
+	for i := len(entries) - 1; i >= 0; i-- {
+		if _, ok := out[entries[i].Key]; ok {
+			out[entries[i].Key] = entries[i].Value
+		}
+	}
+	return out
+}
+
+// setRegisteredMeta writes the registered keys of a `meta` object like WP_REST_Meta_Fields: null values delete the
+// key, and unregistered keys are ignored
+func (s *Store) setRegisteredMeta(parentType string, parentID int, values interface{}) {
+	object, ok := values.(map[string]interface{})
+	if !ok {
+		return
+	}
+	for _, key := range s.registeredMeta[metaObjectType(parentType)] {
+		value, ok := object[key]
+		if !ok {
+			continue
+		}
+		set := false
+		for _, m := range s.metaOf(parentType, parentID) {
+			if m.Key != key {
+				continue
+			}
+			if value == nil || set {
+				delete(s.meta, m.ID)
+				continue
+			}
+			m.Value, set = value, true
+		}
+		if value != nil && !set {
+			s.insertMeta(parentType, parentID, key, value)
+		}
+	}
+}
+
+func renderMeta(m *Meta) Object {
+	return Object{
+		"id":    m.ID,
+		"key":   m.Key,
+		"value": m.Value,
+	}
+}
+
+// hasMeta returns true if the object has a meta entry with the key, and the value unless it is empty
+func (s *Server) hasMeta(parentType string, parentID int, key string, value string) bool {
+	for _, m := range s.Store.metaOf(parentType, parentID) {
+		if m.Key == key && (value == "" || fmt.Sprint(m.Value) == value) {
+			return true
+		}
+	}
+	return false
+}
+
+// serveMeta handles `/{collection}/{id}/meta` and `/{collection}/{id}/meta/{meta}` for posts, pages and media
+func (s *Server) serveMeta(w http.ResponseWriter, req *request, collection string, postType string, parentSegment string, rest []string) {
+	parent, ok := s.Store.posts[parseID(parentSegment)]
+	if !ok || parent.String("type") != postType {
+		writeInvalidID(w, "rest_post_invalid_id")
+		return
+	}
+	if !s.checkPermission(w, req, editCapability(postType), "rest_forbidden") {
+		return
+	}
+	s.serveObjectMeta(w, req, collection, fmt.Sprintf("%v/%v", collection, parent.Int("id")), parent.Int("id"), rest)
+}
+
+// serveUserMeta handles `/users/{id}/meta`; users may edit their own meta
+func (s *Server) serveUserMeta(w http.ResponseWriter, req *request, segment string, rest []string) {
+	user, ok := s.userOfSegment(w, req, segment)
+	if !ok {
+		return
+	}
+	isSelf := req.user != nil && req.user.Int("id") == user.Int("id")
+	if !isSelf && !s.checkPermission(w, req, "edit_users", "rest_forbidden") {
+		return
+	}
+	s.serveObjectMeta(w, req, "users", fmt.Sprintf("users/%v", user.Int("id")), user.Int("id"), rest)
+}
+
+// serveCommentMeta handles `/comments/{id}/meta`
+func (s *Server) serveCommentMeta(w http.ResponseWriter, req *request, segment string, rest []string) {
+	comment, ok := s.Store.comments[parseID(segment)]
+	if !ok {
+		writeInvalidID(w, "rest_comment_invalid_id")
+		return
+	}
+	if !s.checkPermission(w, req, "moderate_comments", "rest_forbidden") {
+		return
+	}
+	s.serveObjectMeta(w, req, "comments", fmt.Sprintf("comments/%v", comment.Int("id")), comment.Int("id"), rest)
+}
+
+// serveTermMeta handles `/terms/{tax_base}/{id}/meta`
+func (s *Server) serveTermMeta(w http.ResponseWriter, req *request, base string, segment string, rest []string) {
+	taxonomy, ok := taxonomyOfBase(base)
+	if !ok {
+		writeError(w, http.StatusNotFound, "rest_taxonomy_invalid", "Invalid taxonomy.")
+		return
+	}
+	term, ok := s.Store.terms[parseID(segment)]
+	if !ok || term.String("taxonomy") != taxonomy {
+		writeInvalidID(w, "rest_term_invalid")
+		return
+	}
+	if !s.checkPermission(w, req, "manage_categories", "rest_forbidden") {
+		return
+	}
+	s.serveObjectMeta(w, req, "terms", fmt.Sprintf("terms/%v/%v", base, term.Int("id")), term.Int("id"), rest)
+}
+
+// serveObjectMeta serves the meta of an object once found and the permission checked. parentType is the collection
+// the meta belongs to, and parentPath the route of the object, for eg. `terms/category/1`.
+func (s *Server) serveObjectMeta(w http.ResponseWriter, req *request, parentType string, parentPath string, parentID int, rest []string) {
+	if len(rest) == 0 {
+		switch req.method {
+		case "GET", "HEAD":
+			out := []Object{}
+			for _, m := range s.Store.metaOf(parentType, parentID) {
+				if !isProtectedMeta(m.Key) {
+					out = append(out, renderMeta(m))
+				}
+			}
+			writeJSON(w, http.StatusOK, out)
+		case "POST":
+			key := req.body.String("key")
+			if key == "" {
+				writeError(w, http.StatusBadRequest, "rest_meta_invalid_key", "Invalid meta key.")
+				return
+			}
+			if isProtectedMeta(key) {
+				writeError(w, http.StatusForbidden, "rest_meta_protected", fmt.Sprintf("%v is marked as a protected field.", key))
+				return
+			}
+			m := s.Store.insertMeta(parentType, parentID, key, req.body["value"])
+			w.Header().Set("Location", fmt.Sprintf("%v/%v/meta/%v", s.BaseAPIURL(), parentPath, m.ID))
+			writeJSON(w, http.StatusCreated, renderMeta(m))
+		default:
+			writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+		}
+		return
+	}
+
+	m, ok := s.Store.meta[parseID(rest[0])]
+	if len(rest) > 1 || !ok || m.ParentType != parentType || m.ParentID != parentID {
+		writeInvalidID(w, "rest_meta_invalid_id")
+		return
+	}
+	if isProtectedMeta(m.Key) {
+		writeError(w, http.StatusForbidden, "rest_meta_protected", fmt.Sprintf("%v is marked as a protected field.", m.Key))
+		return
+	}
+	switch req.method {
+	case "GET", "HEAD":
+		writeJSON(w, http.StatusOK, renderMeta(m))
+	case "POST", "PUT", "PATCH":
+		if key := req.body.String("key"); key != "" {
+			if isProtectedMeta(key) {
+				writeError(w, http.StatusForbidden, "rest_meta_protected", fmt.Sprintf("%v is marked as a protected field.", key))
+				return
+			}
+			m.Key = key
+		}
+		if value, ok := req.body["value"]; ok {
+			m.Value = value
+		}
+		writeJSON(w, http.StatusOK, renderMeta(m))
+	case "DELETE":
+		if !req.force() {
+			writeError(w, http.StatusNotImplemented, "rest_trash_not_supported", "Meta does not support trashing.")
+			return
+		}
+		delete(s.Store.meta, m.ID)
+		writeJSON(w, http.StatusOK, Object{"message": "Deleted meta"})
+	default:
+		writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+	}
+}
@@ -0,0 +1,706 @@
+package wptest
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var (
+	postStatuses = []string{"publish", "future", "draft", "pending", "private"}
+	postFormats  = []string{"standard", "aside", "gallery", "image", "link", "status", "quote", "video", "chat"}
+
+	// defaultImageSizes are the intermediate image sizes registered by default
+	defaultImageSizes = []imageSize{
+		{"thumbnail", 150, 150, true},
+		{"medium", 300, 300, false},
+		{"medium_large", 768, 0, false},
+		{"large", 1024, 1024, false},
+	}
+)
+
+// imageSize is an intermediate image size, as registered by add_image_size(); a width or height of 0 is unbounded
+type imageSize struct {
+	name          string
+	width, height int
+	crop          bool
+}
+
+// postFields are the fields of each post type that can be written through the API
+var postFields = map[string][]string{
+	PostTypePost: {
+		"date", "date_gmt", "password", "slug", "status", "title", "content", "author", "excerpt",
+		"featured_image", "comment_status", "ping_status", "format", "sticky", "categories", "tags",
+	},
+	PostTypePage: {
+		"date", "date_gmt", "password", "slug", "status", "parent", "title", "content", "author", "excerpt",
+		"featured_image", "comment_status", "ping_status", "menu_order", "template",
+	},
+	PostTypeAttachment: {
+		"date", "date_gmt", "slug", "status", "title", "author", "comment_status", "ping_status",
+		"alt_text", "caption", "description", "post",
+	},
+}
+
+// postEditOnlyFields are only returned in the `edit` context
+var postEditOnlyFields = []string{"password"}
+
+func postTypeOfCollection(collection string) string {
+	switch collection {
+	case "pages":
+		return PostTypePage
+	case "media":
+		return PostTypeAttachment
+	}
+	return PostTypePost
+}
+
+func collectionOfPostType(postType string) string {
+	switch postType {
+	case PostTypePage:
+		return "pages"
+	case PostTypeAttachment:
+		return "media"
+	}
+	return "posts"
+}
+
+// editCapability returns the capability required to write posts of the given type
+func editCapability(postType string) string {
+	switch postType {
+	case PostTypePage:
+		return "edit_pages"
+	case PostTypeAttachment:
+		return "upload_files"
+	}
+	return "edit_posts"
+}
+
+// isPublic returns true if anyone may read the post
+func (s *Server) isPublic(post Object) bool {
+	switch post.String("status") {
+	case "publish":
+		return true
+	case "inherit":
+		parent, ok := s.Store.posts[post.Int("post")]
+		return !ok || parent.String("type") == PostTypeAttachment || s.isPublic(parent)
+	}
+	return false
+}
+
+// renderPost returns the post as WP-API shows it in the given context
+func (s *Server) renderPost(post Object, context string) Object {
+	out := post.copy()
+	id := out.Int("id")
+	switch out.String("type") {
+	case PostTypePage:
+		setDefault(out, "link", fmt.Sprintf("%v/?page_id=%v", s.URL, id))
+	case PostTypeAttachment:
+		setDefault(out, "link", fmt.Sprintf("%v/?attachment_id=%v", s.URL, id))
+	default:
+		setDefault(out, "link", fmt.Sprintf("%v/?p=%v", s.URL, id))
+	}
+	if out.Raw("guid") == "" {
+		out["guid"] = renderedField("guid", out.String("link"))
+	}
+	if out.String("type") == PostTypeAttachment {
+		s.absoluteURLs(out)
+		out["caption"] = renderedField("caption", out["caption"])
+		out["description"] = renderedField("description", out["description"])
+		delete(out, "content")
+		delete(out, "excerpt")
+		delete(out, "parent")
+	}
+	out["meta"] = s.Store.renderRegisteredMeta(collectionOfPostType(out.String("type")), id)
+	return stripContext(out, context, postEditOnlyFields)
+}
+
+// absoluteURLs resolves the site-relative URLs of an attachment's files against the server's URL
+func (s *Server) absoluteURLs(attachment Object) {
+	absolute := func(u string) string {
+		if strings.HasPrefix(u, "/") {
+			return s.URL + u
+		}
+		return u
+	}
+	attachment["source_url"] = absolute(attachment.String("source_url"))
+	if guid, ok := attachment["guid"].(map[string]interface{}); ok {
+		guid["raw"] = absolute(Object(guid).String("raw"))
+		guid["rendered"] = absolute(Object(guid).String("rendered"))
+	}
+	details, _ := attachment["media_details"].(map[string]interface{})
+	sizes, _ := details["sizes"].(map[string]interface{})
+	for _, size := range sizes {
+		if sized, ok := size.(map[string]interface{}); ok {
+			sized["source_url"] = absolute(Object(sized).String("source_url"))
+		}
+	}
+}
+
+// renderRevision returns the revision in the shape WP-API v2 beta uses, with plain string fields; in the `edit`
+// context, the title, content and excerpt have their raw and rendered values like WP-API 4.7+
+func (s *Server) renderRevision(revision Object, context string) Object {
+	out := Object{
+		"id":           revision.Int("id"),
+		"author":       fmt.Sprint(revision.Int("author")),
+		"date":         revision.String("date"),
+		"date_gmt":     revision.String("date_gmt"),
+		"guid":         fmt.Sprintf("%v/?p=%v", s.URL, revision.Int("id")),
+		"modified":     revision.String("modified"),
+		"modified_gmt": revision.String("modified_gmt"),
+		"parent":       revision.Int("parent"),
+		"slug":         revision.String("slug"),
+		"title":        revision.Rendered("title"),
+		"content":      revision.Rendered("content"),
+		"excerpt":      revision.Rendered("excerpt"),
+	}
+	if context == "edit" {
+		for _, field := range []string{"title", "content", "excerpt"} {
+			out[field] = revision[field]
+		}
+	}
+	return out
+}
+
+func (s *Server) servePosts(w http.ResponseWriter, req *request, postType string) {
+	switch req.method {
+	case "GET", "HEAD":
+		s.listPosts(w, req, postType)
+	case "POST":
+		if postType == PostTypeAttachment {
+			s.uploadMedia(w, req)
+			return
+		}
+		s.createPost(w, req, postType)
+	default:
+		writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+	}
+}
+
+func (s *Server) servePost(w http.ResponseWriter, req *request, postType string, segment string) {
+	post, ok := s.Store.posts[parseID(segment)]
+	if !ok || post.String("type") != postType {
+		writeInvalidID(w, "rest_post_invalid_id")
+		return
+	}
+	switch req.method {
+	case "GET", "HEAD":
+		s.getPost(w, req, post)
+	case "POST", "PUT", "PATCH":
+		s.updatePost(w, req, post)
+	case "DELETE":
+		s.deletePost(w, req, post)
+	default:
+		writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+	}
+}
+
+func (s *Server) listPosts(w http.ResponseWriter, req *request, postType string) {
+	if req.context() == "edit" && !s.checkPermission(w, req, editCapability(postType), "rest_forbidden_context") {
+		return
+	}
+	q, err := req.listQuery("date", "desc")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "rest_invalid_param", err.Error())
+		return
+	}
+
+	defaultStatus := "publish"
+	if postType == PostTypeAttachment {
+		defaultStatus = "inherit"
+	}
+	statuses := stringList(req.param("status", "post_status"))
+	if len(statuses) == 0 {
+		statuses = []string{defaultStatus}
+	}
+	for _, status := range statuses {
+		if status != defaultStatus && !s.checkPermission(w, req, editCapability(postType), "rest_forbidden_status") {
+			return
+		}
+	}
+
+	authors := req.intList("author")
+	parents := req.intList("parent")
+	slug := req.param("slug", "name")
+	categories := req.intList("categories")
+	tags := req.intList("tags")
+	metaKey := req.param("meta_key", "meta_key")
+	metaValue := req.param("meta_value", "meta_value")
+
+	var posts []Object
+	for _, post := range s.Store.posts {
+		if post.String("type") != postType {
+			continue
+		}
+		status := post.String("status")
+		if !containsString(statuses, status) && !(containsString(statuses, "any") && status != "trash") {
+			continue
+		}
+		if len(authors) > 0 && !containsInt(authors, post.Int("author")) {
+			continue
+		}
+		if len(parents) > 0 && !containsInt(parents, post.Int("parent")) && !containsInt(parents, post.Int("post")) {
+			continue
+		}
+		if slug != "" && post.String("slug") != slug {
+			continue
+		}
+		if len(categories) > 0 && !intersects(categories, post.Ints("categories")) {
+			continue
+		}
+		if len(tags) > 0 && !intersects(tags, post.Ints("tags")) {
+			continue
+		}
+		if metaKey != "" && !s.hasMeta(collectionOfPostType(postType), post.Int("id"), metaKey, metaValue) {
+			continue
+		}
+		posts = append(posts, post)
+	}
+	posts = q.filter(posts, "title", "content", "excerpt")
+	q.sort(posts)
+
+	var out []Object
+	for _, post := range posts {
+		out = append(out, s.renderPost(post, req.context()))
+	}
+	s.writeList(w, req, q, out)
+}
+
+func (s *Server) getPost(w http.ResponseWriter, req *request, post Object) {
+	if req.context() == "edit" && !s.checkPermission(w, req, editCapability(post.String("type")), "rest_forbidden_context") {
+		return
+	}
+	if !s.isPublic(post) && !s.checkPermission(w, req, editCapability(post.String("type")), "rest_forbidden") {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.renderPost(post, req.context()))
+}
+
+// applyPostFields copies the writable fields of the request body onto the post, validating them
+func (s *Server) applyPostFields(w http.ResponseWriter, req *request, post Object) bool {
+	postType := post.String("type")
+	for _, field := range postFields[postType] {
+		value, ok := req.body[field]
+		if !ok {
+			continue
+		}
+		switch field {
+		case "title", "content", "excerpt":
+			post[field] = renderedField(field, value)
+		case "status":
+			status := Object(req.body).String(field)
+			if postType == PostTypeAttachment {
+				if status != "inherit" && status != "private" && status != "trash" {
+					writeError(w, http.StatusBadRequest, "rest_invalid_param", "Invalid parameter(s): status")
+					return false
+				}
+			} else if !containsString(postStatuses, status) {
+				writeError(w, http.StatusBadRequest, "rest_invalid_param", "Invalid parameter(s): status")
+				return false
+			}
+			if status == "publish" && postType != PostTypeAttachment && !s.checkPermission(w, req, "publish_posts", "rest_cannot_publish") {
+				return false
+			}
+			post[field] = status
+		case "format":
+			format := Object(req.body).String(field)
+			if !containsString(postFormats, format) {
+				writeError(w, http.StatusBadRequest, "rest_invalid_param", "Invalid parameter(s): format")
+				return false
+			}
+			post[field] = format
+		case "comment_status", "ping_status":
+			status := Object(req.body).String(field)
+			if status != "open" && status != "closed" {
+				writeError(w, http.StatusBadRequest, "rest_invalid_param", fmt.Sprintf("Invalid parameter(s): %v", field))
+				return false
+			}
+			post[field] = status
+		case "caption", "description":
+			// stored raw, like post_excerpt and post_content; a string or a `{"raw": ...}` object is accepted
+			post[field] = renderedField(field, value)["raw"]
+		case "author", "parent", "featured_image", "menu_order", "post":
+			post[field] = toInt(value)
+		case "categories", "tags":
+			post[field] = Object(req.body).Ints(field)
+		case "sticky":
+			post[field] = value == true || value == "true" || value == "1"
+		default:
+			post[field] = Object(req.body).String(field)
+		}
+	}
+	return true
+}
+
+func (s *Server) createPost(w http.ResponseWriter, req *request, postType string) {
+	if !s.checkPermission(w, req, editCapability(postType), "rest_cannot_create") {
+		return
+	}
+	if req.body.Int("id") != 0 {
+		writeError(w, http.StatusBadRequest, "rest_post_exists", "Cannot create existing post.")
+		return
+	}
+	post := Object{"type": postType, "author": req.user.Int("id")}
+	if !s.applyPostFields(w, req, post) {
+		return
+	}
+	created := s.Store.insertPost(postType, post)
+	s.Store.setRegisteredMeta(collectionOfPostType(postType), created.Int("id"), req.body["meta"])
+	w.Header().Set("Location", fmt.Sprintf("%v/%v/%v", s.BaseAPIURL(), collectionOfPostType(postType), created.Int("id")))
+	writeJSON(w, http.StatusCreated, s.renderPost(created, "edit"))
+}
+
+func (s *Server) updatePost(w http.ResponseWriter, req *request, post Object) {
+	postType := post.String("type")
+	if !s.checkPermission(w, req, editCapability(postType), "rest_cannot_edit") {
+		return
+	}
+	updated := post.copy()
+	if !s.applyPostFields(w, req, updated) {
+		return
+	}
+	now := s.Store.now()
+	updated["modified"] = now
+	updated["modified_gmt"] = now
+	s.Store.posts[post.Int("id")] = updated
+	s.Store.setRegisteredMeta(collectionOfPostType(postType), post.Int("id"), req.body["meta"])
+
+	// save a revision whenever the revisioned fields change, like wp_save_post_revision()
+	if postType == PostTypePost || postType == PostTypePage {
+		changed := false
+		for _, field := range []string{"title", "content", "excerpt"} {
+			if post.Raw(field) != updated.Raw(field) {
+				changed = true
+			}
+		}
+		if changed {
+			s.Store.insertRevision(updated, req.user.Int("id"))
+		}
+	}
+	writeJSON(w, http.StatusOK, s.renderPost(updated, "edit"))
+}
+
+func (s *Server) deletePost(w http.ResponseWriter, req *request, post Object) {
+	postType := post.String("type")
+	if !s.checkPermission(w, req, editCapability(postType), "rest_cannot_delete") {
+		return
+	}
+	id := post.Int("id")
+	if !req.force() {
+		if postType == PostTypeAttachment && !s.Store.MediaTrash {
+			writeError(w, http.StatusNotImplemented, "rest_trash_not_supported", "The post does not support trashing.")
+			return
+		}
+		if post.String("status") == "trash" {
+			writeError(w, http.StatusGone, "rest_already_trashed", "The post has already been deleted.")
+			return
+		}
+		trashed := post.copy()
+		trashed["status"] = "trash"
+		s.Store.posts[id] = trashed
+		writeJSON(w, http.StatusOK, s.renderPost(trashed, "edit"))
+		return
+	}
+
+	s.Store.deletePost(id)
+	writeJSON(w, http.StatusOK, s.renderPost(post, "edit"))
+}
+
+// insertRevision saves a snapshot of the post as a new revision
+func (s *Store) insertRevision(post Object, author int) Object {
+	if author == 0 {
+		author = post.Int("author")
+	}
+	now := s.now()
+	return s.insertPost(PostTypeRevision, Object{
+		"parent":       post.Int("id"),
+		"author":       author,
+		"title":        post["title"],
+		"content":      post["content"],
+		"excerpt":      post["excerpt"],
+		"slug":         fmt.Sprintf("%v-revision-v1", post.Int("id")),
+		"date":         now,
+		"date_gmt":     now,
+		"modified":     now,
+		"modified_gmt": now,
+	})
+}
+
+// deletePost permanently deletes a post with its revisions, comments, meta and uploaded files
+func (s *Store) deletePost(id int) {
+	post := s.posts[id]
+	delete(s.posts, id)
+	for childID, child := range s.posts {
+		if child.String("type") == PostTypeRevision && child.Int("parent") == id {
+			delete(s.posts, childID)
+		}
+	}
+	for commentID, comment := range s.comments {
+		if comment.Int("post") == id {
+			delete(s.comments, commentID)
+		}
+	}
+	parentType := collectionOfPostType(post.String("type"))
+	for _, m := range s.metaOf(parentType, id) {
+		delete(s.meta, m.ID)
+	}
+	if post.String("type") == PostTypeAttachment {
+		for _, file := range attachmentFiles(post) {
+			delete(s.files, file)
+		}
+	}
+}
+
+// attachmentFiles returns the paths of the uploaded file of an attachment and its intermediate sizes
+func attachmentFiles(post Object) []string {
+	details, _ := post["media_details"].(map[string]interface{})
+	file := Object(details).String("file")
+	if file == "" {
+		return nil
+	}
+	files := []string{file}
+	sizes, _ := details["sizes"].(map[string]interface{})
+	for _, size := range sizes {
+		if sized, ok := size.(map[string]interface{}); ok {
+			files = append(files, path.Join(path.Dir(file), Object(sized).String("file")))
+		}
+	}
+	return files
+}
+
+var dispositionFilenameRegexp = regexp.MustCompile(`filename="?([^";]+)"?`)
+
+// uploadFilename returns the filename of a raw upload from its Content-Disposition header.
+// Both `attachment; filename="a.jpg"` and the bare `filename=a.jpg` are accepted, like WP-API does.
+func uploadFilename(header string) string {
+	if _, params, err := mime.ParseMediaType(header); err == nil && params["filename"] != "" {
+		return params["filename"]
+	}
+	if match := dispositionFilenameRegexp.FindStringSubmatch(header); match != nil {
+		return strings.TrimSpace(match[1])
+	}
+	return ""
+}
+
+// uploadMedia handles `POST /media`, either with the file as the raw request body (with a
+// Content-Disposition header), or as the `file` part of a multipart/form-data request
+func (s *Server) uploadMedia(w http.ResponseWriter, req *request) {
+	if !s.checkPermission(w, req, "upload_files", "rest_cannot_create") {
+		return
+	}
+
+	var filename, contentType string
+	var data []byte
+	mediaType, params, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		reader := multipart.NewReader(bytes.NewReader(req.rawBody), params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			value, _ := ioutil.ReadAll(part)
+			if part.FormName() == "file" {
+				filename = part.FileName()
+				contentType = part.Header.Get("Content-Type")
+				data = value
+				continue
+			}
+			req.body[part.FormName()] = string(value)
+		}
+		if data == nil {
+			writeError(w, http.StatusBadRequest, "rest_upload_no_data", "No data supplied.")
+			return
+		}
+	} else {
+		data = req.rawBody
+		contentType = req.Header.Get("Content-Type")
+		filename = uploadFilename(req.Header.Get("Content-Disposition"))
+		if len(data) == 0 {
+			writeError(w, http.StatusBadRequest, "rest_upload_no_data", "No data supplied.")
+			return
+		}
+		if filename == "" {
+			writeError(w, http.StatusBadRequest, "rest_upload_no_content_disposition", "No Content-Disposition supplied.")
+			return
+		}
+	}
+	if contentType == "" || contentType == "application/octet-stream" {
+		if byExtension := mime.TypeByExtension(path.Ext(filename)); byExtension != "" {
+			contentType = byExtension
+		} else {
+			contentType = http.DetectContentType(data)
+		}
+	}
+
+	attachment := s.Store.newAttachment(path.Base(filename), contentType, data)
+	attachment["author"] = req.user.Int("id")
+	attachment["type"] = PostTypeAttachment
+	if !s.applyPostFields(w, req, attachment) {
+		return
+	}
+	created := s.Store.insertPost(PostTypeAttachment, attachment)
+	w.Header().Set("Location", fmt.Sprintf("%v/media/%v", s.BaseAPIURL(), created.Int("id")))
+	writeJSON(w, http.StatusCreated, s.renderPost(created, "edit"))
+}
+
+// newAttachment stores an uploaded file (and its intermediate image sizes) and returns the attachment
+// object describing it. The attachment itself is not inserted.
+// URLs of the files are stored relative to the site, since the store does not know the server's URL.
+func (s *Store) newAttachment(filename string, contentType string, data []byte) Object {
+	dir := s.Now().UTC().Format("2006/01")
+	ext := path.Ext(filename)
+	base := strings.TrimSuffix(filename, ext)
+
+	// make the filename unique, like wp_unique_filename()
+	file := path.Join(dir, filename)
+	for i := 1; s.files[file] != nil; i++ {
+		file = path.Join(dir, fmt.Sprintf("%v-%v%v", base, i, ext))
+	}
+	s.files[file] = &File{ContentType: contentType, Data: data, Modified: s.Now()}
+
+	sourceURL := UploadsPath + file
+	details := map[string]interface{}{"file": file}
+	mediaType := "file"
+	if strings.HasPrefix(contentType, "image/") {
+		mediaType = "image"
+		if config, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+			details["width"] = config.Width
+			details["height"] = config.Height
+			details["sizes"] = s.resizeImage(file, contentType, data, config.Width, config.Height)
+		}
+		details["image_meta"] = map[string]interface{}{}
+	}
+
+	return Object{
+		"title":         strings.TrimSuffix(path.Base(file), ext),
+		"slug":          sanitizeTitle(strings.TrimSuffix(path.Base(file), ext)),
+		"guid":          sourceURL,
+		"media_type":    mediaType,
+		"mime_type":     contentType,
+		"source_url":    sourceURL,
+		"media_details": details,
+	}
+}
+
+// resizeImage registers the intermediate sizes of an uploaded image.
+// The fake does not resize images; every size serves the original data.
+func (s *Store) resizeImage(file string, contentType string, data []byte, width int, height int) map[string]interface{} {
+	ext := path.Ext(file)
+	base := strings.TrimSuffix(path.Base(file), ext)
+	sizes := map[string]interface{}{}
+	for _, size := range s.imageSizes {
+		boxWidth, boxHeight := size.width, size.height
+		if boxWidth == 0 {
+			boxWidth = width
+		}
+		if boxHeight == 0 {
+			boxHeight = height
+		}
+		if width <= boxWidth && height <= boxHeight {
+			continue
+		}
+		w, h := boxWidth, boxHeight
+		if !size.crop || size.width == 0 || size.height == 0 {
+			// fit within the bounding box, keeping the aspect ratio
+			if width*boxHeight > height*boxWidth {
+				h = height * boxWidth / width
+			} else {
+				w = width * boxHeight / height
+			}
+		}
+		sizedFile := fmt.Sprintf("%v-%vx%v%v", base, w, h, ext)
+		s.files[path.Join(path.Dir(file), sizedFile)] = &File{ContentType: contentType, Data: data, Modified: s.Now()}
+		sizes[size.name] = map[string]interface{}{
+			"file":       sizedFile,
+			"width":      w,
+			"height":     h,
+			"mime_type":  contentType,
+			"source_url": UploadsPath + path.Join(path.Dir(file), sizedFile),
+		}
+	}
+	sizes["full"] = map[string]interface{}{
+		"file":       path.Base(file),
+		"width":      width,
+		"height":     height,
+		"mime_type":  contentType,
+		"source_url": UploadsPath + file,
+	}
+	return sizes
+}
+
+func (s *Server) serveRevisions(w http.ResponseWriter, req *request, postType string, parentSegment string, rest []string) {
+	parent, ok := s.Store.posts[parseID(parentSegment)]
+	if !ok || parent.String("type") != postType {
+		writeInvalidID(w, "rest_post_invalid_parent")
+		return
+	}
+	if !s.checkPermission(w, req, editCapability(postType), "rest_cannot_read") {
+		return
+	}
+
+	if len(rest) == 0 {
+		if req.method != "GET" && req.method != "HEAD" {
+			writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+			return
+		}
+		var revisions []Object
+		for _, post := range s.Store.posts {
+			if post.String("type") == PostTypeRevision && post.Int("parent") == parent.Int("id") {
+				revisions = append(revisions, post)
+			}
+		}
+		q := &listQuery{order: "desc", orderBy: "date"}
+		q.sort(revisions)
+		out := []Object{}
+		for _, revision := range revisions {
+			out = append(out, s.renderRevision(revision, req.context()))
+		}
+		writeJSON(w, http.StatusOK, out)
+		return
+	}
+
+	revision, ok := s.Store.posts[parseID(rest[0])]
+	if len(rest) > 1 || !ok || revision.String("type") != PostTypeRevision || revision.Int("parent") != parent.Int("id") {
+		writeInvalidID(w, "rest_post_invalid_id")
+		return
+	}
+	switch req.method {
+	case "GET", "HEAD":
+		writeJSON(w, http.StatusOK, s.renderRevision(revision, req.context()))
+	case "DELETE":
+		// revisions are deleted permanently, and deleting one does not undo its changes to the parent
+		delete(s.Store.posts, revision.Int("id"))
+		writeJSON(w, http.StatusOK, true)
+	default:
+		writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+	}
+}
+
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func intersects(a []int, b []int) bool {
+	for _, v := range a {
+		if containsInt(b, v) {
+			return true
+		}
+	}
+	return false
+}
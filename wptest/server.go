@@ -0,0 +1,563 @@
+// Package wptest provides an in-memory fake of the WP-API (WordPress REST API) server, for testing code
+// that uses the go-wordpress client without a live WordPress installation.
+//
+//	store := wptest.NewStore()
+//	store.AddUser(wptest.Object{"username": "admin", "password": "secret", "email": "admin@example.com",
+//		"roles": []string{wptest.RoleAdministrator}})
+//	server := wptest.NewServer(store)
+//	defer server.Close()
+//
+//	client := wordpress.NewClient(&wordpress.Options{
+//		BaseAPIURL: server.BaseAPIURL(),
+//		Username:   "admin",
+//		Password:   "secret",
+//	})
+package wptest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// APIPath is the path of the `wp/v2` namespace on the fake server
+	APIPath = "/wp-json/wp/v2"
+
+	// UploadsPath is the path uploaded files are served under
+	UploadsPath = "/wp-content/uploads/"
+
+	DefaultPerPage = 10
+	MaxPerPage     = 100
+)
+
+// Server is a fake WP-API server backed by a Store
+type Server struct {
+	*httptest.Server
+	Store *Store
+}
+
+// NewServer starts a fake server serving the given store (see NewFixtureStore), or an empty store if it is nil.
+func NewServer(store *Store) *Server {
+	if store == nil {
+		store = NewStore()
+	}
+	s := &Server{Store: store}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// BaseAPIURL returns the URL to pass as `wordpress.Options.BaseAPIURL`
+func (s *Server) BaseAPIURL() string {
+	return s.URL + APIPath
+}
+
+// request is an incoming API request, with the method override, authentication and body resolved
+type request struct {
+	*http.Request
+	method   string
+	segments []string
+	params   url.Values
+	body     Object
+	rawBody  []byte
+	user     Object
+
+	// applicationPassword is the application password the user authenticated with, if any
+	applicationPassword Object
+}
+
+// ServeHTTP handles a request against the store.
+// Requests are served one at a time, so handlers can use the store without further locking.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(r.URL.Path, UploadsPath):
+		s.serveFile(w, r)
+		return
+	case r.URL.Path == "/" && r.URL.Query().Get("preview") == "true":
+		s.servePreview(w, r)
+		return
+	case r.URL.Path == "/wp-json" || r.URL.Path == "/wp-json/":
+		writeJSON(w, http.StatusOK, s.siteIndex(r.URL.Query()))
+		return
+	case r.URL.Path != APIPath && !strings.HasPrefix(r.URL.Path, APIPath+"/"):
+		writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+		return
+	}
+
+	req, err := s.parseRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "rest_invalid_json", err.Error())
+		return
+	}
+	if req.user == nil && hasBasicAuth(r) {
+		writeError(w, http.StatusUnauthorized, "invalid_username", "Invalid username or incorrect password.")
+		return
+	}
+	if req.method == "OPTIONS" {
+		s.serveOptions(w, req)
+		return
+	}
+	s.route(w, req)
+}
+
+func hasBasicAuth(r *http.Request) bool {
+	username, _, ok := r.BasicAuth()
+	return ok && username != ""
+}
+
+func (s *Server) parseRequest(r *http.Request) (*request, error) {
+	req := &request{
+		Request:  r,
+		method:   r.Method,
+		segments: splitPath(strings.TrimPrefix(r.URL.Path, APIPath)),
+		params:   r.URL.Query(),
+		body:     Object{},
+	}
+
+	// WP-API allows overriding the method with the `_method` param or the `X-HTTP-Method-Override` header.
+	// The go-wordpress client sends the header as `HTTP_X_HTTP_METHOD_OVERRIDE`.
+	for _, override := range []string{
+		req.params.Get("_method"),
+		r.Header.Get("X-HTTP-Method-Override"),
+		r.Header.Get("HTTP_X_HTTP_METHOD_OVERRIDE"),
+	} {
+		if override != "" {
+			req.method = strings.ToUpper(override)
+			break
+		}
+	}
+
+	if r.Body != nil {
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.rawBody = data
+	}
+	contentType := r.Header.Get("Content-Type")
+	switch {
+	case strings.HasPrefix(contentType, "application/json") && len(req.rawBody) > 0:
+		var body map[string]interface{}
+		if err := json.Unmarshal(req.rawBody, &body); err != nil {
+			return nil, err
+		}
+		req.body = Object(body)
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		form, err := url.ParseQuery(string(req.rawBody))
+		if err != nil {
+			return nil, err
+		}
+		for key := range form {
+			req.body[key] = form.Get(key)
+		}
+	}
+
+	req.user, req.applicationPassword = s.authenticate(r)
+	return req, nil
+}
+
+// authenticate returns the user matching the request's basic auth credentials, if any, and the application
+// password they authenticated with, if they did
+func (s *Server) authenticate(r *http.Request) (Object, Object) {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return nil, nil
+	}
+	for _, user := range s.Store.users {
+		if user.String("username") != username && user.String("email") != username {
+			continue
+		}
+		if user.String("password") == password {
+			return user, nil
+		}
+		if applicationPassword := s.Store.useApplicationPassword(user, password, r.RemoteAddr); applicationPassword != nil {
+			return user, applicationPassword
+		}
+	}
+	return nil, nil
+}
+
+func splitPath(path string) []string {
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// route dispatches an API request on its path segments
+func (s *Server) route(w http.ResponseWriter, req *request) {
+	seg := req.segments
+	if len(seg) == 0 {
+		writeJSON(w, http.StatusOK, s.namespaceIndex(req))
+		return
+	}
+
+	switch seg[0] {
+	case "posts", "pages", "media":
+		postType := postTypeOfCollection(seg[0])
+		switch {
+		case len(seg) == 1:
+			s.servePosts(w, req, postType)
+			return
+		case len(seg) == 2:
+			s.servePost(w, req, postType, seg[1])
+			return
+		case seg[2] == "meta":
+			s.serveMeta(w, req, seg[0], postType, seg[1], seg[3:])
+			return
+		case seg[2] == "revisions" && postType != PostTypeAttachment:
+			s.serveRevisions(w, req, postType, seg[1], seg[3:])
+			return
+		case seg[2] == "autosaves" && postType != PostTypeAttachment:
+			s.serveAutosaves(w, req, postType, seg[1], seg[3:])
+			return
+		case seg[2] == "terms" && postType == PostTypePost && len(seg) >= 4:
+			s.servePostTerms(w, req, seg[1], seg[3], seg[4:])
+			return
+		}
+	case "comments":
+		switch {
+		case len(seg) == 1:
+			s.serveComments(w, req)
+			return
+		case len(seg) == 2:
+			s.serveComment(w, req, seg[1])
+			return
+		case seg[2] == "meta":
+			s.serveCommentMeta(w, req, seg[1], seg[3:])
+			return
+		}
+	case "users":
+		switch {
+		case len(seg) == 1:
+			s.serveUsers(w, req)
+			return
+		case len(seg) == 2:
+			s.serveUser(w, req, seg[1])
+			return
+		case seg[2] == "meta":
+			s.serveUserMeta(w, req, seg[1], seg[3:])
+			return
+		case seg[2] == "application-passwords":
+			s.serveApplicationPasswords(w, req, seg[1], seg[3:])
+			return
+		}
+	case "terms":
+		switch {
+		case len(seg) == 2:
+			s.serveTerms(w, req, seg[1])
+			return
+		case len(seg) == 3:
+			s.serveTerm(w, req, seg[1], seg[2])
+			return
+		case len(seg) >= 4 && seg[3] == "meta":
+			s.serveTermMeta(w, req, seg[1], seg[2], seg[4:])
+			return
+		}
+	case "taxonomies":
+		s.serveTaxonomies(w, req, seg[1:])
+		return
+	case "types":
+		s.serveTypes(w, req, seg[1:])
+		return
+	case "statuses":
+		s.serveStatuses(w, req, seg[1:])
+		return
+	}
+	writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+}
+
+func (s *Server) serveFile(w http.ResponseWriter, r *http.Request) {
+	file, ok := s.Store.files[strings.TrimPrefix(r.URL.Path, UploadsPath)]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	// like a web server, honor range and conditional requests
+	w.Header().Set("Content-Type", file.ContentType)
+	http.ServeContent(w, r, "", file.Modified, bytes.NewReader(file.Data))
+}
+
+// GeneralError is the error shape returned by the server; it matches wordpress.GeneralError
+type GeneralError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Data    int    `json:"data"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "rest_internal_error", err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// writeError writes a WP-API error, which (as in WP-API v2 beta) is a list of errors
+func writeError(w http.ResponseWriter, status int, code string, message string) {
+	writeJSON(w, status, []GeneralError{{
+		Code:    code,
+		Message: message,
+		Data:    status,
+	}})
+}
+
+func writeInvalidID(w http.ResponseWriter, code string) {
+	writeError(w, http.StatusNotFound, code, "Invalid resource id.")
+}
+
+// parseID parses an ID path segment; invalid IDs resolve to 0, which is never a valid ID
+func parseID(segment string) int {
+	id, err := strconv.Atoi(segment)
+	if err != nil || id < 0 {
+		return 0
+	}
+	return id
+}
+
+// context returns the `context` param of the request, which defaults to `view`
+func (req *request) context() string {
+	if context := req.params.Get("context"); context != "" {
+		return context
+	}
+	return "view"
+}
+
+func (req *request) force() bool {
+	force := req.params.Get("force")
+	if force == "" {
+		force = req.body.String("force")
+	}
+	return force == "true" || force == "1"
+}
+
+// param returns a query param, falling back to its WP-API v2 beta `filter[...]` equivalent
+func (req *request) param(name string, filter string) string {
+	if value := req.params.Get(name); value != "" {
+		return value
+	}
+	if filter != "" {
+		return req.params.Get(fmt.Sprintf("filter[%v]", filter))
+	}
+	return ""
+}
+
+// intList parses a comma-separated (or repeated) list-of-IDs param, such as `include`
+func (req *request) intList(name string) []int {
+	var ids []int
+	values := append(req.params[name], req.params[name+"[]"]...)
+	for _, value := range values {
+		for _, s := range strings.Split(value, ",") {
+			if id, err := strconv.Atoi(strings.TrimSpace(s)); err == nil {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// listQuery holds the generic collection params supported by every list endpoint
+type listQuery struct {
+	page    int
+	perPage int
+	include []int
+	exclude []int
+	search  string
+	order   string
+	orderBy string
+}
+
+func (req *request) listQuery(defaultOrderBy string, defaultOrder string) (*listQuery, error) {
+	q := &listQuery{
+		page:    1,
+		perPage: DefaultPerPage,
+		include: req.intList("include"),
+		exclude: req.intList("exclude"),
+		search:  strings.ToLower(req.param("search", "s")),
+		order:   strings.ToLower(req.param("order", "order")),
+		orderBy: strings.ToLower(req.param("orderby", "orderby")),
+	}
+	if page := req.params.Get("page"); page != "" {
+		p, err := strconv.Atoi(page)
+		if err != nil || p < 1 {
+			return nil, fmt.Errorf("page must be a positive integer")
+		}
+		q.page = p
+	}
+	if perPage := req.param("per_page", "posts_per_page"); perPage != "" {
+		p, err := strconv.Atoi(perPage)
+		if err != nil || p < 1 || p > MaxPerPage {
+			return nil, fmt.Errorf("per_page must be between 1 (inclusive) and %v (inclusive)", MaxPerPage)
+		}
+		q.perPage = p
+	}
+	if q.order == "" {
+		q.order = defaultOrder
+	}
+	if q.order != "asc" && q.order != "desc" {
+		return nil, fmt.Errorf("order is not one of asc, desc")
+	}
+	if q.orderBy == "" {
+		q.orderBy = defaultOrderBy
+	}
+	return q, nil
+}
+
+// filter applies include/exclude/search to objects
+func (q *listQuery) filter(objects []Object, searchFields ...string) []Object {
+	var filtered []Object
+	for _, o := range objects {
+		if len(q.include) > 0 && !containsInt(q.include, o.Int("id")) {
+			continue
+		}
+		if containsInt(q.exclude, o.Int("id")) {
+			continue
+		}
+		if q.search != "" && !matchesSearch(o, q.search, searchFields) {
+			continue
+		}
+		filtered = append(filtered, o)
+	}
+	return filtered
+}
+
+func matchesSearch(o Object, search string, fields []string) bool {
+	for _, field := range fields {
+		value := o.String(field)
+		if _, ok := o[field].(map[string]interface{}); ok {
+			value = o.Raw(field)
+		}
+		if strings.Contains(strings.ToLower(value), search) {
+			return true
+		}
+	}
+	return false
+}
+
+// sort orders objects by the query's `orderby` field
+func (q *listQuery) sort(objects []Object) {
+	key := func(o Object) string {
+		switch q.orderBy {
+		case "id":
+			return fmt.Sprintf("%010d", o.Int("id"))
+		case "include":
+			return fmt.Sprintf("%010d", indexOfInt(q.include, o.Int("id")))
+		case "title":
+			return strings.ToLower(o.Raw("title"))
+		case "date", "modified":
+			return o.String(q.orderBy)
+		case "parent", "menu_order", "count":
+			return fmt.Sprintf("%010d", o.Int(q.orderBy))
+		default:
+			return strings.ToLower(o.String(q.orderBy))
+		}
+	}
+	// objects listed by `include` keep the order of the IDs in the param
+	desc := q.order == "desc" && q.orderBy != "include"
+	sort.SliceStable(objects, func(i, j int) bool {
+		ki, kj := key(objects[i]), key(objects[j])
+		if ki == kj {
+			// break ties by ID, newest first when descending
+			if desc {
+				return objects[i].Int("id") > objects[j].Int("id")
+			}
+			return objects[i].Int("id") < objects[j].Int("id")
+		}
+		if desc {
+			return ki > kj
+		}
+		return ki < kj
+	})
+}
+
+// writeList writes the requested page of objects, with WP-API's pagination headers
+func (s *Server) writeList(w http.ResponseWriter, req *request, q *listQuery, objects []Object) {
+	total := len(objects)
+	totalPages := int(math.Ceil(float64(total) / float64(q.perPage)))
+	if q.page > 1 && q.page > totalPages {
+		writeError(w, http.StatusBadRequest, "rest_post_invalid_page_number", "The page number requested is larger than the number of pages available.")
+		return
+	}
+
+	start := (q.page - 1) * q.perPage
+	end := start + q.perPage
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+	page := objects[start:end]
+	if page == nil {
+		page = []Object{}
+	}
+
+	w.Header().Set("X-WP-Total", strconv.Itoa(total))
+	w.Header().Set("X-WP-TotalPages", strconv.Itoa(totalPages))
+	var links []string
+	if q.page > 1 {
+		links = append(links, fmt.Sprintf(`<%v>; rel="prev"`, s.pageURL(req, q.page-1)))
+	}
+	if q.page < totalPages {
+		links = append(links, fmt.Sprintf(`<%v>; rel="next"`, s.pageURL(req, q.page+1)))
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) pageURL(req *request, page int) string {
+	params := url.Values{}
+	for key, values := range req.params {
+		params[key] = values
+	}
+	params.Set("page", strconv.Itoa(page))
+	return fmt.Sprintf("%v%v?%v", s.URL, req.URL.Path, params.Encode())
+}
+
+func containsInt(list []int, value int) bool {
+	return indexOfInt(list, value) >= 0
+}
+
+func indexOfInt(list []int, value int) int {
+	for i, v := range list {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// stripContext removes fields that are only returned in the `edit` context
+func stripContext(o Object, context string, editOnly []string) Object {
+	if context == "edit" {
+		return o
+	}
+	for _, field := range editOnly {
+		delete(o, field)
+	}
+	for _, value := range o {
+		if nested, ok := value.(map[string]interface{}); ok {
+			delete(nested, "raw")
+		}
+	}
+	return o
+}
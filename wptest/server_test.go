@@ -0,0 +1,252 @@
+package wptest_test
+
+import (
+	"github.com/sogko/go-wordpress"
+	"github.com/sogko/go-wordpress/wptest"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newFixtureStore returns a store seeded with the fixture export, found from the directory of this package
+func newFixtureStore(t *testing.T) *wptest.Store {
+	f, err := os.Open(filepath.Join("..", filepath.FromSlash(wptest.FixtureWXR)))
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	defer f.Close()
+	store, err := wptest.NewFixtureStore(f)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	return store
+}
+
+func newTestClient(server *wptest.Server) *wordpress.Client {
+	return wordpress.NewClient(&wordpress.Options{
+		BaseAPIURL: server.BaseAPIURL(),
+		Username:   wptest.DefaultUsername,
+		Password:   wptest.DefaultPassword,
+	})
+}
+
+func TestServer_Fixture(t *testing.T) {
+	server := wptest.NewServer(newFixtureStore(t))
+	defer server.Close()
+	wp := newTestClient(server)
+
+	post, resp, _, err := wp.Posts().Get(1, "context=edit")
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 OK, got %v", resp.Status)
+	}
+	if post.Title.Raw != "Hello world!" || post.Status != wordpress.PostStatusPublish {
+		t.Errorf("Unexpected fixture post: %v", post)
+	}
+
+	comments, _, _, _ := wp.Comments().List("post=1")
+	if len(comments) != 1 || comments[0].AuthorName != "Mr WordPress" {
+		t.Errorf("Expected the fixture comment, got %v", comments)
+	}
+
+	revisions, _, _, _ := post.Revisions().List(nil)
+	if len(revisions) != 1 {
+		t.Errorf("Expected one revision, got %v", len(revisions))
+	}
+
+	media, _, _, _ := wp.Media().List(nil)
+	if len(media) != 1 {
+		t.Fatalf("Expected one media item, got %v", len(media))
+	}
+	if !strings.HasPrefix(media[0].SourceURL, server.URL+wptest.UploadsPath) {
+		t.Errorf("Unexpected source URL: %v", media[0].SourceURL)
+	}
+	if media[0].MediaDetails.Sizes["thumbnail"].Width != 150 {
+		t.Errorf("Expected a 150px thumbnail, got %v", media[0].MediaDetails.Sizes["thumbnail"])
+	}
+	file, err := http.Get(media[0].SourceURL)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	file.Body.Close()
+	if file.StatusCode != http.StatusOK || file.Header.Get("Content-Type") != "image/jpeg" {
+		t.Errorf("Expected the uploaded file to be served, got %v %v", file.Status, file.Header.Get("Content-Type"))
+	}
+}
+
+func TestServer_Pagination(t *testing.T) {
+	store := wptest.NewStore()
+	for i := 0; i < 25; i++ {
+		store.AddPost(wptest.Object{"title": "Post", "status": "publish"})
+	}
+	server := wptest.NewServer(store)
+	defer server.Close()
+	wp := wordpress.NewClient(&wordpress.Options{BaseAPIURL: server.BaseAPIURL()})
+
+	posts, resp, _, err := wp.Posts().List("page=3")
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if len(posts) != 5 {
+		t.Errorf("Expected 5 posts on the last page, got %v", len(posts))
+	}
+	if resp.Header.Get("X-WP-Total") != "25" || resp.Header.Get("X-WP-TotalPages") != "3" {
+		t.Errorf("Unexpected pagination headers: %v", resp.Header)
+	}
+	if !strings.Contains(resp.Header.Get("Link"), `rel="prev"`) || strings.Contains(resp.Header.Get("Link"), `rel="next"`) {
+		t.Errorf("Unexpected Link header: %v", resp.Header.Get("Link"))
+	}
+
+	_, resp, _, err = wp.Posts().List("page=4")
+	if err == nil || resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 Bad Request for a page out of range, got %v", resp.Status)
+	}
+}
+
+func TestServer_Permissions(t *testing.T) {
+	store := wptest.NewStore()
+	store.AddUser(wptest.Object{"username": "sub", "password": "secret", "email": "sub@example.com"})
+	server := wptest.NewServer(store)
+	defer server.Close()
+
+	anonymous := wordpress.NewClient(&wordpress.Options{BaseAPIURL: server.BaseAPIURL()})
+	post := wordpress.Post{Title: wordpress.Title{Raw: "Anonymous"}}
+	_, resp, _, _ := anonymous.Posts().Create(&post)
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 Unauthorized, got %v", resp.Status)
+	}
+
+	subscriber := wordpress.NewClient(&wordpress.Options{
+		BaseAPIURL: server.BaseAPIURL(),
+		Username:   "sub",
+		Password:   "secret",
+	})
+	_, resp, body, _ := subscriber.Posts().Create(&post)
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected 403 Forbidden, got %v", resp.Status)
+	}
+	serverErrors, err := wordpress.UnmarshallServerError(body)
+	if err != nil || len(serverErrors) != 1 || serverErrors[0].Code != "rest_cannot_create" {
+		t.Errorf("Expected rest_cannot_create error, got %s", body)
+	}
+
+	wrongPassword := wordpress.NewClient(&wordpress.Options{
+		BaseAPIURL: server.BaseAPIURL(),
+		Username:   "sub",
+		Password:   "wrong",
+	})
+	_, resp, _, _ = wrongPassword.Users().Me(nil)
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 Unauthorized, got %v", resp.Status)
+	}
+}
+
+func TestServer_Terms(t *testing.T) {
+	server := wptest.NewServer(newFixtureStore(t))
+	defer server.Close()
+	wp := newTestClient(server)
+
+	tag, resp, _, err := wp.Terms().Tag().Create(&wordpress.Term{Name: "Go"})
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if resp.StatusCode != http.StatusCreated || tag.Slug != "go" || tag.Taxonomy != "post_tag" {
+		t.Errorf("Unexpected created tag: %v %v", resp.Status, tag)
+	}
+	_, resp, _, _ = wp.Terms().Tag().Create(&wordpress.Term{Name: "Go"})
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Expected 500 for a duplicate term, got %v", resp.Status)
+	}
+
+	_, resp, _, _ = wp.Posts().Entity(1).Terms().Tag().Create(tag.ID)
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("Expected 201 Created, got %v", resp.Status)
+	}
+	terms, _, _, _ := wp.Posts().Entity(1).Terms().Tag().List(nil)
+	if len(terms) != 1 || terms[0].ID != tag.ID {
+		t.Errorf("Expected the post to have the tag, got %v", terms)
+	}
+	posts, _, _, _ := wp.Posts().List("tags=99")
+	if len(posts) != 0 {
+		t.Errorf("Expected no post tagged with an unknown tag, got %v", len(posts))
+	}
+
+	_, resp, _, _ = wp.Terms().Tag().Delete(tag.ID, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 OK, got %v", resp.Status)
+	}
+	terms, _, _, _ = wp.Posts().Entity(1).Terms().Tag().List(nil)
+	if len(terms) != 0 {
+		t.Errorf("Deleted tag should be unassigned from posts, got %v", terms)
+	}
+}
+
+func TestServer_Options(t *testing.T) {
+	server := wptest.NewServer(newFixtureStore(t))
+	defer server.Close()
+	wp := newTestClient(server)
+
+	route, _, _, err := wp.Route(server.BaseAPIURL() + "/posts/1")
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if route.Schema == nil || route.Schema.Title != "post" {
+		t.Fatalf("Expected the post schema, got %v", route.Schema)
+	}
+	if !route.Schema.Properties["id"].ReadOnly {
+		t.Errorf("Expected `id` to be read-only")
+	}
+
+	index, _, _, err := wp.Index("context=help")
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	if _, ok := index.Routes["/wp/v2/users/(?P<id>[\\d]+)"]; !ok {
+		t.Errorf("Expected the users item route in the index")
+	}
+}
+
+func TestServer_ImageSizes(t *testing.T) {
+	store := newFixtureStore(t)
+	store.AddImageSize("post-thumbnail", 825, 510, true)
+	store.AddImageSize("1536x1536", 1536, 1536, false)
+	server := wptest.NewServer(store)
+	defer server.Close()
+	wp := newTestClient(server)
+
+	data, err := ioutil.ReadFile("../test-data/test-media.jpg")
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+	media, _, _, err := wp.Media().Create(&wordpress.MediaUploadOptions{Filename: "a.jpg", ContentType: "image/jpeg", Data: data})
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+
+	// the test image is 1024x1024: sizes at least as large are not generated
+	sizes := media.MediaDetails.Sizes
+	for name, want := range map[string][2]int{"thumbnail": {150, 150}, "medium": {300, 300}, "medium_large": {768, 768}, "post-thumbnail": {825, 510}, "full": {1024, 1024}} {
+		if size, ok := sizes[name]; !ok || size.Width != want[0] || size.Height != want[1] {
+			t.Errorf("Expected a %vx%v %v size, got %v", want[0], want[1], name, size)
+		}
+	}
+	for _, name := range []string{"large", "1536x1536"} {
+		if _, ok := sizes[name]; ok {
+			t.Errorf("Expected no %v size", name)
+		}
+	}
+}
+
+func TestNewFixtureStore_Invalid(t *testing.T) {
+	if _, err := wptest.NewFixtureStore(strings.NewReader("<rss>")); err == nil {
+		t.Errorf("Expected an error loading an invalid export")
+	}
+	if _, err := wptest.NewFixtureStore(strings.NewReader(`<rss><channel></channel></rss>`)); err == nil {
+		t.Errorf("Expected an error loading an export without the fixture posts")
+	}
+}
@@ -0,0 +1,492 @@
+package wptest
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var postTypes = map[string]Object{
+	PostTypePost: {
+		"name":         "Posts",
+		"slug":         PostTypePost,
+		"description":  "",
+		"hierarchical": false,
+		"labels":       map[string]interface{}{"name": "Posts", "singular_name": "Post", "add_new": "Add New", "all_items": "All Posts"},
+	},
+	PostTypePage: {
+		"name":         "Pages",
+		"slug":         PostTypePage,
+		"description":  "",
+		"hierarchical": true,
+		"labels":       map[string]interface{}{"name": "Pages", "singular_name": "Page", "add_new": "Add New", "all_items": "All Pages"},
+	},
+	PostTypeAttachment: {
+		"name":         "Media",
+		"slug":         PostTypeAttachment,
+		"description":  "",
+		"hierarchical": false,
+		"labels":       map[string]interface{}{"name": "Media", "singular_name": "Media", "add_new": "Add New", "all_items": "Media"},
+	},
+}
+
+var postStatusObjects = map[string]Object{
+	"publish": {"name": "Published", "slug": "publish", "public": true, "private": false, "protected": false, "queryable": true, "show_in_list": true},
+	"future":  {"name": "Scheduled", "slug": "future", "public": false, "private": false, "protected": true, "queryable": false, "show_in_list": true},
+	"draft":   {"name": "Draft", "slug": "draft", "public": false, "private": false, "protected": true, "queryable": false, "show_in_list": true},
+	"pending": {"name": "Pending", "slug": "pending", "public": false, "private": false, "protected": true, "queryable": false, "show_in_list": true},
+	"private": {"name": "Private", "slug": "private", "public": false, "private": true, "protected": false, "queryable": false, "show_in_list": true},
+}
+
+func (s *Server) serveTypes(w http.ResponseWriter, req *request, rest []string) {
+	serveStatic(w, req, rest, postTypes, "rest_type_invalid", "Invalid resource.")
+}
+
+func (s *Server) serveStatuses(w http.ResponseWriter, req *request, rest []string) {
+	serveStatic(w, req, rest, postStatusObjects, "rest_status_invalid", "Invalid resource.")
+}
+
+// serveStatic serves a read-only map of objects keyed by slug, such as `/types` and `/types/{slug}`
+func serveStatic(w http.ResponseWriter, req *request, rest []string, objects map[string]Object, code string, message string) {
+	if req.method != "GET" && req.method != "HEAD" {
+		writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+		return
+	}
+	switch len(rest) {
+	case 0:
+		writeJSON(w, http.StatusOK, objects)
+		return
+	case 1:
+		if o, ok := objects[rest[0]]; ok {
+			writeJSON(w, http.StatusOK, o)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, code, message)
+}
+
+// routeDef describes a route of the fake server, in the shape of the API index
+type routeDef struct {
+	path    string
+	methods []string
+	schema  string
+	args    map[string]interface{}
+}
+
+const (
+	idPattern     = `(?P<id>[\d]+)`
+	parentPattern = `(?P<parent_id>[\d]+)`
+)
+
+var (
+	readMethods   = []string{"GET"}
+	listMethods   = []string{"GET", "POST"}
+	entityMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+)
+
+// routeDefs are the routes of the `wp/v2` namespace served by the fake server
+var routeDefs = buildRouteDefs()
+
+func buildRouteDefs() []routeDef {
+	routes := []routeDef{{path: "/wp/v2", methods: readMethods}}
+	for _, collection := range []string{"posts", "pages", "media"} {
+		schema := postTypeOfCollection(collection)
+		base := "/wp/v2/" + collection
+		routes = append(routes,
+			routeDef{path: base, methods: listMethods, schema: schema},
+			routeDef{path: base + "/" + idPattern, methods: entityMethods, schema: schema},
+		)
+		// attachments have meta routes on sites adding `custom-fields` support to them, as the fake does
+		routes = append(routes,
+			routeDef{path: base + "/" + parentPattern + "/meta", methods: listMethods, schema: "meta",
+				args: map[string]interface{}{"key": map[string]interface{}{"required": true}}},
+			routeDef{path: base + "/" + parentPattern + "/meta/" + idPattern, methods: entityMethods, schema: "meta"},
+		)
+		if collection == "media" {
+			continue
+		}
+		routes = append(routes,
+			routeDef{path: base + "/" + parentPattern + "/revisions", methods: readMethods, schema: "revision"},
+			routeDef{path: base + "/" + parentPattern + "/revisions/" + idPattern, methods: []string{"GET", "DELETE"}, schema: "revision"},
+			routeDef{path: base + "/" + parentPattern + "/autosaves", methods: listMethods, schema: "autosave"},
+			routeDef{path: base + "/" + parentPattern + "/autosaves/" + idPattern, methods: readMethods, schema: "autosave"},
+		)
+	}
+	for base := range taxonomies {
+		routes = append(routes,
+			routeDef{path: "/wp/v2/posts/(?P<post_id>[\\d]+)/terms/" + base, methods: readMethods, schema: "term"},
+			routeDef{path: "/wp/v2/posts/(?P<post_id>[\\d]+)/terms/" + base + "/(?P<term_id>[\\d]+)", methods: []string{"GET", "POST", "DELETE"}, schema: "term"},
+			routeDef{path: "/wp/v2/terms/" + base, methods: listMethods, schema: "term",
+				args: map[string]interface{}{"name": map[string]interface{}{"required": true}}},
+			routeDef{path: "/wp/v2/terms/" + base + "/" + idPattern, methods: entityMethods, schema: "term"},
+			routeDef{path: "/wp/v2/terms/" + base + "/" + parentPattern + "/meta", methods: listMethods, schema: "meta",
+				args: map[string]interface{}{"key": map[string]interface{}{"required": true}}},
+			routeDef{path: "/wp/v2/terms/" + base + "/" + parentPattern + "/meta/" + idPattern, methods: entityMethods, schema: "meta"},
+		)
+	}
+	for _, collection := range []string{"comments", "users"} {
+		routes = append(routes,
+			routeDef{path: "/wp/v2/" + collection + "/" + parentPattern + "/meta", methods: listMethods, schema: "meta",
+				args: map[string]interface{}{"key": map[string]interface{}{"required": true}}},
+			routeDef{path: "/wp/v2/" + collection + "/" + parentPattern + "/meta/" + idPattern, methods: entityMethods, schema: "meta"},
+		)
+	}
+	routes = append(routes,
+		routeDef{path: "/wp/v2/comments", methods: listMethods, schema: "comment"},
+		routeDef{path: "/wp/v2/comments/" + idPattern, methods: entityMethods, schema: "comment"},
+		routeDef{path: "/wp/v2/users", methods: listMethods, schema: "user",
+			args: map[string]interface{}{
+				"username": map[string]interface{}{"required": true},
+				"email":    map[string]interface{}{"required": true},
+				"password": map[string]interface{}{"required": true},
+			}},
+		routeDef{path: "/wp/v2/users/" + idPattern, methods: entityMethods, schema: "user"},
+		routeDef{path: "/wp/v2/users/me", methods: readMethods, schema: "user"},
+		routeDef{path: "/wp/v2/users/(?P<user_id>(?:[\\d]+|me))/application-passwords", methods: []string{"GET", "POST", "DELETE"},
+			schema: "application-password", args: map[string]interface{}{"name": map[string]interface{}{"required": true}}},
+		routeDef{path: "/wp/v2/users/(?P<user_id>(?:[\\d]+|me))/application-passwords/introspect", methods: readMethods,
+			schema: "application-password"},
+		routeDef{path: "/wp/v2/users/(?P<user_id>(?:[\\d]+|me))/application-passwords/(?P<uuid>[\\da-f\\-]+)", methods: entityMethods,
+			schema: "application-password"},
+		routeDef{path: "/wp/v2/taxonomies", methods: readMethods, schema: "taxonomy"},
+		routeDef{path: "/wp/v2/taxonomies/(?P<taxonomy>[\\w-]+)", methods: readMethods, schema: "taxonomy"},
+		routeDef{path: "/wp/v2/types", methods: readMethods, schema: "type"},
+		routeDef{path: "/wp/v2/types/(?P<type>[\\w-]+)", methods: readMethods, schema: "type"},
+		routeDef{path: "/wp/v2/statuses", methods: readMethods, schema: "status"},
+		routeDef{path: "/wp/v2/statuses/(?P<status>[\\w-]+)", methods: readMethods, schema: "status"},
+	)
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].path < routes[j].path
+	})
+	return routes
+}
+
+// describe returns the route in the shape WP-API describes it in the index and on `OPTIONS` requests
+func (def routeDef) describe(withSchema bool) Object {
+	var endpoints []interface{}
+	for _, method := range def.methods {
+		switch method {
+		case "GET":
+			args := map[string]interface{}{
+				"context": map[string]interface{}{"type": "string", "enum": []string{"view", "edit"}, "default": "view"},
+			}
+			if !strings.HasSuffix(def.path, ")") && def.path != "/wp/v2" {
+				args["page"] = map[string]interface{}{"type": "integer", "default": 1}
+				args["per_page"] = map[string]interface{}{"type": "integer", "default": DefaultPerPage}
+				args["search"] = map[string]interface{}{"type": "string"}
+			}
+			endpoints = append(endpoints, map[string]interface{}{"methods": []string{"GET"}, "args": args})
+		case "POST":
+			methods := []string{"POST"}
+			if containsString(def.methods, "PUT") {
+				methods = []string{"POST", "PUT", "PATCH"}
+			}
+			args := def.args
+			if args == nil {
+				args = map[string]interface{}{}
+			}
+			endpoints = append(endpoints, map[string]interface{}{"methods": methods, "args": args})
+		case "DELETE":
+			endpoints = append(endpoints, map[string]interface{}{
+				"methods": []string{"DELETE"},
+				"args":    map[string]interface{}{"force": map[string]interface{}{"type": "boolean", "default": false}},
+			})
+		}
+	}
+	route := Object{
+		"namespace": "wp/v2",
+		"methods":   def.methods,
+		"endpoints": endpoints,
+	}
+	if withSchema && def.schema != "" {
+		route["schema"] = schemas[def.schema]
+	}
+	return route
+}
+
+// routeIndex returns the routes keyed by path; schemas are only included for `context=help`
+func routeIndex(params url.Values) map[string]interface{} {
+	withSchema := params.Get("context") == "help"
+	routes := map[string]interface{}{}
+	for _, def := range routeDefs {
+		routes[def.path] = def.describe(withSchema)
+	}
+	return routes
+}
+
+// siteIndex is served at `/wp-json`
+func (s *Server) siteIndex(params url.Values) Object {
+	return Object{
+		"name":        "go-wordpress",
+		"description": "Just another WordPress site",
+		"url":         s.URL,
+		"namespaces":  []string{"wp/v2"},
+		"routes":      routeIndex(params),
+	}
+}
+
+func (s *Server) namespaceIndex(req *request) Object {
+	return Object{
+		"namespace": "wp/v2",
+		"routes":    routeIndex(req.params),
+	}
+}
+
+// serveOptions describes the route matching the request path, including its schema
+func (s *Server) serveOptions(w http.ResponseWriter, req *request) {
+	path := strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, "/wp-json"), "/")
+	for _, def := range routeDefs {
+		if matched, _ := regexp.MatchString("^"+def.path+"$", path); matched {
+			writeJSON(w, http.StatusOK, def.describe(true))
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+}
+
+func property(jsonType interface{}, extra ...interface{}) map[string]interface{} {
+	p := map[string]interface{}{"type": jsonType}
+	for i := 0; i+1 < len(extra); i += 2 {
+		p[extra[i].(string)] = extra[i+1]
+	}
+	return p
+}
+
+func renderedProperty(readOnlyRaw bool) map[string]interface{} {
+	return property("object", "properties", map[string]interface{}{
+		"raw":      property("string", "readonly", readOnlyRaw),
+		"rendered": property("string", "readonly", true),
+	})
+}
+
+// schemas are the item schemas of the routes, trimmed down to their types, formats and enums
+var schemas = map[string]Object{
+	PostTypePost:       postSchema(PostTypePost),
+	PostTypePage:       postSchema(PostTypePage),
+	PostTypeAttachment: postSchema(PostTypeAttachment),
+	"revision": {
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"title":   "revision",
+		"type":    "object",
+		"properties": map[string]interface{}{
+			"id":           property("integer", "readonly", true),
+			"author":       property("string", "readonly", true),
+			"date":         property("string", "format", "date-time", "readonly", true),
+			"date_gmt":     property("string", "format", "date-time", "readonly", true),
+			"guid":         property("string", "readonly", true),
+			"modified":     property("string", "format", "date-time", "readonly", true),
+			"modified_gmt": property("string", "format", "date-time", "readonly", true),
+			"parent":       property("integer", "readonly", true),
+			"slug":         property("string", "readonly", true),
+			"title":        property("string", "readonly", true),
+			"content":      property("string", "readonly", true),
+			"excerpt":      property("string", "readonly", true),
+		},
+	},
+	"application-password": {
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"title":   "application-password",
+		"type":    "object",
+		"properties": map[string]interface{}{
+			"uuid":      property("string", "format", "uuid", "readonly", true),
+			"app_id":    property("string", "format", "uuid"),
+			"name":      property("string", "required", true),
+			"password":  property("string", "readonly", true),
+			"created":   property("string", "format", "date-time", "readonly", true),
+			"last_used": property([]string{"string", "null"}, "format", "date-time", "readonly", true),
+			"last_ip":   property([]string{"string", "null"}, "format", "ip", "readonly", true),
+		},
+	},
+	"autosave": {
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"title":   "autosave",
+		"type":    "object",
+		"properties": map[string]interface{}{
+			"id":           property("integer", "readonly", true),
+			"author":       property("integer", "readonly", true),
+			"date":         property("string", "format", "date-time", "readonly", true),
+			"date_gmt":     property("string", "format", "date-time", "readonly", true),
+			"guid":         renderedProperty(true),
+			"modified":     property("string", "format", "date-time", "readonly", true),
+			"modified_gmt": property("string", "format", "date-time", "readonly", true),
+			"parent":       property("integer", "readonly", true),
+			"slug":         property("string", "readonly", true),
+			"title":        renderedProperty(false),
+			"content":      renderedProperty(false),
+			"excerpt":      renderedProperty(false),
+			"preview_link": property("string", "format", "uri", "readonly", true),
+		},
+	},
+	"meta": {
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"title":   "meta",
+		"type":    "object",
+		"properties": map[string]interface{}{
+			"id":    property("integer", "readonly", true),
+			"key":   property("string"),
+			"value": property("string"),
+		},
+	},
+	"comment": {
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"title":   "comment",
+		"type":    "object",
+		"properties": map[string]interface{}{
+			"id":                property("integer", "readonly", true),
+			"author":            property("integer"),
+			"author_email":      property("string", "format", "email"),
+			"author_ip":         property("string", "format", "ip"),
+			"author_name":       property("string"),
+			"author_url":        property("string", "format", "uri"),
+			"author_user_agent": property("string"),
+			"content":           renderedProperty(false),
+			"date":              property("string", "format", "date-time"),
+			"date_gmt":          property("string", "format", "date-time"),
+			"karma":             property("integer"),
+			"link":              property("string", "format", "uri", "readonly", true),
+			"meta":              property("object"),
+			"parent":            property("integer"),
+			"post":              property("integer"),
+			"status":            property("string", "enum", []string{CommentStatusApproved, CommentStatusHold, CommentStatusSpam, CommentStatusTrash, "approve", "unapproved", "0", "1"}),
+			"type":              property("string"),
+		},
+	},
+	"user": {
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"title":   "user",
+		"type":    "object",
+		"properties": map[string]interface{}{
+			"id":                 property("integer", "readonly", true),
+			"avatar_urls":        property("object", "readonly", true),
+			"capabilities":       property("object", "readonly", true),
+			"description":        property("string"),
+			"email":              property("string", "format", "email"),
+			"extra_capabilities": property("object", "readonly", true),
+			"first_name":         property("string"),
+			"last_name":          property("string"),
+			"link":               property("string", "format", "uri", "readonly", true),
+			"meta":               property("object"),
+			"name":               property("string"),
+			"nickname":           property("string"),
+			"password":           property("string"),
+			"registered_date":    property("string", "format", "date-time", "readonly", true),
+			"roles":              property("array", "items", property("string")),
+			"slug":               property("string"),
+			"url":                property("string", "format", "uri"),
+			"username":           property("string"),
+		},
+	},
+	"term": {
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"title":   "term",
+		"type":    "object",
+		"properties": map[string]interface{}{
+			"id":          property("integer", "readonly", true),
+			"count":       property("integer", "readonly", true),
+			"description": property("string"),
+			"link":        property("string", "format", "uri", "readonly", true),
+			"meta":        property("object"),
+			"name":        property("string"),
+			"parent":      property("integer"),
+			"slug":        property("string"),
+			"taxonomy":    property("string", "readonly", true),
+		},
+	},
+	"taxonomy": {
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"title":   "taxonomy",
+		"type":    "object",
+		"properties": map[string]interface{}{
+			"description":  property("string", "readonly", true),
+			"hierarchical": property("boolean", "readonly", true),
+			"labels":       property("object", "readonly", true),
+			"name":         property("string", "readonly", true),
+			"rest_base":    property("string", "readonly", true),
+			"show_cloud":   property("boolean", "readonly", true),
+			"slug":         property("string", "readonly", true),
+			"types":        property("array", "items", property("string"), "readonly", true),
+		},
+	},
+	"type": {
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"title":   "type",
+		"type":    "object",
+		"properties": map[string]interface{}{
+			"description":  property("string", "readonly", true),
+			"hierarchical": property("boolean", "readonly", true),
+			"labels":       property("object", "readonly", true),
+			"name":         property("string", "readonly", true),
+			"slug":         property("string", "readonly", true),
+		},
+	},
+	"status": {
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"title":   "status",
+		"type":    "object",
+		"properties": map[string]interface{}{
+			"name":         property("string", "readonly", true),
+			"private":      property("boolean", "readonly", true),
+			"protected":    property("boolean", "readonly", true),
+			"public":       property("boolean", "readonly", true),
+			"queryable":    property("boolean", "readonly", true),
+			"show_in_list": property("boolean", "readonly", true),
+			"slug":         property("string", "readonly", true),
+		},
+	},
+}
+
+func postSchema(postType string) Object {
+	properties := map[string]interface{}{
+		"id":             property("integer", "readonly", true),
+		"author":         property("integer"),
+		"comment_status": property("string", "enum", []string{"open", "closed"}),
+		"date":           property([]string{"string", "null"}, "format", "date-time"),
+		"date_gmt":       property([]string{"string", "null"}, "format", "date-time"),
+		"guid":           property("object", "readonly", true),
+		"link":           property("string", "format", "uri", "readonly", true),
+		"meta":           property("object"),
+		"modified":       property("string", "format", "date-time", "readonly", true),
+		"modified_gmt":   property("string", "format", "date-time", "readonly", true),
+		"ping_status":    property("string", "enum", []string{"open", "closed"}),
+		"slug":           property("string"),
+		"status":         property("string", "enum", postStatuses),
+		"title":          renderedProperty(false),
+		"type":           property("string", "readonly", true),
+	}
+	switch postType {
+	case PostTypeAttachment:
+		properties["status"] = property("string", "enum", []string{"inherit", "private", "trash"})
+		properties["alt_text"] = property("string")
+		properties["caption"] = renderedProperty(false)
+		properties["description"] = renderedProperty(false)
+		properties["media_type"] = property("string", "enum", []string{"image", "file"}, "readonly", true)
+		properties["mime_type"] = property("string", "readonly", true)
+		properties["media_details"] = property("object", "readonly", true)
+		properties["post"] = property("integer")
+		properties["source_url"] = property("string", "format", "uri", "readonly", true)
+	default:
+		properties["content"] = renderedProperty(false)
+		properties["excerpt"] = renderedProperty(false)
+		properties["featured_image"] = property("integer")
+		properties["password"] = property("string")
+	}
+	switch postType {
+	case PostTypePost:
+		properties["categories"] = property("array", "items", property("integer"))
+		properties["format"] = property("string", "enum", postFormats)
+		properties["sticky"] = property("boolean")
+		properties["tags"] = property("array", "items", property("integer"))
+	case PostTypePage:
+		properties["menu_order"] = property("integer")
+		properties["parent"] = property("integer")
+		properties["template"] = property("string")
+	}
+	return Object{
+		"$schema":    "http://json-schema.org/draft-04/schema#",
+		"title":      postType,
+		"type":       "object",
+		"properties": properties,
+	}
+}
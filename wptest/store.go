@@ -0,0 +1,625 @@
+package wptest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Sequences of IDs handed out by the store.
+	// Posts, pages, attachments and revisions share one sequence, like the `wp_posts` table.
+	sequencePosts    = "posts"
+	sequenceComments = "comments"
+	sequenceUsers    = "users"
+	sequenceTerms    = "terms"
+	sequenceMeta     = "meta"
+
+	PostTypePost       = "post"
+	PostTypePage       = "page"
+	PostTypeAttachment = "attachment"
+	PostTypeRevision   = "revision"
+
+	TaxonomyCategory = "category"
+	TaxonomyTag      = "post_tag"
+
+	// dateLayout is the layout WP-API uses for `date`, `modified` and friends
+	dateLayout = "2006-01-02T15:04:05"
+)
+
+// Object is a resource as stored by the fake server.
+// Objects are kept in the shape WP-API returns them in the `edit` context;
+// fields such as `title` hold a `{"raw": ..., "rendered": ...}` object.
+type Object map[string]interface{}
+
+func (o Object) Int(key string) int {
+	return toInt(o[key])
+}
+func (o Object) String(key string) string {
+	switch v := o[key].(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Raw returns the raw value of a `{"raw": ..., "rendered": ...}` field
+func (o Object) Raw(key string) string {
+	switch v := o[key].(type) {
+	case map[string]interface{}:
+		return Object(v).String("raw")
+	case Object:
+		return v.String("raw")
+	case string:
+		return v
+	}
+	return ""
+}
+
+// Rendered returns the rendered value of a `{"raw": ..., "rendered": ...}` field
+func (o Object) Rendered(key string) string {
+	switch v := o[key].(type) {
+	case map[string]interface{}:
+		return Object(v).String("rendered")
+	case Object:
+		return v.String("rendered")
+	case string:
+		return v
+	}
+	return ""
+}
+
+// Ints returns the value of a list-of-IDs field such as `categories`
+func (o Object) Ints(key string) []int {
+	var ids []int
+	switch v := o[key].(type) {
+	case []int:
+		ids = append(ids, v...)
+	case []interface{}:
+		for _, id := range v {
+			ids = append(ids, toInt(id))
+		}
+	}
+	return ids
+}
+
+// copy returns a deep copy of the object, so stored objects never leak to handlers that modify their output
+func (o Object) copy() Object {
+	return copyValue(o).(Object)
+}
+
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case Object:
+		c := Object{}
+		for key, nested := range v {
+			c[key] = copyValue(nested)
+		}
+		return c
+	case map[string]interface{}:
+		c := map[string]interface{}{}
+		for key, nested := range v {
+			c[key] = copyValue(nested)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i, nested := range v {
+			c[i] = copyValue(nested)
+		}
+		return c
+	case []int:
+		return append([]int{}, v...)
+	case []string:
+		return append([]string{}, v...)
+	}
+	return value
+}
+
+// Meta is a single meta entry of a post, page, user, comment or term
+type Meta struct {
+	ID         int
+	ParentType string
+	ParentID   int
+	Key        string
+	Value      interface{}
+}
+
+// File is an uploaded attachment, served under `/wp-content/uploads/`
+type File struct {
+	ContentType string
+	Data        []byte
+	Modified    time.Time
+}
+
+// Store is the in-memory data of the fake server.
+// Seed it with the Add* functions before (or while) serving requests.
+type Store struct {
+	mu sync.Mutex
+
+	sequences map[string]int
+	posts     map[int]Object
+	comments  map[int]Object
+	users     map[int]Object
+	terms     map[int]Object
+	meta      map[int]*Meta
+	files     map[string]*File
+
+	// applicationPasswords are keyed by UUID; they hold the `user` they belong to and their `password`
+	applicationPasswords map[string]Object
+
+	// registeredMeta are the meta keys shown in the `meta` object, by object type
+	registeredMeta map[string][]string
+
+	imageSizes []imageSize
+
+	// Now returns the current time, used to date new and modified objects.
+	Now func() time.Time
+
+	// MediaTrash lets attachments be moved to the trash, like `define( 'MEDIA_TRASH', true );`;
+	// otherwise they can only be deleted permanently.
+	MediaTrash bool
+}
+
+func NewStore() *Store {
+	return &Store{
+		sequences:  map[string]int{},
+		posts:      map[int]Object{},
+		comments:   map[int]Object{},
+		users:      map[int]Object{},
+		terms:      map[int]Object{},
+		meta:       map[int]*Meta{},
+		files:      map[string]*File{},
+		imageSizes: append([]imageSize(nil), defaultImageSizes...),
+		Now:        time.Now,
+
+		applicationPasswords: map[string]Object{},
+		registeredMeta:       map[string][]string{},
+	}
+}
+
+// nextID returns the next ID of the given sequence; seeded IDs are never returned, as reserveID moves the
+// sequence past them
+func (s *Store) nextID(sequence string) int {
+	s.sequences[sequence]++
+	return s.sequences[sequence]
+}
+
+// reserveID records an explicitly seeded ID, so generated IDs do not collide with it
+func (s *Store) reserveID(sequence string, id int) {
+	if id > s.sequences[sequence] {
+		s.sequences[sequence] = id
+	}
+}
+
+func (s *Store) now() string {
+	return s.Now().UTC().Format(dateLayout)
+}
+
+// AddUser seeds a user. A `password` field sets the password the user authenticates with;
+// `roles` defaults to `subscriber`.
+func (s *Store) AddUser(user Object) Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.insertUser(user)
+}
+
+// AddPost seeds a post, page, attachment or revision, depending on its `type` (default: `post`).
+// `title`, `content` and `excerpt` may be given either as plain strings or as `{"raw": ...}` objects.
+func (s *Store) AddPost(post Object) Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	postType := post.String("type")
+	if postType == "" {
+		postType = PostTypePost
+	}
+	return s.insertPost(postType, post)
+}
+
+// AddComment seeds a comment
+func (s *Store) AddComment(comment Object) Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.insertComment(comment)
+}
+
+// AddTerm seeds a term of the taxonomy given by its `taxonomy` field (default: `category`)
+func (s *Store) AddTerm(term Object) Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	taxonomy := term.String("taxonomy")
+	if taxonomy == "" {
+		taxonomy = TaxonomyCategory
+	}
+	return s.insertTerm(taxonomy, term)
+}
+
+// AddMeta seeds a meta entry of an object. parentType is the collection of the object, for eg. `posts`.
+func (s *Store) AddMeta(parentType string, parentID int, key string, value interface{}) *Meta {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.insertMeta(parentType, parentID, key, value)
+}
+
+// AddImageSize registers an intermediate image size generated for images uploaded afterwards,
+// like add_image_size() in a theme. A width or height of 0 leaves that dimension unbounded.
+func (s *Store) AddImageSize(name string, width int, height int, crop bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, size := range s.imageSizes {
+		if size.name == name {
+			s.imageSizes[i] = imageSize{name, width, height, crop}
+			return
+		}
+	}
+	s.imageSizes = append(s.imageSizes, imageSize{name, width, height, crop})
+}
+
+// AddFile seeds the contents of an uploaded file; path is relative to `/wp-content/uploads/`
+func (s *Store) AddFile(path string, contentType string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[strings.TrimLeft(path, "/")] = &File{
+		ContentType: contentType,
+		Data:        data,
+		Modified:    s.Now(),
+	}
+}
+
+// Post returns a copy of the stored post, page, attachment or revision
+func (s *Store) Post(id int) (Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	post, ok := s.posts[id]
+	if !ok {
+		return nil, false
+	}
+	return post.copy(), true
+}
+
+// Comment returns a copy of the stored comment
+func (s *Store) Comment(id int) (Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	comment, ok := s.comments[id]
+	if !ok {
+		return nil, false
+	}
+	return comment.copy(), true
+}
+
+// User returns a copy of the stored user
+func (s *Store) User(id int) (Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[id]
+	if !ok {
+		return nil, false
+	}
+	return user.copy(), true
+}
+
+// Term returns a copy of the stored term
+func (s *Store) Term(id int) (Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	term, ok := s.terms[id]
+	if !ok {
+		return nil, false
+	}
+	return term.copy(), true
+}
+
+// MetaOf returns the meta entries of an object, ordered by ID
+func (s *Store) MetaOf(parentType string, parentID int) []Meta {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var entries []Meta
+	for _, m := range s.metaOf(parentType, parentID) {
+		entries = append(entries, *m)
+	}
+	return entries
+}
+
+func (s *Store) insertUser(user Object) Object {
+	user = user.copy()
+	id := user.Int("id")
+	if id == 0 {
+		id = s.nextID(sequenceUsers)
+	} else {
+		s.reserveID(sequenceUsers, id)
+	}
+	user["id"] = id
+
+	username := user.String("username")
+	setDefault(user, "name", username)
+	setDefault(user, "nickname", username)
+	setDefault(user, "slug", sanitizeTitle(username))
+	setDefault(user, "registered_date", s.now()+"+00:00")
+	setDefault(user, "description", "")
+	setDefault(user, "first_name", "")
+	setDefault(user, "last_name", "")
+	setDefault(user, "url", "")
+	if len(stringList(user["roles"])) == 0 {
+		user["roles"] = []string{RoleSubscriber}
+	} else {
+		user["roles"] = stringList(user["roles"])
+	}
+	s.users[id] = user
+	return user.copy()
+}
+
+func (s *Store) insertPost(postType string, post Object) Object {
+	post = post.copy()
+	id := post.Int("id")
+	if id == 0 {
+		id = s.nextID(sequencePosts)
+	} else {
+		s.reserveID(sequencePosts, id)
+	}
+	now := s.now()
+
+	post["id"] = id
+	post["type"] = postType
+	for _, field := range []string{"title", "content", "excerpt", "guid"} {
+		post[field] = renderedField(field, post[field])
+	}
+	setDefault(post, "date", now)
+	setDefault(post, "date_gmt", post.String("date"))
+	setDefault(post, "modified", post.String("date"))
+	setDefault(post, "modified_gmt", post.String("modified"))
+	setDefault(post, "slug", sanitizeTitle(post.Raw("title")))
+	setDefault(post, "password", "")
+	setDefault(post, "author", 1)
+	post["author"] = post.Int("author")
+	post["parent"] = post.Int("parent")
+
+	switch postType {
+	case PostTypePost:
+		setDefault(post, "status", "draft")
+		setDefault(post, "format", "standard")
+		setDefault(post, "sticky", false)
+		setDefault(post, "comment_status", "open")
+		setDefault(post, "ping_status", "open")
+		setDefault(post, "featured_image", 0)
+		post["categories"] = post.Ints("categories")
+		post["tags"] = post.Ints("tags")
+	case PostTypePage:
+		setDefault(post, "status", "draft")
+		setDefault(post, "comment_status", "closed")
+		setDefault(post, "ping_status", "closed")
+		setDefault(post, "featured_image", 0)
+		setDefault(post, "menu_order", 0)
+		setDefault(post, "template", "")
+	case PostTypeAttachment:
+		setDefault(post, "status", "inherit")
+		setDefault(post, "comment_status", "open")
+		setDefault(post, "ping_status", "closed")
+		setDefault(post, "alt_text", "")
+		setDefault(post, "caption", "")
+		setDefault(post, "description", "")
+		setDefault(post, "media_type", "file")
+		setDefault(post, "mime_type", "")
+		setDefault(post, "source_url", "")
+		setDefault(post, "media_details", map[string]interface{}{})
+		post["post"] = post.Int("post")
+	case PostTypeRevision:
+		post["status"] = "inherit"
+	}
+	s.posts[id] = post
+	return post.copy()
+}
+
+func (s *Store) insertComment(comment Object) Object {
+	comment = comment.copy()
+	id := comment.Int("id")
+	if id == 0 {
+		id = s.nextID(sequenceComments)
+	} else {
+		s.reserveID(sequenceComments, id)
+	}
+	comment["id"] = id
+	comment["content"] = renderedField("content", comment["content"])
+	comment["post"] = comment.Int("post")
+	comment["parent"] = comment.Int("parent")
+	comment["author"] = comment.Int("author")
+	comment["karma"] = comment.Int("karma")
+	setDefault(comment, "date", s.now())
+	setDefault(comment, "date_gmt", comment.String("date"))
+	setDefault(comment, "status", CommentStatusApproved)
+	comment["status"] = normalizeCommentStatus(comment.String("status"))
+	setDefault(comment, "type", "comment")
+	for _, field := range []string{"author_name", "author_email", "author_url", "author_ip", "author_user_agent"} {
+		setDefault(comment, field, "")
+	}
+	s.comments[id] = comment
+	return comment.copy()
+}
+
+func (s *Store) insertTerm(taxonomy string, term Object) Object {
+	term = term.copy()
+	id := term.Int("id")
+	if id == 0 {
+		id = s.nextID(sequenceTerms)
+	} else {
+		s.reserveID(sequenceTerms, id)
+	}
+	term["id"] = id
+	term["taxonomy"] = taxonomy
+	term["parent"] = term.Int("parent")
+	setDefault(term, "slug", sanitizeTitle(term.String("name")))
+	setDefault(term, "description", "")
+	s.terms[id] = term
+	return term.copy()
+}
+
+func (s *Store) insertMeta(parentType string, parentID int, key string, value interface{}) *Meta {
+	m := &Meta{
+		ID:         s.nextID(sequenceMeta),
+		ParentType: parentType,
+		ParentID:   parentID,
+		Key:        key,
+		Value:      value,
+	}
+	s.meta[m.ID] = m
+	copied := *m
+	return &copied
+}
+
+func (s *Store) metaOf(parentType string, parentID int) []*Meta {
+	var entries []*Meta
+	for _, m := range s.meta {
+		if m.ParentType == parentType && m.ParentID == parentID {
+			entries = append(entries, m)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+	return entries
+}
+
+// termCount returns the number of posts a term is assigned to
+func (s *Store) termCount(term Object) int {
+	field := "categories"
+	if term.String("taxonomy") == TaxonomyTag {
+		field = "tags"
+	}
+	count := 0
+	for _, post := range s.posts {
+		for _, id := range post.Ints(field) {
+			if id == term.Int("id") {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// renderedField turns a plain string or a `{"raw": ...}` value into a `{"raw": ..., "rendered": ...}` object
+func renderedField(field string, value interface{}) map[string]interface{} {
+	var raw string
+	switch v := value.(type) {
+	case string:
+		raw = v
+	case map[string]interface{}:
+		raw = Object(v).String("raw")
+		if raw == "" {
+			raw = Object(v).String("rendered")
+		}
+	case Object:
+		raw = v.String("raw")
+		if raw == "" {
+			raw = v.String("rendered")
+		}
+	}
+	rendered := raw
+	if field == "content" || field == "excerpt" || field == "caption" || field == "description" {
+		rendered = autop(raw)
+	}
+	return map[string]interface{}{
+		"raw":      raw,
+		"rendered": rendered,
+	}
+}
+
+// autop is a much simplified wpautop(): blank lines separate paragraphs, line breaks become <br />
+func autop(text string) string {
+	text = strings.TrimSpace(strings.Replace(text, "\r\n", "\n", -1))
+	if text == "" {
+		return ""
+	}
+	var out []string
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		if isBlockElement(paragraph) {
+			out = append(out, paragraph)
+			continue
+		}
+		out = append(out, "<p>"+strings.Replace(paragraph, "\n", "<br />\n", -1)+"</p>")
+	}
+	return strings.Join(out, "\n") + "\n"
+}
+
+func isBlockElement(html string) bool {
+	for _, tag := range []string{"<p", "<h1", "<h2", "<h3", "<h4", "<h5", "<h6", "<div", "<ul", "<ol", "<blockquote", "<pre", "<table", "<figure"} {
+		if strings.HasPrefix(html, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeTitle is a much simplified sanitize_title(), used to derive slugs
+func sanitizeTitle(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+			dash = false
+		case !dash && b.Len() > 0:
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	return strings.TrimRight(b.String(), "-")
+}
+
+func setDefault(o Object, key string, value interface{}) {
+	if _, ok := o[key]; !ok {
+		o[key] = value
+	}
+}
+
+func toInt(value interface{}) int {
+	switch v := value.(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	case json.Number:
+		i, _ := v.Int64()
+		return int(i)
+	case string:
+		i, _ := strconv.Atoi(v)
+		return i
+	case bool:
+		if v {
+			return 1
+		}
+	}
+	return 0
+}
+
+func stringList(value interface{}) []string {
+	switch v := value.(type) {
+	case []string:
+		return append([]string{}, v...)
+	case []interface{}:
+		var list []string
+		for _, s := range v {
+			list = append(list, fmt.Sprint(s))
+		}
+		return list
+	case string:
+		if v == "" {
+			return nil
+		}
+		return strings.Split(v, ",")
+	}
+	return nil
+}
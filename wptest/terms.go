@@ -0,0 +1,359 @@
+package wptest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// taxonomies are the taxonomies registered by default, keyed by their `rest_base`
+var taxonomies = map[string]Object{
+	"category": {
+		"name":         "Categories",
+		"slug":         TaxonomyCategory,
+		"description":  "",
+		"hierarchical": true,
+		"show_cloud":   true,
+		"types":        []string{PostTypePost},
+		"rest_base":    "category",
+		"labels":       map[string]interface{}{"name": "Categories", "singular_name": "Category"},
+	},
+	"tag": {
+		"name":         "Tags",
+		"slug":         TaxonomyTag,
+		"description":  "",
+		"hierarchical": false,
+		"show_cloud":   true,
+		"types":        []string{PostTypePost},
+		"rest_base":    "tag",
+		"labels":       map[string]interface{}{"name": "Tags", "singular_name": "Tag"},
+	},
+}
+
+// taxonomyOfBase returns the taxonomy served under the given `rest_base`, for eg. `post_tag` for `tag`
+func taxonomyOfBase(base string) (string, bool) {
+	taxonomy, ok := taxonomies[base]
+	if !ok {
+		return "", false
+	}
+	return taxonomy.String("slug"), true
+}
+
+// postTermsField returns the post field holding the IDs of terms of the taxonomy
+func postTermsField(taxonomy string) string {
+	if taxonomy == TaxonomyTag {
+		return "tags"
+	}
+	return "categories"
+}
+
+// renderTerm returns the term as WP-API shows it
+func (s *Server) renderTerm(term Object) Object {
+	out := term.copy()
+	out["count"] = s.Store.termCount(term)
+	if term.String("taxonomy") == TaxonomyTag {
+		out["link"] = fmt.Sprintf("%v/tag/%v/", s.URL, term.String("slug"))
+		delete(out, "parent")
+	} else {
+		out["link"] = fmt.Sprintf("%v/category/%v/", s.URL, term.String("slug"))
+	}
+	out["meta"] = s.Store.renderRegisteredMeta("terms", term.Int("id"))
+	return out
+}
+
+func (s *Server) serveTerms(w http.ResponseWriter, req *request, base string) {
+	taxonomy, ok := taxonomyOfBase(base)
+	if !ok {
+		writeError(w, http.StatusNotFound, "rest_taxonomy_invalid", "Invalid taxonomy.")
+		return
+	}
+	switch req.method {
+	case "GET", "HEAD":
+		s.listTerms(w, req, taxonomy)
+	case "POST":
+		s.createTerm(w, req, taxonomy)
+	default:
+		writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+	}
+}
+
+func (s *Server) serveTerm(w http.ResponseWriter, req *request, base string, segment string) {
+	taxonomy, ok := taxonomyOfBase(base)
+	if !ok {
+		writeError(w, http.StatusNotFound, "rest_taxonomy_invalid", "Invalid taxonomy.")
+		return
+	}
+	term, ok := s.Store.terms[parseID(segment)]
+	if !ok || term.String("taxonomy") != taxonomy {
+		writeInvalidID(w, "rest_term_invalid")
+		return
+	}
+	switch req.method {
+	case "GET", "HEAD":
+		writeJSON(w, http.StatusOK, s.renderTerm(term))
+	case "POST", "PUT", "PATCH":
+		s.updateTerm(w, req, term)
+	case "DELETE":
+		s.deleteTerm(w, req, term)
+	default:
+		writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+	}
+}
+
+func (s *Server) listTerms(w http.ResponseWriter, req *request, taxonomy string) {
+	q, err := req.listQuery("name", "asc")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "rest_invalid_param", err.Error())
+		return
+	}
+	parents := req.intList("parent")
+	slug := req.param("slug", "")
+	hideEmpty := req.param("hide_empty", "") == "true" || req.param("hide_empty", "") == "1"
+
+	var terms []Object
+	for _, term := range s.Store.terms {
+		if term.String("taxonomy") != taxonomy {
+			continue
+		}
+		if len(parents) > 0 && !containsInt(parents, term.Int("parent")) {
+			continue
+		}
+		if slug != "" && term.String("slug") != slug {
+			continue
+		}
+		if hideEmpty && s.Store.termCount(term) == 0 {
+			continue
+		}
+		terms = append(terms, s.renderTerm(term))
+	}
+	terms = q.filter(terms, "name", "slug", "description")
+	q.sort(terms)
+	s.writeList(w, req, q, terms)
+}
+
+// applyTermFields copies the writable fields of the request body onto the term
+func (s *Server) applyTermFields(w http.ResponseWriter, req *request, term Object) bool {
+	for _, field := range []string{"name", "slug", "description", "parent"} {
+		value, ok := req.body[field]
+		if !ok {
+			continue
+		}
+		if field != "parent" {
+			term[field] = Object(req.body).String(field)
+			continue
+		}
+		if term.String("taxonomy") == TaxonomyTag {
+			writeError(w, http.StatusBadRequest, "rest_taxonomy_not_hierarchical", "Can not set term parent, taxonomy is not hierarchical.")
+			return false
+		}
+		parent := toInt(value)
+		if p, ok := s.Store.terms[parent]; parent != 0 && (!ok || p.String("taxonomy") != term.String("taxonomy")) {
+			writeError(w, http.StatusBadRequest, "rest_term_invalid", "Parent term doesn't exist.")
+			return false
+		}
+		term[field] = parent
+	}
+	return true
+}
+
+// termExists returns true if another term of the taxonomy has the same name or slug, with the same parent
+func (s *Store) termExists(term Object) bool {
+	for _, existing := range s.terms {
+		if existing.Int("id") == term.Int("id") || existing.String("taxonomy") != term.String("taxonomy") {
+			continue
+		}
+		if existing.Int("parent") != term.Int("parent") {
+			continue
+		}
+		if existing.String("name") == term.String("name") || existing.String("slug") == sanitizeTitle(term.String("slug")) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) createTerm(w http.ResponseWriter, req *request, taxonomy string) {
+	if !s.checkPermission(w, req, "manage_categories", "rest_cannot_create") {
+		return
+	}
+	if req.body.String("name") == "" {
+		writeError(w, http.StatusBadRequest, "rest_missing_callback_param", "Missing parameter(s): name")
+		return
+	}
+	term := Object{"taxonomy": taxonomy}
+	if !s.applyTermFields(w, req, term) {
+		return
+	}
+	if slug := term.String("slug"); slug != "" {
+		term["slug"] = sanitizeTitle(slug)
+	}
+	if s.Store.termExists(term) {
+		writeError(w, http.StatusInternalServerError, "term_exists", "A term with the name provided already exists with this parent.")
+		return
+	}
+	created := s.Store.insertTerm(taxonomy, term)
+	s.Store.setRegisteredMeta("terms", created.Int("id"), req.body["meta"])
+	w.Header().Set("Location", fmt.Sprintf("%v/terms/%v/%v", s.BaseAPIURL(), taxonomyBase(taxonomy), created.Int("id")))
+	writeJSON(w, http.StatusCreated, s.renderTerm(created))
+}
+
+func (s *Server) updateTerm(w http.ResponseWriter, req *request, term Object) {
+	if !s.checkPermission(w, req, "manage_categories", "rest_cannot_update") {
+		return
+	}
+	updated := term.copy()
+	if !s.applyTermFields(w, req, updated) {
+		return
+	}
+	updated["slug"] = sanitizeTitle(updated.String("slug"))
+	if s.Store.termExists(updated) {
+		writeError(w, http.StatusInternalServerError, "term_exists", "A term with the name provided already exists with this parent.")
+		return
+	}
+	s.Store.terms[term.Int("id")] = updated
+	s.Store.setRegisteredMeta("terms", term.Int("id"), req.body["meta"])
+	writeJSON(w, http.StatusOK, s.renderTerm(updated))
+}
+
+func (s *Server) deleteTerm(w http.ResponseWriter, req *request, term Object) {
+	if !s.checkPermission(w, req, "manage_categories", "rest_cannot_delete") {
+		return
+	}
+	rendered := s.renderTerm(term)
+	s.Store.deleteTerm(term.Int("id"))
+	writeJSON(w, http.StatusOK, rendered)
+}
+
+// deleteTerm deletes a term, unassigning it from posts and moving its children up to its parent
+func (s *Store) deleteTerm(id int) {
+	term := s.terms[id]
+	delete(s.terms, id)
+	field := postTermsField(term.String("taxonomy"))
+	for _, post := range s.posts {
+		var ids []int
+		for _, termID := range post.Ints(field) {
+			if termID != id {
+				ids = append(ids, termID)
+			}
+		}
+		if _, ok := post[field]; ok {
+			post[field] = ids
+		}
+	}
+	for _, child := range s.terms {
+		if child.Int("parent") == id {
+			child["parent"] = term.Int("parent")
+		}
+	}
+	for _, m := range s.metaOf("terms", id) {
+		delete(s.meta, m.ID)
+	}
+}
+
+func taxonomyBase(taxonomy string) string {
+	for base, t := range taxonomies {
+		if t.String("slug") == taxonomy {
+			return base
+		}
+	}
+	return taxonomy
+}
+
+// servePostTerms handles `/posts/{id}/terms/{taxonomy}` (list) and `/posts/{id}/terms/{taxonomy}/{term}`
+// (get, link with POST and unlink with DELETE)
+func (s *Server) servePostTerms(w http.ResponseWriter, req *request, postSegment string, base string, rest []string) {
+	post, ok := s.Store.posts[parseID(postSegment)]
+	if !ok || post.String("type") != PostTypePost {
+		writeInvalidID(w, "rest_post_invalid_id")
+		return
+	}
+	taxonomy, ok := taxonomyOfBase(base)
+	if !ok {
+		writeError(w, http.StatusNotFound, "rest_taxonomy_invalid", "Invalid taxonomy.")
+		return
+	}
+	field := postTermsField(taxonomy)
+
+	if len(rest) == 0 {
+		if req.method != "GET" && req.method != "HEAD" {
+			writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+			return
+		}
+		if !s.isPublic(post) && !s.checkPermission(w, req, "edit_posts", "rest_forbidden") {
+			return
+		}
+		ids := post.Ints(field)
+		sort.Ints(ids)
+		out := []Object{}
+		for _, id := range ids {
+			if term, ok := s.Store.terms[id]; ok {
+				out = append(out, s.renderTerm(term))
+			}
+		}
+		writeJSON(w, http.StatusOK, out)
+		return
+	}
+
+	term, ok := s.Store.terms[parseID(rest[0])]
+	if len(rest) > 1 || !ok || term.String("taxonomy") != taxonomy {
+		writeInvalidID(w, "rest_term_invalid")
+		return
+	}
+	assigned := containsInt(post.Ints(field), term.Int("id"))
+	switch req.method {
+	case "GET", "HEAD":
+		if !assigned {
+			writeError(w, http.StatusNotFound, "rest_post_not_in_term", "Invalid taxonomy for post ID.")
+			return
+		}
+		writeJSON(w, http.StatusOK, s.renderTerm(term))
+	case "POST", "PUT":
+		if !s.checkPermission(w, req, "edit_posts", "rest_forbidden") {
+			return
+		}
+		if !assigned {
+			post[field] = append(post.Ints(field), term.Int("id"))
+		}
+		writeJSON(w, http.StatusCreated, s.renderTerm(term))
+	case "DELETE":
+		if !s.checkPermission(w, req, "edit_posts", "rest_forbidden") {
+			return
+		}
+		if !req.force() {
+			writeError(w, http.StatusNotImplemented, "rest_trash_not_supported", "Terms do not support trashing.")
+			return
+		}
+		var ids []int
+		for _, id := range post.Ints(field) {
+			if id != term.Int("id") {
+				ids = append(ids, id)
+			}
+		}
+		post[field] = ids
+		writeJSON(w, http.StatusOK, s.renderTerm(term))
+	default:
+		writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+	}
+}
+
+func (s *Server) serveTaxonomies(w http.ResponseWriter, req *request, rest []string) {
+	if req.method != "GET" && req.method != "HEAD" {
+		writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+		return
+	}
+	if len(rest) == 0 {
+		out := map[string]Object{}
+		for _, taxonomy := range taxonomies {
+			out[taxonomy.String("slug")] = taxonomy
+		}
+		writeJSON(w, http.StatusOK, out)
+		return
+	}
+	for _, taxonomy := range taxonomies {
+		if len(rest) == 1 && taxonomy.String("slug") == rest[0] {
+			writeJSON(w, http.StatusOK, taxonomy)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "rest_taxonomy_invalid", "Invalid taxonomy.")
+}
@@ -0,0 +1,330 @@
+package wptest
+
+import (
+	"crypto/md5"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const (
+	RoleAdministrator = "administrator"
+	RoleEditor        = "editor"
+	RoleAuthor        = "author"
+	RoleContributor   = "contributor"
+	RoleSubscriber    = "subscriber"
+)
+
+// roleCapabilities are the capabilities granted by each default role, trimmed down to
+// the capabilities the fake server checks
+var roleCapabilities = map[string][]string{
+	RoleAdministrator: {
+		"read", "edit_posts", "publish_posts", "edit_others_posts", "delete_posts", "edit_pages", "publish_pages",
+		"edit_others_pages", "delete_pages", "upload_files", "moderate_comments", "manage_categories",
+		"list_users", "create_users", "edit_users", "promote_users", "delete_users", "manage_options",
+	},
+	RoleEditor: {
+		"read", "edit_posts", "publish_posts", "edit_others_posts", "delete_posts", "edit_pages", "publish_pages",
+		"edit_others_pages", "delete_pages", "upload_files", "moderate_comments", "manage_categories",
+	},
+	RoleAuthor:      {"read", "edit_posts", "publish_posts", "delete_posts", "upload_files"},
+	RoleContributor: {"read", "edit_posts", "delete_posts"},
+	RoleSubscriber:  {"read"},
+}
+
+// userEditOnlyFields are only returned in the `edit` context
+var userEditOnlyFields = []string{
+	"capabilities", "email", "extra_capabilities", "first_name", "last_name", "nickname", "registered_date",
+	"roles", "username",
+}
+
+// userFields are the fields of a user that can be written through the API
+var userFields = []string{
+	"username", "name", "first_name", "last_name", "email", "url", "description", "nickname", "slug", "roles",
+	"password",
+}
+
+// can returns true if the user has the capability through one of its roles
+func can(user Object, capability string) bool {
+	if user == nil {
+		return false
+	}
+	for _, role := range stringList(user["roles"]) {
+		if containsString(roleCapabilities[role], capability) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPermission writes an error and returns false unless the request's user has the capability.
+// Anonymous requests get `401 Unauthorized`, users lacking the capability get `403 Forbidden`.
+func (s *Server) checkPermission(w http.ResponseWriter, req *request, capability string, code string) bool {
+	if can(req.user, capability) {
+		return true
+	}
+	status := http.StatusForbidden
+	if req.user == nil {
+		status = http.StatusUnauthorized
+	}
+	writeError(w, status, code, "Sorry, you are not allowed to do that.")
+	return false
+}
+
+// renderUser returns the user as WP-API shows it in the given context
+func (s *Server) renderUser(user Object, context string) Object {
+	out := user.copy()
+	delete(out, "password")
+
+	hash := fmt.Sprintf("%x", md5.Sum([]byte(strings.ToLower(strings.TrimSpace(user.String("email"))))))
+	avatars := map[string]interface{}{}
+	for _, size := range []int{24, 48, 96} {
+		avatars[fmt.Sprint(size)] = fmt.Sprintf("https://secure.gravatar.com/avatar/%v?s=%v&d=mm&r=g", hash, size)
+	}
+	out["avatar_urls"] = avatars
+	out["link"] = fmt.Sprintf("%v/author/%v/", s.URL, user.String("slug"))
+
+	capabilities := map[string]interface{}{}
+	extra := map[string]interface{}{}
+	for _, role := range stringList(user["roles"]) {
+		extra[role] = true
+		for _, capability := range roleCapabilities[role] {
+			capabilities[capability] = true
+		}
+	}
+	out["capabilities"] = capabilities
+	out["extra_capabilities"] = extra
+	out["meta"] = s.Store.renderRegisteredMeta("users", user.Int("id"))
+	return stripContext(out, context, userEditOnlyFields)
+}
+
+func (s *Server) serveUsers(w http.ResponseWriter, req *request) {
+	switch req.method {
+	case "GET", "HEAD":
+		s.listUsers(w, req)
+	case "POST":
+		s.createUser(w, req)
+	default:
+		writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+	}
+}
+
+// userOfSegment returns the user of an ID or `me` path segment, or writes an error
+func (s *Server) userOfSegment(w http.ResponseWriter, req *request, segment string) (Object, bool) {
+	if segment == "me" {
+		if req.user == nil {
+			writeError(w, http.StatusUnauthorized, "rest_not_logged_in", "You are not currently logged in.")
+			return nil, false
+		}
+		return req.user, true
+	}
+	user, ok := s.Store.users[parseID(segment)]
+	if !ok {
+		writeInvalidID(w, "rest_user_invalid_id")
+	}
+	return user, ok
+}
+
+func (s *Server) serveUser(w http.ResponseWriter, req *request, segment string) {
+	user, ok := s.userOfSegment(w, req, segment)
+	if !ok {
+		return
+	}
+
+	switch req.method {
+	case "GET", "HEAD":
+		s.getUser(w, req, user)
+	case "POST", "PUT", "PATCH":
+		s.updateUser(w, req, user)
+	case "DELETE":
+		s.deleteUser(w, req, user)
+	default:
+		writeError(w, http.StatusNotFound, "rest_no_route", "No route was found matching the URL and request method")
+	}
+}
+
+func (s *Server) listUsers(w http.ResponseWriter, req *request) {
+	if req.context() == "edit" && !s.checkPermission(w, req, "list_users", "rest_forbidden_context") {
+		return
+	}
+	q, err := req.listQuery("name", "asc")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "rest_invalid_param", err.Error())
+		return
+	}
+	roles := stringList(req.param("roles", "role"))
+	slug := req.param("slug", "")
+
+	var users []Object
+	for _, user := range s.Store.users {
+		if len(roles) > 0 && !containsAnyString(stringList(user["roles"]), roles) {
+			continue
+		}
+		if slug != "" && user.String("slug") != slug {
+			continue
+		}
+		users = append(users, user)
+	}
+	users = q.filter(users, "name", "username", "email", "slug", "url")
+	q.sort(users)
+
+	var out []Object
+	for _, user := range users {
+		out = append(out, s.renderUser(user, req.context()))
+	}
+	s.writeList(w, req, q, out)
+}
+
+func (s *Server) getUser(w http.ResponseWriter, req *request, user Object) {
+	isSelf := req.user != nil && req.user.Int("id") == user.Int("id")
+	if req.context() == "edit" && !isSelf && !s.checkPermission(w, req, "list_users", "rest_user_cannot_view") {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.renderUser(user, req.context()))
+}
+
+// applyUserFields copies the writable fields of the request body onto the user
+func (s *Server) applyUserFields(w http.ResponseWriter, req *request, user Object) bool {
+	for _, field := range userFields {
+		value, ok := req.body[field]
+		if !ok {
+			continue
+		}
+		switch field {
+		case "roles":
+			roles := stringList(value)
+			for _, role := range roles {
+				if _, ok := roleCapabilities[role]; !ok {
+					writeError(w, http.StatusBadRequest, "rest_user_invalid_role", "Role is invalid.")
+					return false
+				}
+			}
+			if len(roles) > 0 && !s.checkPermission(w, req, "promote_users", "rest_cannot_edit_roles") {
+				return false
+			}
+			if len(roles) > 0 {
+				user[field] = roles
+			}
+		case "email":
+			email := Object(req.body).String(field)
+			if !strings.Contains(email, "@") {
+				writeError(w, http.StatusBadRequest, "rest_invalid_email", "Invalid email address.")
+				return false
+			}
+			user[field] = email
+		default:
+			user[field] = Object(req.body).String(field)
+		}
+	}
+	return true
+}
+
+func (s *Server) createUser(w http.ResponseWriter, req *request) {
+	if !s.checkPermission(w, req, "create_users", "rest_cannot_create_user") {
+		return
+	}
+	if req.body.Int("id") != 0 {
+		writeError(w, http.StatusBadRequest, "rest_user_exists", "Cannot create existing user.")
+		return
+	}
+	for _, field := range []string{"username", "email", "password"} {
+		if req.body.String(field) == "" {
+			writeError(w, http.StatusBadRequest, "rest_missing_callback_param", fmt.Sprintf("Missing parameter(s): %v", field))
+			return
+		}
+	}
+	for _, existing := range s.Store.users {
+		if existing.String("username") == req.body.String("username") {
+			writeError(w, http.StatusInternalServerError, "existing_user_login", "Sorry, that username already exists!")
+			return
+		}
+		if existing.String("email") == req.body.String("email") {
+			writeError(w, http.StatusInternalServerError, "existing_user_email", "Sorry, that email address is already used!")
+			return
+		}
+	}
+
+	user := Object{}
+	if !s.applyUserFields(w, req, user) {
+		return
+	}
+	created := s.Store.insertUser(user)
+	s.Store.setRegisteredMeta("users", created.Int("id"), req.body["meta"])
+	w.Header().Set("Location", fmt.Sprintf("%v/users/%v", s.BaseAPIURL(), created.Int("id")))
+	writeJSON(w, http.StatusCreated, s.renderUser(created, "edit"))
+}
+
+func (s *Server) updateUser(w http.ResponseWriter, req *request, user Object) {
+	isSelf := req.user != nil && req.user.Int("id") == user.Int("id")
+	if !isSelf && !s.checkPermission(w, req, "edit_users", "rest_cannot_edit") {
+		return
+	}
+	if username := req.body.String("username"); username != "" && username != user.String("username") {
+		writeError(w, http.StatusBadRequest, "rest_user_invalid_argument", "Username isn't editable")
+		return
+	}
+	updated := user.copy()
+	if !s.applyUserFields(w, req, updated) {
+		return
+	}
+	s.Store.users[user.Int("id")] = updated
+	s.Store.setRegisteredMeta("users", user.Int("id"), req.body["meta"])
+	writeJSON(w, http.StatusOK, s.renderUser(updated, "edit"))
+}
+
+func (s *Server) deleteUser(w http.ResponseWriter, req *request, user Object) {
+	if !s.checkPermission(w, req, "delete_users", "rest_user_cannot_delete") {
+		return
+	}
+	if !req.force() {
+		writeError(w, http.StatusNotImplemented, "rest_trash_not_supported", "Users do not support trashing.")
+		return
+	}
+	id := user.Int("id")
+	reassign := toInt(req.param("reassign", ""))
+	if reassign != 0 {
+		if _, ok := s.Store.users[reassign]; !ok || reassign == id {
+			writeError(w, http.StatusBadRequest, "rest_user_invalid_reassign", "Invalid user ID for reassignment.")
+			return
+		}
+	}
+	s.Store.deleteUser(id, reassign)
+	writeJSON(w, http.StatusOK, Object{"deleted": true, "previous": s.renderUser(user, "edit")})
+}
+
+// deleteUser deletes a user, reassigning its posts to another user, or deleting them if reassign is 0
+func (s *Store) deleteUser(id int, reassign int) {
+	delete(s.users, id)
+	var owned []int
+	for postID, post := range s.posts {
+		if post.Int("author") == id && post.String("type") != PostTypeRevision {
+			owned = append(owned, postID)
+		}
+	}
+	sort.Ints(owned)
+	for _, postID := range owned {
+		if reassign != 0 {
+			s.posts[postID]["author"] = reassign
+			continue
+		}
+		s.deletePost(postID)
+	}
+	for _, m := range s.metaOf("users", id) {
+		delete(s.meta, m.ID)
+	}
+	for uuid, applicationPassword := range s.applicationPasswords {
+		if applicationPassword.Int("user") == id {
+			delete(s.applicationPasswords, uuid)
+		}
+	}
+}
+
+func containsAnyString(list []string, values []string) bool {
+	for _, value := range values {
+		if containsString(list, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,403 @@
+package wptest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"strings"
+)
+
+// WXR (WordPress eXtended RSS) is the format of the WordPress exporter (Tools > Export).
+// Elements are matched by their local name, so exports of any WXR version (1.0 to 1.2) can be loaded.
+type wxrChannel struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	Description string        `xml:"description"`
+	Authors     []wxrAuthor   `xml:"author"`
+	Categories  []wxrCategory `xml:"category"`
+	Tags        []wxrTag      `xml:"tag"`
+	Terms       []wxrTerm     `xml:"term"`
+	Items       []wxrItem     `xml:"item"`
+}
+
+type wxrAuthor struct {
+	ID          int    `xml:"author_id"`
+	Login       string `xml:"author_login"`
+	Email       string `xml:"author_email"`
+	DisplayName string `xml:"author_display_name"`
+	FirstName   string `xml:"author_first_name"`
+	LastName    string `xml:"author_last_name"`
+}
+
+type wxrCategory struct {
+	ID          int    `xml:"term_id"`
+	Slug        string `xml:"category_nicename"`
+	Parent      string `xml:"category_parent"`
+	Name        string `xml:"cat_name"`
+	Description string `xml:"category_description"`
+}
+
+type wxrTag struct {
+	ID          int    `xml:"term_id"`
+	Slug        string `xml:"tag_slug"`
+	Name        string `xml:"tag_name"`
+	Description string `xml:"tag_description"`
+}
+
+type wxrTerm struct {
+	ID          int    `xml:"term_id"`
+	Taxonomy    string `xml:"term_taxonomy"`
+	Slug        string `xml:"term_slug"`
+	Parent      string `xml:"term_parent"`
+	Name        string `xml:"term_name"`
+	Description string `xml:"term_description"`
+}
+
+// wxrEncoded is a `content:encoded` or `excerpt:encoded` element, told apart by their namespace
+type wxrEncoded struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+type wxrItemCategory struct {
+	Domain   string `xml:"domain,attr"`
+	Nicename string `xml:"nicename,attr"`
+	Name     string `xml:",chardata"`
+}
+
+type wxrMeta struct {
+	Key   string `xml:"meta_key"`
+	Value string `xml:"meta_value"`
+}
+
+type wxrItem struct {
+	Title         string            `xml:"title"`
+	Link          string            `xml:"link"`
+	Creator       string            `xml:"creator"`
+	GUID          string            `xml:"guid"`
+	Encoded       []wxrEncoded      `xml:"encoded"`
+	ID            int               `xml:"post_id"`
+	Date          string            `xml:"post_date"`
+	DateGMT       string            `xml:"post_date_gmt"`
+	CommentStatus string            `xml:"comment_status"`
+	PingStatus    string            `xml:"ping_status"`
+	Slug          string            `xml:"post_name"`
+	Status        string            `xml:"status"`
+	Parent        int               `xml:"post_parent"`
+	MenuOrder     int               `xml:"menu_order"`
+	Type          string            `xml:"post_type"`
+	Password      string            `xml:"post_password"`
+	Sticky        int               `xml:"is_sticky"`
+	AttachmentURL string            `xml:"attachment_url"`
+	Categories    []wxrItemCategory `xml:"category"`
+	Meta          []wxrMeta         `xml:"postmeta"`
+	Comments      []wxrComment      `xml:"comment"`
+}
+
+type wxrComment struct {
+	ID          int       `xml:"comment_id"`
+	Author      string    `xml:"comment_author"`
+	AuthorEmail string    `xml:"comment_author_email"`
+	AuthorURL   string    `xml:"comment_author_url"`
+	AuthorIP    string    `xml:"comment_author_IP"`
+	Date        string    `xml:"comment_date"`
+	DateGMT     string    `xml:"comment_date_gmt"`
+	Content     string    `xml:"comment_content"`
+	Approved    string    `xml:"comment_approved"`
+	Type        string    `xml:"comment_type"`
+	Parent      int       `xml:"comment_parent"`
+	UserID      int       `xml:"comment_user_id"`
+	Meta        []wxrMeta `xml:"commentmeta"`
+}
+
+// LoadWXRFile returns a new store seeded with the content of a WXR export file
+func LoadWXRFile(filename string) (*Store, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	store := NewStore()
+	if err := store.LoadWXR(f); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// LoadWXR seeds the store with the authors, categories, tags, posts, pages, attachments, comments
+// and meta of a WXR export, keeping their IDs.
+//
+// Like the WordPress importer, authors are mapped to existing users with the same username.
+// Other authors are created with the `author` role and no password, so they cannot authenticate.
+// Uploaded files are not part of an export; attachments point to files that are not served
+// unless they are added with AddFile().
+func (s *Store) LoadWXR(r io.Reader) error {
+	var rss struct {
+		Channel wxrChannel `xml:"channel"`
+	}
+	if err := xml.NewDecoder(r).Decode(&rss); err != nil {
+		return fmt.Errorf("wptest: invalid WXR: %v", err)
+	}
+	channel := rss.Channel
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users := map[string]int{}
+	for _, user := range s.users {
+		users[user.String("username")] = user.Int("id")
+	}
+	for _, author := range channel.Authors {
+		if _, ok := users[author.Login]; ok {
+			continue
+		}
+		user := s.insertUser(Object{
+			"id":         s.freeID(s.users, author.ID),
+			"username":   author.Login,
+			"email":      author.Email,
+			"name":       author.DisplayName,
+			"first_name": author.FirstName,
+			"last_name":  author.LastName,
+			"roles":      []string{RoleAuthor},
+		})
+		users[author.Login] = user.Int("id")
+	}
+
+	s.loadWXRTerms(channel)
+	for _, item := range channel.Items {
+		if err := s.loadWXRItem(item, users); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// freeID returns id if it is not taken yet, or 0 to have the store assign one
+func (s *Store) freeID(objects map[int]Object, id int) int {
+	if _, taken := objects[id]; taken {
+		return 0
+	}
+	return id
+}
+
+// loadWXRTerms seeds the categories, tags and terms of other taxonomies declared by the channel
+func (s *Store) loadWXRTerms(channel wxrChannel) {
+	terms := []wxrTerm{}
+	for _, c := range channel.Categories {
+		terms = append(terms, wxrTerm{c.ID, TaxonomyCategory, c.Slug, c.Parent, c.Name, c.Description})
+	}
+	for _, t := range channel.Tags {
+		terms = append(terms, wxrTerm{t.ID, TaxonomyTag, t.Slug, "", t.Name, t.Description})
+	}
+	terms = append(terms, channel.Terms...)
+
+	for _, t := range terms {
+		if s.termBySlug(t.Taxonomy, t.Slug) != nil {
+			continue
+		}
+		s.insertTerm(t.Taxonomy, Object{
+			"id":          s.freeID(s.terms, t.ID),
+			"name":        t.Name,
+			"slug":        t.Slug,
+			"description": t.Description,
+		})
+	}
+	// parents are given by slug, and may be declared after their children
+	for _, t := range terms {
+		if t.Parent == "" {
+			continue
+		}
+		if parent := s.termBySlug(t.Taxonomy, t.Parent); parent != nil {
+			s.termBySlug(t.Taxonomy, t.Slug)["parent"] = parent.Int("id")
+		}
+	}
+}
+
+func (s *Store) termBySlug(taxonomy string, slug string) Object {
+	for _, term := range s.terms {
+		if term.String("taxonomy") == taxonomy && term.String("slug") == slug {
+			return term
+		}
+	}
+	return nil
+}
+
+func (s *Store) loadWXRItem(item wxrItem, users map[string]int) error {
+	switch item.Type {
+	case PostTypePost, PostTypePage, PostTypeAttachment:
+	default:
+		// menus, custom post types and the like are not served by the fake
+		return nil
+	}
+	if _, taken := s.posts[item.ID]; taken {
+		return fmt.Errorf("wptest: WXR item %v conflicts with an existing post", item.ID)
+	}
+
+	var content, excerpt string
+	for _, encoded := range item.Encoded {
+		if strings.Contains(encoded.XMLName.Space, "excerpt") {
+			excerpt = encoded.Value
+		} else {
+			content = encoded.Value
+		}
+	}
+	date := wxrDate(item.Date)
+	dateGMT := wxrDate(item.DateGMT)
+	if dateGMT == "" {
+		dateGMT = date
+	}
+
+	post := Object{
+		"id":             item.ID,
+		"title":          item.Title,
+		"content":        content,
+		"excerpt":        excerpt,
+		"guid":           item.GUID,
+		"date":           date,
+		"date_gmt":       dateGMT,
+		"modified":       date,
+		"modified_gmt":   dateGMT,
+		"slug":           item.Slug,
+		"status":         item.Status,
+		"password":       item.Password,
+		"author":         users[item.Creator],
+		"comment_status": item.CommentStatus,
+		"ping_status":    item.PingStatus,
+	}
+	switch item.Type {
+	case PostTypePost:
+		post["sticky"] = item.Sticky == 1
+		post["categories"] = s.wxrItemTerms(item, TaxonomyCategory)
+		post["tags"] = s.wxrItemTerms(item, TaxonomyTag)
+		for _, m := range item.Meta {
+			if m.Key == "_thumbnail_id" {
+				post["featured_image"] = toInt(m.Value)
+			}
+		}
+	case PostTypePage:
+		post["parent"] = item.Parent
+		post["menu_order"] = item.MenuOrder
+		for _, m := range item.Meta {
+			if m.Key == "_wp_page_template" && m.Value != "default" {
+				post["template"] = m.Value
+			}
+		}
+	case PostTypeAttachment:
+		// attachments keep their description in the content and their caption in the excerpt
+		delete(post, "content")
+		delete(post, "excerpt")
+		post["description"] = content
+		post["caption"] = excerpt
+		post["post"] = item.Parent
+		for key, value := range wxrAttachment(item) {
+			post[key] = value
+		}
+	}
+	// leave unset fields to the store's defaults
+	omitEmpty(post, "date", "date_gmt", "modified", "modified_gmt", "slug", "status", "comment_status", "ping_status")
+	s.insertPost(item.Type, post)
+
+	parentType := collectionOfPostType(item.Type)
+	for _, m := range item.Meta {
+		s.insertMeta(parentType, item.ID, m.Key, m.Value)
+	}
+	for _, c := range item.Comments {
+		status := normalizeCommentStatus(c.Approved)
+		if status == "" {
+			status = CommentStatusHold
+		}
+		commentType := c.Type
+		if commentType == "" {
+			commentType = "comment"
+		}
+		comment := Object{
+			"id":           s.freeID(s.comments, c.ID),
+			"post":         item.ID,
+			"parent":       c.Parent,
+			"author":       c.UserID,
+			"author_name":  c.Author,
+			"author_email": c.AuthorEmail,
+			"author_url":   c.AuthorURL,
+			"author_ip":    c.AuthorIP,
+			"date":         wxrDate(c.Date),
+			"date_gmt":     wxrDate(c.DateGMT),
+			"content":      c.Content,
+			"status":       status,
+			"type":         commentType,
+		}
+		omitEmpty(comment, "date", "date_gmt")
+		comment = s.insertComment(comment)
+		for _, m := range c.Meta {
+			s.insertMeta("comments", comment.Int("id"), m.Key, m.Value)
+		}
+	}
+	return nil
+}
+
+// wxrItemTerms returns the IDs of the item's terms of the taxonomy, adding terms not declared by the channel
+func (s *Store) wxrItemTerms(item wxrItem, taxonomy string) []int {
+	ids := []int{}
+	for _, c := range item.Categories {
+		if c.Domain != taxonomy && !(taxonomy == TaxonomyTag && c.Domain == "tag") {
+			continue
+		}
+		term := s.termBySlug(taxonomy, c.Nicename)
+		if term == nil {
+			term = s.insertTerm(taxonomy, Object{"name": c.Name, "slug": c.Nicename})
+		}
+		ids = append(ids, term.Int("id"))
+	}
+	return ids
+}
+
+// wxrAttachment returns the file fields of an attachment, from its `_wp_attached_file` meta or its URL
+func wxrAttachment(item wxrItem) Object {
+	file := ""
+	for _, m := range item.Meta {
+		if m.Key == "_wp_attached_file" {
+			file = m.Value
+		}
+	}
+	if file == "" {
+		if i := strings.Index(item.AttachmentURL, UploadsPath); i >= 0 {
+			file = item.AttachmentURL[i+len(UploadsPath):]
+		}
+	}
+	mimeType := mime.TypeByExtension(path.Ext(file))
+	if i := strings.Index(mimeType, ";"); i >= 0 {
+		mimeType = mimeType[:i]
+	}
+	mediaType := "file"
+	if strings.HasPrefix(mimeType, "image/") {
+		mediaType = "image"
+	}
+	sourceURL := item.AttachmentURL
+	if file != "" {
+		sourceURL = UploadsPath + file
+	}
+	return Object{
+		"media_type":    mediaType,
+		"mime_type":     mimeType,
+		"source_url":    sourceURL,
+		"media_details": map[string]interface{}{"file": file},
+	}
+}
+
+func omitEmpty(o Object, keys ...string) {
+	for _, key := range keys {
+		if o.String(key) == "" {
+			delete(o, key)
+		}
+	}
+}
+
+// wxrDate converts a WXR date (`2015-08-23 10:19:07`) to the WP-API format; unset dates are empty
+func wxrDate(date string) string {
+	if date == "" || strings.HasPrefix(date, "0000-00-00") {
+		return ""
+	}
+	return strings.Replace(date, " ", "T", 1)
+}
@@ -0,0 +1,155 @@
+package wptest_test
+
+import (
+	"github.com/sogko/go-wordpress/wptest"
+	"strings"
+	"testing"
+)
+
+func TestLoadWXRFile(t *testing.T) {
+	store, err := wptest.LoadWXRFile("../" + wptest.FixtureWXR)
+	if err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+
+	user, ok := store.User(1)
+	if !ok || user.String("username") != "go-wordpress" || user.String("email") != "sgk.sprm+go-wordpress@gmail.com" {
+		t.Errorf("Expected the exported author, got %v", user)
+	}
+
+	post, ok := store.Post(1)
+	if !ok {
+		t.Fatalf("Expected post 1")
+	}
+	if post.Raw("title") != "Hello world!" || post.String("status") != "publish" || post.String("type") != wptest.PostTypePost {
+		t.Errorf("Unexpected post: %v", post)
+	}
+	if post.String("date") != "2015-08-23T10:19:07" || post.Int("author") != 1 {
+		t.Errorf("Unexpected post date or author: %v %v", post.String("date"), post.Int("author"))
+	}
+	if categories := post.Ints("categories"); len(categories) != 1 || categories[0] != 1 {
+		t.Errorf("Expected post in category 1, got %v", categories)
+	}
+
+	comment, ok := store.Comment(1)
+	if !ok || comment.String("author_name") != "Mr WordPress" || comment.String("status") != wptest.CommentStatusApproved {
+		t.Errorf("Expected the exported comment, got %v", comment)
+	}
+
+	page, ok := store.Post(2)
+	if !ok || page.String("type") != wptest.PostTypePage || page.String("slug") != "sample-page" {
+		t.Errorf("Expected the sample page, got %v", page)
+	}
+	meta := store.MetaOf("pages", 2)
+	if len(meta) != 1 || meta[0].Key != "_wp_page_template" || meta[0].Value != "default" {
+		t.Errorf("Expected the page template meta, got %v", meta)
+	}
+
+	for _, id := range []int{4, 6} {
+		attachment, ok := store.Post(id)
+		if !ok || attachment.String("type") != wptest.PostTypeAttachment {
+			t.Fatalf("Expected attachment %v", id)
+		}
+		if !strings.HasPrefix(attachment.String("source_url"), wptest.UploadsPath+"2015/08/WP-API-develop") {
+			t.Errorf("Unexpected attachment source URL: %v", attachment.String("source_url"))
+		}
+	}
+}
+
+const testWXR = `<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0"
+	xmlns:excerpt="http://wordpress.org/export/1.2/excerpt/"
+	xmlns:content="http://purl.org/rss/1.0/modules/content/"
+	xmlns:dc="http://purl.org/dc/elements/1.1/"
+	xmlns:wp="http://wordpress.org/export/1.2/">
+<channel>
+	<wp:author><wp:author_id>1</wp:author_id><wp:author_login>admin</wp:author_login></wp:author>
+	<wp:author><wp:author_id>2</wp:author_id><wp:author_login>jane</wp:author_login><wp:author_email>jane@example.com</wp:author_email></wp:author>
+	<wp:category><wp:term_id>3</wp:term_id><wp:category_nicename>go</wp:category_nicename><wp:category_parent>programming</wp:category_parent><wp:cat_name><![CDATA[Go]]></wp:cat_name></wp:category>
+	<wp:category><wp:term_id>2</wp:term_id><wp:category_nicename>programming</wp:category_nicename><wp:cat_name><![CDATA[Programming]]></wp:cat_name></wp:category>
+	<wp:tag><wp:term_id>4</wp:term_id><wp:tag_slug>gophers</wp:tag_slug><wp:tag_name><![CDATA[Gophers]]></wp:tag_name></wp:tag>
+	<item>
+		<title>Draft</title>
+		<dc:creator><![CDATA[jane]]></dc:creator>
+		<content:encoded><![CDATA[Body]]></content:encoded>
+		<excerpt:encoded><![CDATA[Summary]]></excerpt:encoded>
+		<wp:post_id>10</wp:post_id>
+		<wp:post_date>2016-01-02 03:04:05</wp:post_date>
+		<wp:post_date_gmt>0000-00-00 00:00:00</wp:post_date_gmt>
+		<wp:status>draft</wp:status>
+		<wp:post_type>post</wp:post_type>
+		<wp:is_sticky>1</wp:is_sticky>
+		<category domain="category" nicename="go"><![CDATA[Go]]></category>
+		<category domain="post_tag" nicename="gophers"><![CDATA[Gophers]]></category>
+		<category domain="post_tag" nicename="undeclared"><![CDATA[Undeclared]]></category>
+		<wp:postmeta><wp:meta_key>rating</wp:meta_key><wp:meta_value><![CDATA[5]]></wp:meta_value></wp:postmeta>
+		<wp:comment>
+			<wp:comment_id>7</wp:comment_id>
+			<wp:comment_author><![CDATA[Spammer]]></wp:comment_author>
+			<wp:comment_content><![CDATA[Buy now]]></wp:comment_content>
+			<wp:comment_approved>spam</wp:comment_approved>
+		</wp:comment>
+	</item>
+	<item>
+		<title>Menu item</title>
+		<wp:post_id>11</wp:post_id>
+		<wp:post_type>nav_menu_item</wp:post_type>
+	</item>
+</channel>
+</rss>`
+
+func TestStoreLoadWXR(t *testing.T) {
+	store := wptest.NewStore()
+	store.AddUser(wptest.Object{"id": 1, "username": "admin", "password": "secret", "roles": []string{"administrator"}})
+	if err := store.LoadWXR(strings.NewReader(testWXR)); err != nil {
+		t.Fatalf("Should not return error: %v", err.Error())
+	}
+
+	// existing users are kept, other authors are added
+	admin, _ := store.User(1)
+	if admin.String("password") != "secret" {
+		t.Errorf("Existing user should not be replaced, got %v", admin)
+	}
+	jane, ok := store.User(2)
+	if !ok || jane.String("username") != "jane" {
+		t.Fatalf("Expected author jane to be added, got %v", jane)
+	}
+
+	post, ok := store.Post(10)
+	if !ok {
+		t.Fatalf("Expected post 10")
+	}
+	if post.Int("author") != 2 || post.Raw("content") != "Body" || post.Raw("excerpt") != "Summary" {
+		t.Errorf("Unexpected post: %v", post)
+	}
+	if post.String("date") != "2016-01-02T03:04:05" || post.String("date_gmt") != "2016-01-02T03:04:05" {
+		t.Errorf("Unset GMT date should default to the date, got %v", post.String("date_gmt"))
+	}
+	if post["sticky"] != true || post.String("status") != "draft" {
+		t.Errorf("Expected a sticky draft, got %v", post)
+	}
+	if categories := post.Ints("categories"); len(categories) != 1 || categories[0] != 3 {
+		t.Errorf("Expected post in category 3, got %v", categories)
+	}
+	if tags := post.Ints("tags"); len(tags) != 2 || tags[0] != 4 {
+		t.Errorf("Expected post with two tags, got %v", tags)
+	}
+
+	child, _ := store.Term(3)
+	if child.Int("parent") != 2 {
+		t.Errorf("Expected category go to be a child of programming, got %v", child)
+	}
+	if meta := store.MetaOf("posts", 10); len(meta) != 1 || meta[0].Value != "5" {
+		t.Errorf("Expected post meta, got %v", meta)
+	}
+	if comment, ok := store.Comment(7); !ok || comment.String("status") != wptest.CommentStatusSpam {
+		t.Errorf("Expected a spam comment, got %v", comment)
+	}
+	if _, ok := store.Post(11); ok {
+		t.Errorf("Menu items should not be loaded")
+	}
+
+	if err := store.LoadWXR(strings.NewReader(testWXR)); err == nil {
+		t.Errorf("Loading the same posts twice should return error")
+	}
+}